@@ -0,0 +1,227 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+)
+
+// AuditBackend is the backend of the audit middleware, recording the entries
+// of the audit log.
+type AuditBackend interface {
+	LogAudit(audit.Entry)
+	ContainerID(name string) (string, error)
+	ContainerExecInspect(id string) (*backend.ExecInspect, error)
+	SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{})
+	UnsubscribeFromEvents(chan interface{})
+}
+
+var (
+	versionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+	// auditedReads are the GET requests recorded in the audit log, as they
+	// give access to the processes or the files of a container.
+	auditedReads = regexp.MustCompile(`^/containers/[^/]+/(attach/ws|export|archive)$`)
+
+	// sessions are the requests attaching to a container or an exec process,
+	// whose start is recorded in the audit log before they are handled.
+	sessions = regexp.MustCompile(`^(/containers/[^/]+/attach(/ws)?|/exec/[^/]+/start)$`)
+)
+
+// AuditMiddleware is a middleware recording the API requests changing the
+// state of the daemon, or accessing the processes and files of containers, in
+// the audit log, with the identity of the caller. The start of the attach and
+// exec sessions, and the exit of the exec processes started through the API
+// are recorded too.
+type AuditMiddleware struct {
+	backend AuditBackend
+
+	mu    sync.Mutex
+	execs map[string]audit.Entry // the exec processes started, by ID
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware.
+func NewAuditMiddleware(b AuditBackend) *AuditMiddleware {
+	m := &AuditMiddleware{
+		backend: b,
+		execs:   make(map[string]audit.Entry),
+	}
+	go m.watchExecs()
+	return m
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *AuditMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		path := versionPrefix.ReplaceAllString(r.URL.Path, "")
+		switch r.Method {
+		case http.MethodGet:
+			if !auditedReads.MatchString(path) {
+				return handler(ctx, w, r, vars)
+			}
+		case http.MethodHead, http.MethodOptions:
+			return handler(ctx, w, r, vars)
+		}
+
+		e := audit.Entry{
+			Time:     time.Now().UTC(),
+			Action:   audit.RequestAction,
+			Caller:   callerFromRequest(r),
+			Method:   r.Method,
+			Endpoint: r.URL.Path,
+		}
+
+		execStart := false
+		var created *createdContainerRecorder
+		switch {
+		case path == "/containers/create":
+			// The ID of the container is only known once it is created.
+			created = &createdContainerRecorder{ResponseWriter: w}
+			w = created
+		case strings.HasPrefix(path, "/containers/"):
+			e.Container = vars["name"]
+			if id, err := m.backend.ContainerID(e.Container); err == nil {
+				e.Container = id
+			}
+		case strings.HasPrefix(path, "/exec/"):
+			e.ExecID = vars["name"]
+			if ec, err := m.backend.ContainerExecInspect(e.ExecID); err == nil {
+				e.Container = ec.ContainerID
+				if ec.ProcessConfig != nil {
+					e.Command = append([]string{ec.ProcessConfig.Entrypoint}, ec.ProcessConfig.Arguments...)
+				}
+			}
+			// Watch the exit of the exec process before it is started.
+			if execStart = strings.HasSuffix(path, "/start"); execStart {
+				m.mu.Lock()
+				m.execs[e.ExecID] = e
+				m.mu.Unlock()
+			}
+		}
+
+		// Hijacked sessions may last until the daemon stops, so their start
+		// is recorded too.
+		if sessions.MatchString(path) {
+			started := e
+			started.Action = audit.StartedAction
+			m.backend.LogAudit(started)
+		}
+
+		err := handler(ctx, w, r, vars)
+		if created != nil {
+			e.Container = created.containerID()
+		}
+		if err != nil {
+			e.StatusCode = errdefs.GetHTTPErrorStatusCode(err)
+			e.Error = err.Error()
+			if execStart {
+				m.mu.Lock()
+				delete(m.execs, e.ExecID)
+				m.mu.Unlock()
+			}
+		}
+		m.backend.LogAudit(e)
+		return err
+	}
+}
+
+// createdContainerRecorder records the response of a request creating a
+// container.
+type createdContainerRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *createdContainerRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// containerID returns the ID of the container created, or an empty string
+// if the request failed.
+func (w *createdContainerRecorder) containerID() string {
+	var resp container.ContainerCreateCreatedBody
+	if err := json.Unmarshal(w.body.Bytes(), &resp); err != nil {
+		return ""
+	}
+	return resp.ID
+}
+
+// watchExecs records the exit of the exec processes started through the API.
+func (m *AuditMiddleware) watchExecs() {
+	ef := filters.NewArgs(
+		filters.Arg("type", events.ContainerEventType),
+		filters.Arg("event", "exec_die"),
+	)
+	_, l := m.backend.SubscribeToEvents(time.Time{}, time.Time{}, ef)
+	defer m.backend.UnsubscribeFromEvents(l)
+
+	for ev := range l {
+		msg, ok := ev.(events.Message)
+		if !ok {
+			continue
+		}
+		id := msg.Actor.Attributes["execID"]
+		m.mu.Lock()
+		e, ok := m.execs[id]
+		delete(m.execs, id)
+		m.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		e.Time = time.Unix(0, msg.TimeNano).UTC()
+		e.Action = audit.ExecDieAction
+		e.Method, e.Endpoint = "", ""
+		if exitCode, err := strconv.Atoi(msg.Actor.Attributes["exitCode"]); err == nil {
+			e.ExitCode = &exitCode
+		}
+		m.backend.LogAudit(e)
+	}
+}
+
+// callerFromRequest returns the identity of the caller of the API, from its
+// TLS client certificate, or its credentials on the unix socket.
+func callerFromRequest(r *http.Request) audit.Caller {
+	var c audit.Caller
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		c.CommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	var pid, uid, gid int
+	if _, err := fmt.Sscanf(r.RemoteAddr, peerCredFormat, &pid, &uid, &gid); err == nil {
+		c.PID, c.UID, c.GID = &pid, &uid, &gid
+	} else if r.RemoteAddr != "" && r.RemoteAddr != "@" {
+		c.RemoteAddr = r.RemoteAddr
+	}
+	return c
+}
+
+// peerCredFormat is the format of the remote address of the connections on
+// the unix socket, carrying the credentials of the peer process.
+const peerCredFormat = "pid=%d,uid=%d,gid=%d"
+
+type peerCredAddr struct {
+	pid, uid, gid int
+}
+
+func (a peerCredAddr) Network() string {
+	return "unix"
+}
+
+func (a peerCredAddr) String() string {
+	return fmt.Sprintf(peerCredFormat, a.pid, a.uid, a.gid)
+}
@@ -0,0 +1,66 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"net"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// NewAuditListener wraps a unix socket listener so that the credentials of
+// the peer processes are recorded in the audit log. Other listeners are
+// returned as is.
+func NewAuditListener(l net.Listener) net.Listener {
+	if _, ok := l.(*net.UnixListener); !ok {
+		return l
+	}
+	return &peerCredListener{l}
+}
+
+type peerCredListener struct {
+	net.Listener
+}
+
+func (l *peerCredListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return c, err
+	}
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return c, nil
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		logrus.WithError(err).Debug("Error getting the credentials of the peer process")
+		return c, nil
+	}
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		if err == nil {
+			err = credErr
+		}
+		logrus.WithError(err).Debug("Error getting the credentials of the peer process")
+		return c, nil
+	}
+	return &peerCredConn{
+		UnixConn: uc,
+		addr:     peerCredAddr{pid: int(cred.Pid), uid: int(cred.Uid), gid: int(cred.Gid)},
+	}, nil
+}
+
+// peerCredConn is a connection on the unix socket whose remote address
+// carries the credentials of the peer process.
+type peerCredConn struct {
+	*net.UnixConn
+	addr peerCredAddr
+}
+
+func (c *peerCredConn) RemoteAddr() net.Addr {
+	return c.addr
+}
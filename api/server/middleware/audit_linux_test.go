@@ -0,0 +1,39 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestAuditListener(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-listener")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "docker.sock"))
+	assert.NilError(t, err)
+	l = NewAuditListener(l)
+	defer l.Close()
+
+	go func() {
+		c, err := net.Dial("unix", filepath.Join(dir, "docker.sock"))
+		if err == nil {
+			defer c.Close()
+			c.Read(make([]byte, 1))
+		}
+	}()
+
+	c, err := l.Accept()
+	assert.NilError(t, err)
+	defer c.Close()
+
+	_, ok := c.(interface{ CloseWrite() error })
+	assert.Check(t, ok, "connection does not support CloseWrite")
+	assert.Check(t, is.Equal(c.RemoteAddr(), net.Addr(peerCredAddr{pid: os.Getpid(), uid: os.Getuid(), gid: os.Getgid()})))
+}
@@ -0,0 +1,204 @@
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+)
+
+type fakeAuditBackend struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+	events  chan interface{}
+}
+
+func (b *fakeAuditBackend) LogAudit(e audit.Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, e)
+}
+
+func (b *fakeAuditBackend) Entries() []audit.Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]audit.Entry(nil), b.entries...)
+}
+
+func (b *fakeAuditBackend) ContainerID(name string) (string, error) {
+	if name == "web" {
+		return "web-id", nil
+	}
+	return "", errdefs.NotFound(errors.New("no such container"))
+}
+
+func (b *fakeAuditBackend) ContainerExecInspect(id string) (*backend.ExecInspect, error) {
+	return &backend.ExecInspect{
+		ID:            id,
+		ContainerID:   "container-id",
+		ProcessConfig: &backend.ExecProcessConfig{Entrypoint: "sh", Arguments: []string{"-c", "exit 3"}},
+	}, nil
+}
+
+func (b *fakeAuditBackend) SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{}) {
+	return nil, b.events
+}
+
+func (b *fakeAuditBackend) UnsubscribeFromEvents(chan interface{}) {}
+
+func TestAuditMiddleware(t *testing.T) {
+	b := &fakeAuditBackend{events: make(chan interface{})}
+	defer close(b.events)
+	m := NewAuditMiddleware(b)
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if vars["name"] == "missing" {
+			return errdefs.NotFound(errors.New("no such container"))
+		}
+		return nil
+	}
+	h := m.WrapHandler(handler)
+
+	do := func(method, path, name string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "pid=42,uid=1000,gid=100"
+		h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": name})
+	}
+	do(http.MethodGet, "/v1.41/containers/web/json", "web")
+	do(http.MethodPost, "/v1.41/containers/missing/stop", "missing")
+	do(http.MethodPost, "/v1.41/containers/web/stop", "web")
+	do(http.MethodPost, "/exec/exec-id/start", "exec-id")
+	b.events <- events.Message{
+		TimeNano: time.Now().UnixNano(),
+		Actor:    events.Actor{ID: "container-id", Attributes: map[string]string{"execID": "exec-id", "exitCode": "3"}},
+	}
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if len(b.Entries()) < 5 {
+			return poll.Continue("waiting for the exec entry")
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(5*time.Second))
+
+	uid, gid, pid := 1000, 100, 42
+	caller := audit.Caller{UID: &uid, GID: &gid, PID: &pid}
+	entries := b.Entries()
+	assert.Assert(t, is.Len(entries, 5))
+
+	assert.Check(t, is.Equal(entries[0].Action, audit.RequestAction))
+	assert.Check(t, is.DeepEqual(entries[0].Caller, caller))
+	assert.Check(t, is.Equal(entries[0].Endpoint, "/v1.41/containers/missing/stop"))
+	assert.Check(t, is.Equal(entries[0].Container, "missing"))
+	assert.Check(t, is.Equal(entries[0].StatusCode, http.StatusNotFound))
+	assert.Check(t, is.Equal(entries[0].Error, "no such container"))
+
+	assert.Check(t, is.Equal(entries[1].Action, audit.RequestAction))
+	assert.Check(t, is.Equal(entries[1].Container, "web-id"))
+	assert.Check(t, is.Equal(entries[1].StatusCode, 0))
+
+	assert.Check(t, is.Equal(entries[2].Action, audit.StartedAction))
+	assert.Check(t, is.Equal(entries[2].ExecID, "exec-id"))
+	assert.Check(t, is.Equal(entries[2].Container, "container-id"))
+
+	assert.Check(t, is.Equal(entries[3].Action, audit.RequestAction))
+	assert.Check(t, is.Equal(entries[3].ExecID, "exec-id"))
+	assert.Check(t, is.Equal(entries[3].Container, "container-id"))
+	assert.Check(t, is.DeepEqual(entries[3].Command, []string{"sh", "-c", "exit 3"}))
+	assert.Check(t, is.Nil(entries[3].ExitCode))
+
+	assert.Check(t, is.Equal(entries[4].Action, audit.ExecDieAction))
+	assert.Check(t, is.DeepEqual(entries[4].Caller, caller))
+	assert.Check(t, is.Equal(entries[4].ExecID, "exec-id"))
+	assert.Check(t, is.DeepEqual(entries[4].Command, []string{"sh", "-c", "exit 3"}))
+	assert.Assert(t, entries[4].ExitCode != nil)
+	assert.Check(t, is.Equal(*entries[4].ExitCode, 3))
+}
+
+func TestAuditMiddlewareContainerAccess(t *testing.T) {
+	b := &fakeAuditBackend{events: make(chan interface{})}
+	defer close(b.events)
+	m := NewAuditMiddleware(b)
+
+	// The handler checks that the session was recorded before it is handled.
+	var started int
+	h := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if entries := b.Entries(); len(entries) > 0 && entries[len(entries)-1].Action == audit.StartedAction {
+			started++
+		}
+		return nil
+	})
+
+	for _, tc := range []struct {
+		method, path string
+		recorded     bool
+	}{
+		{method: http.MethodGet, path: "/v1.41/containers/web/attach/ws", recorded: true},
+		{method: http.MethodGet, path: "/v1.41/containers/web/export", recorded: true},
+		{method: http.MethodGet, path: "/v1.41/containers/web/archive", recorded: true},
+		{method: http.MethodHead, path: "/v1.41/containers/web/archive"},
+		{method: http.MethodGet, path: "/v1.41/containers/web/logs"},
+		{method: http.MethodPost, path: "/v1.41/containers/web/attach", recorded: true},
+	} {
+		before := len(b.Entries())
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		assert.NilError(t, h(context.Background(), httptest.NewRecorder(), req, map[string]string{"name": "web"}))
+		entries := b.Entries()[before:]
+		if !tc.recorded {
+			assert.Check(t, is.Len(entries, 0), "%s %s", tc.method, tc.path)
+			continue
+		}
+		last := entries[len(entries)-1]
+		assert.Check(t, is.Equal(last.Action, audit.RequestAction), "%s %s", tc.method, tc.path)
+		assert.Check(t, is.Equal(last.Container, "web-id"), "%s %s", tc.method, tc.path)
+	}
+	assert.Check(t, is.Equal(started, 2))
+}
+
+func TestAuditMiddlewareContainerCreate(t *testing.T) {
+	b := &fakeAuditBackend{events: make(chan interface{})}
+	defer close(b.events)
+	m := NewAuditMiddleware(b)
+
+	h := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return httputils.WriteJSON(w, http.StatusCreated, container.ContainerCreateCreatedBody{ID: "created-id", Warnings: []string{}})
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1.41/containers/create?name=web", nil)
+	rec := httptest.NewRecorder()
+	assert.NilError(t, h(context.Background(), rec, req, map[string]string{}))
+	assert.Check(t, is.Equal(rec.Code, http.StatusCreated))
+
+	entries := b.Entries()
+	assert.Assert(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Action, audit.RequestAction))
+	assert.Check(t, is.Equal(entries[0].Container, "created-id"))
+}
+
+func TestAuditCallerFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/containers/create", nil)
+	req.RemoteAddr = "192.0.2.1:4242"
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}},
+	}
+	assert.Check(t, is.DeepEqual(callerFromRequest(req), audit.Caller{CommonName: "alice", RemoteAddr: "192.0.2.1:4242"}))
+
+	req = httptest.NewRequest(http.MethodPost, "/containers/create", nil)
+	req.RemoteAddr = "@"
+	assert.Check(t, is.DeepEqual(callerFromRequest(req), audit.Caller{}))
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package middleware // import "github.com/docker/docker/api/server/middleware"
+
+import "net"
+
+// NewAuditListener returns the listener as is, as the credentials of the
+// peer processes are not supported on this platform.
+func NewAuditListener(l net.Listener) net.Listener {
+	return l
+}
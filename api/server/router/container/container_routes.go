@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/docker/docker/api/server/httputils"
@@ -14,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/versions"
 	containerpkg "github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
@@ -54,6 +56,10 @@ func (s *containerRouter) postCommit(ctx context.Context, w http.ResponseWriter,
 		Config:  config,
 		Changes: r.Form["changes"],
 	}
+	if versions.GreaterThanOrEqualTo(version, "1.41") {
+		commitCfg.Snapshot = httputils.BoolValue(r, "snapshot")
+		commitCfg.Incremental = httputils.BoolValue(r, "incremental")
+	}
 
 	imgID, err := s.backend.CreateImageFromContainer(r.Form.Get("container"), commitCfg)
 	if err != nil {
@@ -397,6 +403,25 @@ func (s *containerRouter) getContainersTop(ctx context.Context, w http.ResponseW
 	return httputils.WriteJSON(w, http.StatusOK, procList)
 }
 
+func (s *containerRouter) getContainersProcesses(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	options := types.ContainerProcessesOptions{
+		Tree: httputils.BoolValue(r, "tree"),
+	}
+	if fields := r.Form.Get("fields"); fields != "" {
+		options.Fields = strings.Split(fields, ",")
+	}
+	processes, err := s.backend.ContainerProcesses(vars["name"], options)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, processes)
+}
+
 func (s *containerRouter) postContainerRename(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -425,9 +450,21 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	if err := decoder.Decode(&updateConfig); err != nil {
 		return err
 	}
-	if versions.LessThan(httputils.VersionFromContext(ctx), "1.40") {
+	version := httputils.VersionFromContext(ctx)
+	if versions.LessThan(version, "1.40") {
 		updateConfig.PidsLimit = nil
 	}
+	if versions.LessThan(version, "1.41") {
+		// Older API versions ignored these fields when updating a container.
+		updateConfig.Devices = nil
+		updateConfig.Labels = nil
+		updateConfig.Healthcheck = nil
+		updateConfig.LogConfig = nil
+		updateConfig.PortBindings = nil
+		updateConfig.NetworkIngressRate = 0
+		updateConfig.NetworkEgressRate = 0
+		updateConfig.NetworkConntrackMax = 0
+	}
 	if updateConfig.PidsLimit != nil && *updateConfig.PidsLimit <= 0 {
 		// Both `0` and `-1` are accepted to set "unlimited" when updating.
 		// Historically, any negative value was accepted, so treat them as
@@ -436,13 +473,21 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 		updateConfig.PidsLimit = &unlimited
 	}
 
+	config := &container.Config{
+		Labels:      updateConfig.Labels,
+		Healthcheck: updateConfig.Healthcheck,
+	}
 	hostConfig := &container.HostConfig{
 		Resources:     updateConfig.Resources,
 		RestartPolicy: updateConfig.RestartPolicy,
+		PortBindings:  updateConfig.PortBindings,
+	}
+	if updateConfig.LogConfig != nil {
+		hostConfig.LogConfig = *updateConfig.LogConfig
 	}
 
 	name := vars["name"]
-	resp, err := s.backend.ContainerUpdate(name, hostConfig)
+	resp, err := s.backend.ContainerUpdate(name, config, hostConfig)
 	if err != nil {
 		return err
 	}
@@ -490,11 +535,34 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 			hostConfig.IpcMode = container.IpcMode("shareable")
 		}
 	}
+	if config != nil && versions.LessThan(version, "1.41") {
+		// Ignore StopSequence and PreStop because they were added in API 1.41.
+		config.StopSequence = nil
+		config.PreStop = nil
+	}
 	if hostConfig != nil && versions.LessThan(version, "1.41") {
+		// Ignore VolumeOptions.Subpath because it was added in API 1.41.
+		for _, m := range hostConfig.Mounts {
+			if vo := m.VolumeOptions; vo != nil {
+				vo.Subpath = ""
+			}
+			if m.Type == mount.TypeImage {
+				return errdefs.InvalidParameter(errors.Errorf("mount type %q requires API version 1.41 or later", m.Type))
+			}
+		}
 		// Older clients expect the default to be "host"
 		if hostConfig.CgroupnsMode.IsEmpty() {
 			hostConfig.CgroupnsMode = container.CgroupnsMode("host")
 		}
+		if hostConfig.UTSMode.IsContainer() {
+			return errdefs.InvalidParameter(errors.Errorf("UTS mode %q requires API version 1.41 or later", hostConfig.UTSMode))
+		}
+		// Ignore DependsOn, the network limits, and EgressPolicy because they were added in API 1.41.
+		hostConfig.DependsOn = nil
+		hostConfig.EgressPolicy = nil
+		hostConfig.NetworkIngressRate = 0
+		hostConfig.NetworkEgressRate = 0
+		hostConfig.NetworkConntrackMax = 0
 	}
 
 	if hostConfig != nil && hostConfig.PidsLimit != nil && *hostConfig.PidsLimit <= 0 {
@@ -519,6 +587,30 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 	return httputils.WriteJSON(w, http.StatusCreated, ccr)
 }
 
+func (s *containerRouter) postContainersDebug(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var config types.ContainerDebugConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+
+	ccr, err := s.backend.ContainerDebug(vars["name"], &config)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, ccr)
+}
+
 func (s *containerRouter) deleteContainers(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/errdefs"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
@@ -130,13 +132,8 @@ func (s *distributionRouter) getDistributionInfo(ctx context.Context, w http.Res
 			})
 		}
 	case *schema2.DeserializedManifest:
-		configJSON, err := blobsrvc.Get(ctx, mnfstObj.Config.Digest)
-		var platform v1.Platform
-		if err == nil {
-			err := json.Unmarshal(configJSON, &platform)
-			if err == nil && (platform.OS != "" || platform.Architecture != "") {
-				distributionInspect.Platforms = append(distributionInspect.Platforms, platform)
-			}
+		if platform, ok := configPlatform(ctx, blobsrvc, mnfstObj.Config.Digest); ok {
+			distributionInspect.Platforms = append(distributionInspect.Platforms, platform)
 		}
 	case *schema1.SignedManifest:
 		platform := v1.Platform{
@@ -144,7 +141,32 @@ func (s *distributionRouter) getDistributionInfo(ctx context.Context, w http.Res
 			OS:           "linux",
 		}
 		distributionInspect.Platforms = append(distributionInspect.Platforms, platform)
+	default:
+		// OCI image manifests are deserialized by the distribution package of
+		// the daemon, with the same format as schema2 manifests.
+		if mediaType == v1.MediaTypeImageManifest {
+			var ociManifest v1.Manifest
+			if err := json.Unmarshal(payload, &ociManifest); err == nil {
+				if platform, ok := configPlatform(ctx, blobsrvc, ociManifest.Config.Digest); ok {
+					distributionInspect.Platforms = append(distributionInspect.Platforms, platform)
+				}
+			}
+		}
 	}
 
 	return httputils.WriteJSON(w, http.StatusOK, distributionInspect)
 }
+
+// configPlatform returns the platform of the image configuration with the
+// given digest, if it can be retrieved and sets the OS or the architecture.
+func configPlatform(ctx context.Context, blobsrvc distribution.BlobStore, dgst digest.Digest) (v1.Platform, bool) {
+	var platform v1.Platform
+	configJSON, err := blobsrvc.Get(ctx, dgst)
+	if err != nil {
+		return platform, false
+	}
+	if err := json.Unmarshal(configJSON, &platform); err != nil {
+		return platform, false
+	}
+	return platform, platform.OS != "" || platform.Architecture != ""
+}
@@ -29,16 +29,21 @@ func (r *imageRouter) initRoutes() {
 		router.NewGetRoute("/images/json", r.getImagesJSON),
 		router.NewGetRoute("/images/search", r.getImagesSearch),
 		router.NewGetRoute("/images/get", r.getImagesGet),
+		router.NewGetRoute("/images/layers", r.getImagesLayers),
 		router.NewGetRoute("/images/{name:.*}/get", r.getImagesGet),
 		router.NewGetRoute("/images/{name:.*}/history", r.getImagesHistory),
 		router.NewGetRoute("/images/{name:.*}/json", r.getImagesByName),
+		router.NewGetRoute("/transfers", r.getTransfers),
 		// POST
+		router.NewPostRoute("/images/get", r.postImagesGet),
 		router.NewPostRoute("/images/load", r.postImagesLoad),
 		router.NewPostRoute("/images/create", r.postImagesCreate),
 		router.NewPostRoute("/images/{name:.*}/push", r.postImagesPush),
 		router.NewPostRoute("/images/{name:.*}/tag", r.postImagesTag),
 		router.NewPostRoute("/images/prune", r.postImagesPrune),
+		router.NewPostRoute("/transfers/{id:.*}/attach", r.postTransfersAttach),
 		// DELETE
 		router.NewDeleteRoute("/images/{name:.*}", r.deleteImages),
+		router.NewDeleteRoute("/transfers/{id:.*}", r.deleteTransfers),
 	}
 }
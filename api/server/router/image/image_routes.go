@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,10 +15,12 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
@@ -166,6 +169,50 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 	return nil
 }
 
+func (s *imageRouter) postImagesGet(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var req types.ImageSaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+	excludeLayers := make([]layer.ChainID, 0, len(req.ExcludeLayers))
+	for _, l := range req.ExcludeLayers {
+		chainID, err := digest.Parse(l)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid layer %q", l))
+		}
+		excludeLayers = append(excludeLayers, layer.ChainID(chainID))
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+
+	output := ioutils.NewWriteFlusher(w)
+	defer output.Close()
+
+	if err := s.backend.ExportImageExcludingLayers(req.Names, excludeLayers, output); err != nil {
+		if !output.Flushed() {
+			return err
+		}
+		output.Write(streamformatter.FormatError(err))
+	}
+	return nil
+}
+
+func (s *imageRouter) getImagesLayers(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	chainIDs := s.backend.LayerChainIDs()
+	layers := make([]string, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		layers = append(layers, chainID.String())
+	}
+	return httputils.WriteJSON(w, http.StatusOK, layers)
+}
+
 func (s *imageRouter) postImagesLoad(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -320,3 +367,30 @@ func (s *imageRouter) postImagesPrune(ctx context.Context, w http.ResponseWriter
 	}
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
+
+func (s *imageRouter) getTransfers(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return httputils.WriteJSON(w, http.StatusOK, s.backend.Transfers())
+}
+
+func (s *imageRouter) postTransfersAttach(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	output := ioutils.NewWriteFlusher(w)
+	defer output.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := s.backend.TransferAttach(ctx, vars["id"], output); err != nil {
+		if !output.Flushed() {
+			return err
+		}
+		output.Write(streamformatter.FormatError(err))
+	}
+	return nil
+}
+
+func (s *imageRouter) deleteTransfers(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := s.backend.TransferCancel(vars["id"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -32,6 +32,7 @@ func NewRouter(b Backend, c ClusterBackend, fscache *fscache.FSCache, builder *b
 		router.NewGetRoute("/_ping", r.pingHandler),
 		router.NewHeadRoute("/_ping", r.pingHandler),
 		router.NewGetRoute("/events", r.getEvents),
+		router.NewGetRoute("/audit", r.getAuditLog),
 		router.NewGetRoute("/info", r.getInfo),
 		router.NewGetRoute("/version", r.getVersion),
 		router.NewGetRoute("/system/df", r.getDiskUsage),
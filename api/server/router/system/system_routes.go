@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/router/build"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/audit"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/registry"
@@ -242,6 +243,38 @@ func (s *systemRouter) postAuth(ctx context.Context, w http.ResponseWriter, r *h
 	})
 }
 
+func (s *systemRouter) getAuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	since, err := eventTime(r.Form.Get("since"))
+	if err != nil {
+		return err
+	}
+	until, err := eventTime(r.Form.Get("until"))
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() && until.Before(since) {
+		return invalidRequestError{fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", r.Form.Get("since"), r.Form.Get("until"))}
+	}
+
+	ef, err := filters.FromJSON(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.backend.AuditLog(since, until, ef)
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
+
 func eventTime(formTime string) (time.Time, error) {
 	t, tNano, err := timetypes.ParseTimestamps(formTime, -1)
 	if err != nil {
@@ -2,6 +2,7 @@ package volume // import "github.com/docker/docker/api/server/router/volume"
 
 import (
 	"context"
+	"io"
 
 	"github.com/docker/docker/volume/service/opts"
 	// TODO return types need to be refactored into pkg
@@ -17,4 +18,17 @@ type Backend interface {
 	Create(ctx context.Context, name, driverName string, opts ...opts.CreateOption) (*types.Volume, error)
 	Remove(ctx context.Context, name string, opts ...opts.RemoveOption) error
 	Prune(ctx context.Context, pruneFilters filters.Args) (*types.VolumesPruneReport, error)
+	Snapshot(ctx context.Context, name, snapshot string) (*types.VolumeSnapshot, error)
+	ListSnapshots(ctx context.Context, name string) ([]*types.VolumeSnapshot, error)
+	RemoveSnapshot(ctx context.Context, name, snapshot string) error
+	Clone(ctx context.Context, name, snapshot, target string, opts ...opts.CreateOption) (*types.Volume, error)
+	Restore(ctx context.Context, name, snapshot string) error
+	Resize(ctx context.Context, name string, size int64) error
+}
+
+// ArchiveBackend is the methods that need to be implemented to copy files
+// from and to volumes
+type ArchiveBackend interface {
+	VolumeArchivePath(ctx context.Context, name, path string) (io.ReadCloser, error)
+	VolumeExtractToDir(ctx context.Context, name, path, driverName string, noOverwriteDirNonDir bool, content io.Reader) error
 }
@@ -4,14 +4,16 @@ import "github.com/docker/docker/api/server/router"
 
 // volumeRouter is a router to talk with the volumes controller
 type volumeRouter struct {
-	backend Backend
-	routes  []router.Route
+	backend        Backend
+	archiveBackend ArchiveBackend
+	routes         []router.Route
 }
 
 // NewRouter initializes a new volume router
-func NewRouter(b Backend) router.Router {
+func NewRouter(b Backend, ab ArchiveBackend) router.Router {
 	r := &volumeRouter{
-		backend: b,
+		backend:        b,
+		archiveBackend: ab,
 	}
 	r.initRoutes()
 	return r
@@ -26,11 +28,20 @@ func (r *volumeRouter) initRoutes() {
 	r.routes = []router.Route{
 		// GET
 		router.NewGetRoute("/volumes", r.getVolumesList),
+		router.NewGetRoute("/volumes/{name:.*}/snapshots", r.getVolumeSnapshots),
+		router.NewGetRoute("/volumes/{name:.*}/archive", r.getVolumeArchive),
 		router.NewGetRoute("/volumes/{name:.*}", r.getVolumeByName),
 		// POST
 		router.NewPostRoute("/volumes/create", r.postVolumesCreate),
 		router.NewPostRoute("/volumes/prune", r.postVolumesPrune),
+		router.NewPostRoute("/volumes/{name:.*}/snapshots", r.postVolumeSnapshots),
+		router.NewPostRoute("/volumes/{name:.*}/clone", r.postVolumeClone),
+		router.NewPostRoute("/volumes/{name:.*}/restore", r.postVolumeRestore),
+		router.NewPostRoute("/volumes/{name:.*}/resize", r.postVolumeResize),
+		// PUT
+		router.NewPutRoute("/volumes/{name:.*}/archive", r.putVolumeArchive),
 		// DELETE
+		router.NewDeleteRoute("/volumes/{name:.*}/snapshots/{snapshot}", r.deleteVolumeSnapshot),
 		router.NewDeleteRoute("/volumes/{name:.*}", r.deleteVolumes),
 	}
 }
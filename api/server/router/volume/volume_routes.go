@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
@@ -94,3 +95,109 @@ func (v *volumeRouter) postVolumesPrune(ctx context.Context, w http.ResponseWrit
 	}
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
+
+func (v *volumeRouter) getVolumeSnapshots(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	snapshots, err := v.backend.ListSnapshots(ctx, vars["name"])
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, snapshots)
+}
+
+func (v *volumeRouter) postVolumeSnapshots(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req types.VolumeSnapshotCreateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		return err
+	}
+
+	snapshot, err := v.backend.Snapshot(ctx, vars["name"], req.Name)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, snapshot)
+}
+
+func (v *volumeRouter) deleteVolumeSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := v.backend.RemoveSnapshot(ctx, vars["name"], vars["snapshot"]); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (v *volumeRouter) postVolumeClone(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req types.VolumeCloneRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		return err
+	}
+
+	volume, err := v.backend.Clone(ctx, vars["name"], req.Snapshot, req.Name, opts.WithCreateOptions(req.DriverOpts), opts.WithCreateLabels(req.Labels))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+func (v *volumeRouter) postVolumeRestore(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req types.VolumeRestoreRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		return err
+	}
+
+	if err := v.backend.Restore(ctx, vars["name"], req.Snapshot); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (v *volumeRouter) postVolumeResize(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	var req types.VolumeResizeRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		return err
+	}
+
+	if err := v.backend.Resize(ctx, vars["name"], req.Size); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+	return nil
+}
+
+func (v *volumeRouter) getVolumeArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	tarArchive, err := v.archiveBackend.VolumeArchivePath(ctx, vars["name"], r.Form.Get("path"))
+	if err != nil {
+		return err
+	}
+	defer tarArchive.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	_, err = io.Copy(w, tarArchive)
+	return err
+}
+
+func (v *volumeRouter) putVolumeArchive(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	noOverwriteDirNonDir := httputils.BoolValue(r, "noOverwriteDirNonDir")
+	return v.archiveBackend.VolumeExtractToDir(ctx, vars["name"], r.Form.Get("path"), r.Form.Get("driver"), noOverwriteDirNonDir, r.Body)
+}
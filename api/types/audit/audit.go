@@ -0,0 +1,45 @@
+package audit // import "github.com/docker/docker/api/types/audit"
+
+import "time"
+
+const (
+	// RequestAction is the action of the entries recording an API request.
+	RequestAction = "request"
+	// StartedAction is the action of the entries recording the start of an
+	// attach or exec session, before the request is handled.
+	StartedAction = "started"
+	// ExecDieAction is the action of the entries recording the exit of an
+	// exec process started through the API.
+	ExecDieAction = "exec_die"
+)
+
+// Caller identifies the client calling the API. CommonName is the common
+// name of the TLS client certificate, UID, GID and PID are the credentials
+// of the peer process on the unix socket.
+type Caller struct {
+	CommonName string `json:",omitempty"`
+	UID        *int   `json:",omitempty"`
+	GID        *int   `json:",omitempty"`
+	PID        *int   `json:",omitempty"`
+	RemoteAddr string `json:",omitempty"`
+}
+
+// Entry is an entry of the audit log.
+type Entry struct {
+	Time     time.Time
+	Action   string
+	Caller   Caller
+	Method   string `json:",omitempty"`
+	Endpoint string `json:",omitempty"`
+
+	// StatusCode and Error are set if the request failed.
+	StatusCode int    `json:",omitempty"`
+	Error      string `json:",omitempty"`
+
+	// Container is the ID of the container of the request, or its name as
+	// given by the caller if it does not exist.
+	Container string   `json:",omitempty"`
+	ExecID    string   `json:",omitempty"`
+	Command   []string `json:",omitempty"`
+	ExitCode  *int     `json:",omitempty"`
+}
@@ -91,6 +91,7 @@ type ExecInspect struct {
 	ContainerID   string
 	DetachKeys    []byte
 	Pid           int
+	Detachable    bool
 }
 
 // ExecProcessConfig holds information about the exec process
@@ -106,13 +107,15 @@ type ExecProcessConfig struct {
 // CreateImageConfig is the configuration for creating an image from a
 // container.
 type CreateImageConfig struct {
-	Repo    string
-	Tag     string
-	Pause   bool
-	Author  string
-	Comment string
-	Config  *container.Config
-	Changes []string
+	Repo        string
+	Tag         string
+	Pause       bool
+	Snapshot    bool
+	Incremental bool
+	Author      string
+	Comment     string
+	Config      *container.Config
+	Changes     []string
 }
 
 // CommitConfig is the configuration for creating an image as part of a build.
@@ -125,4 +128,10 @@ type CommitConfig struct {
 	ContainerMountLabel string
 	ContainerOS         string
 	ParentImageID       string
+	// Snapshot is the ID of the snapshot of the RW layer of the container
+	// to create the image from, instead of the RW layer itself. If
+	// SnapshotSince is set, only the changes since that snapshot are added
+	// to the parent image.
+	Snapshot      string
+	SnapshotSince string
 }
@@ -46,6 +46,14 @@ type ContainerCommitOptions struct {
 	Changes   []string
 	Pause     bool
 	Config    *container.Config
+
+	// Snapshot commits the container from a snapshot of its filesystem,
+	// without pausing it. Pause is ignored if set.
+	Snapshot bool
+	// Incremental creates the image on top of the image of the last
+	// snapshot commit of the container, adding only the changes since then.
+	// It requires Snapshot to be set.
+	Incremental bool
 }
 
 // ContainerExecInspect holds information returned by exec inspect.
@@ -55,6 +63,7 @@ type ContainerExecInspect struct {
 	Running     bool
 	ExitCode    int
 	Pid         int
+	Detachable  bool
 }
 
 // ContainerListOptions holds parameters to list containers with.
@@ -81,6 +90,16 @@ type ContainerLogsOptions struct {
 	Details    bool
 }
 
+// ContainerProcessesOptions holds parameters to list the processes of a
+// container.
+type ContainerProcessesOptions struct {
+	// Fields are the fields of the processes to return. All fields are
+	// returned if empty.
+	Fields []string
+	// Tree lists the processes as a tree, each process holding its children.
+	Tree bool
+}
+
 // ContainerRemoveOptions holds parameters to remove containers.
 type ContainerRemoveOptions struct {
 	RemoveVolumes bool
@@ -101,6 +120,15 @@ type CopyToContainerOptions struct {
 	CopyUIDGID                bool
 }
 
+// CopyToVolumeOptions holds information
+// about files to copy into a volume
+type CopyToVolumeOptions struct {
+	AllowOverwriteDirWithFile bool
+	// Driver is the volume driver used to create the volume if it does not
+	// exist yet
+	Driver string
+}
+
 // EventsOptions holds parameters to filter events with.
 type EventsOptions struct {
 	Since   string
@@ -108,6 +136,13 @@ type EventsOptions struct {
 	Filters filters.Args
 }
 
+// AuditLogOptions holds parameters to filter the audit log with.
+type AuditLogOptions struct {
+	Since   string
+	Until   string
+	Filters filters.Args
+}
+
 // NetworkListOptions holds parameters to filter the list of networks with.
 type NetworkListOptions struct {
 	Filters filters.Args
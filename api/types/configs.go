@@ -39,6 +39,21 @@ type ExecConfig struct {
 	Env          []string // Environment variables
 	WorkingDir   string   // Working directory
 	Cmd          []string // Execution commands and args
+	Detachable   bool     // Keep the output of the exec, so that clients can attach to it after it was started
+}
+
+// ContainerDebugConfig holds the configuration of a debug container, which
+// joins the namespaces of a running container to debug it.
+type ContainerDebugConfig struct {
+	Image      string   // Image of the debug container, with the debugging tools
+	Cmd        []string // Command to run in the debug container
+	Env        []string // Environment variables
+	User       string   // User that will run the command
+	WorkingDir string   // Working directory
+	Tty        bool     // Allocate a pseudo-TTY
+	OpenStdin  bool     // Open stdin, makes possible user interaction
+	Privileged bool     // Is the debug container in privileged mode
+	CapAdd     []string // Capabilities to add to the debug container
 }
 
 // PluginRmConfig holds arguments for plugin remove.
@@ -34,6 +34,27 @@ type HealthConfig struct {
 	Retries int `json:",omitempty"`
 }
 
+// PreStopHook is a command run in the container before it is stopped, for
+// example to drain its connections.
+type PreStopHook struct {
+	// Cmd is the command run, as an exec process.
+	Cmd strslice.StrSlice
+
+	// User is the user the command is run as. The user of the container is
+	// used if it is empty.
+	User string `json:",omitempty"`
+
+	// Timeout is the time (in seconds) to wait for the command to exit,
+	// before it is killed and the container is stopped.
+	Timeout *int `json:",omitempty"`
+}
+
+// StopSignalStep is a step of the stop sequence of a container.
+type StopSignalStep struct {
+	Signal  string // Signal sent to the container
+	Timeout int    // Timeout (in seconds) to wait for the container to exit before the next step
+}
+
 // Config contains the configuration data about a container.
 // It should hold only portable information about the container.
 // Here, "portable" means "independent from the host we are running on".
@@ -65,5 +86,7 @@ type Config struct {
 	Labels          map[string]string   // List of labels set to this container
 	StopSignal      string              `json:",omitempty"` // Signal to stop a container
 	StopTimeout     *int                `json:",omitempty"` // Timeout (in seconds) to stop a container
+	StopSequence    []StopSignalStep    `json:",omitempty"` // Signals sent in order to stop a container, before it is killed
+	PreStop         *PreStopHook        `json:",omitempty"` // Command run in the container before it is stopped
 	Shell           strslice.StrSlice   `json:",omitempty"` // Shell for shell-form of RUN, CMD, ENTRYPOINT
 }
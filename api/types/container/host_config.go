@@ -205,7 +205,7 @@ type UTSMode string
 
 // IsPrivate indicates whether the container uses its private UTS namespace.
 func (n UTSMode) IsPrivate() bool {
-	return !(n.IsHost())
+	return !(n.IsHost() || n.IsContainer())
 }
 
 // IsHost indicates whether the container uses the host's UTS namespace.
@@ -213,11 +213,32 @@ func (n UTSMode) IsHost() bool {
 	return n == "host"
 }
 
+// IsContainer indicates whether the container uses the UTS namespace of
+// another container.
+func (n UTSMode) IsContainer() bool {
+	parts := strings.SplitN(string(n), ":", 2)
+	return len(parts) > 1 && parts[0] == "container"
+}
+
+// Container returns the name of the container whose UTS namespace is going to
+// be used.
+func (n UTSMode) Container() string {
+	parts := strings.SplitN(string(n), ":", 2)
+	if len(parts) > 1 && parts[0] == "container" {
+		return parts[1]
+	}
+	return ""
+}
+
 // Valid indicates whether the UTS namespace is valid.
 func (n UTSMode) Valid() bool {
 	parts := strings.Split(string(n), ":")
 	switch mode := parts[0]; mode {
 	case "", "host":
+	case "container":
+		if len(parts) != 2 || parts[1] == "" {
+			return false
+		}
 	default:
 		return false
 	}
@@ -320,6 +341,53 @@ func (rp *RestartPolicy) IsSame(tp *RestartPolicy) bool {
 	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount
 }
 
+// DependencyCondition is the condition a dependency of a container must meet
+// for the container to be started.
+type DependencyCondition string
+
+const (
+	// DependencyStarted requires the dependency to be running.
+	DependencyStarted DependencyCondition = "started"
+	// DependencyHealthy requires the dependency to be running and healthy.
+	DependencyHealthy DependencyCondition = "healthy"
+	// DependencyCompletedSuccessfully requires the dependency to have exited
+	// with the exit code 0.
+	DependencyCompletedSuccessfully DependencyCondition = "completed-successfully"
+)
+
+// IsValid indicates if the dependency condition is valid. An empty condition
+// is the "started" condition.
+func (c DependencyCondition) IsValid() bool {
+	switch c {
+	case "", DependencyStarted, DependencyHealthy, DependencyCompletedSuccessfully:
+		return true
+	}
+	return false
+}
+
+// Dependency is a container which must meet a condition for a container to be
+// started.
+type Dependency struct {
+	Container string              // Name or ID of the container
+	Condition DependencyCondition `json:",omitempty"` // Condition the container must meet, "started" if empty
+}
+
+// EgressPolicy restricts the destinations a container can connect to. The
+// connections to destinations which are not allowed are rejected, including
+// to the host and to the other containers of the network. Only IPv4 is
+// supported.
+type EgressPolicy struct {
+	Allow []EgressRule // Allowed destinations
+}
+
+// EgressRule is a destination a container is allowed to connect to, either a
+// network or a DNS name.
+type EgressRule struct {
+	CIDR  string   `json:",omitempty"` // IPv4 network or address
+	Host  string   `json:",omitempty"` // DNS name, resolved once by the daemon each time the container connects to a network
+	Ports []string `json:",omitempty"` // Ports or port ranges, such as "443" or "8000-8100/udp", tcp if the protocol is not set; all ports if empty
+}
+
 // LogMode is a type to define the available modes for logging
 // These modes affect how logs are handled when log messages start piling up.
 type LogMode string
@@ -369,6 +437,9 @@ type Resources struct {
 	OomKillDisable       *bool           // Whether to disable OOM Killer or not
 	PidsLimit            *int64          // Setting PIDs limit for a container; Set `0` or `-1` for unlimited, or `null` to not change.
 	Ulimits              []*units.Ulimit // List of ulimits to be set in the container
+	NetworkIngressRate   int64           // Rate limit of the data received by the container (in bytes per second); set `-1` to remove the limit on update
+	NetworkEgressRate    int64           // Rate limit of the data sent by the container (in bytes per second); set `-1` to remove the limit on update
+	NetworkConntrackMax  int64           // Maximum number of connections tracked for the container, in each direction; set `-1` to remove the limit on update
 
 	// Applicable to Windows
 	CPUCount           int64  `json:"CpuCount"`   // CPU count
@@ -383,6 +454,12 @@ type UpdateConfig struct {
 	// Contains container's resources (cgroups, ulimits)
 	Resources
 	RestartPolicy RestartPolicy
+
+	// Fields below are left unchanged if they are not set (nil).
+	Labels       map[string]string // Labels of the container, replacing all its labels
+	Healthcheck  *HealthConfig     `json:",omitempty"` // Healthcheck describes how to check the container is healthy
+	LogConfig    *LogConfig        `json:",omitempty"` // Configuration of the logs for this container; the log driver is kept if Type is empty
+	PortBindings nat.PortMap       // Port mapping between the exposed port (container) and the host, replacing all its port bindings
 }
 
 // HostConfig the non-portable Config structure of a container.
@@ -399,6 +476,7 @@ type HostConfig struct {
 	AutoRemove      bool          // Automatically remove container when it exits
 	VolumeDriver    string        // Name of the volume driver used to mount volumes
 	VolumesFrom     []string      // List of volumes to take from other container
+	DependsOn       []Dependency  `json:",omitempty"` // Containers which must meet a condition for the container to be started
 
 	// Applicable to UNIX platforms
 	CapAdd          strslice.StrSlice // List of kernel capabilities to add to the container
@@ -426,6 +504,7 @@ type HostConfig struct {
 	ShmSize         int64             // Total shm memory usage
 	Sysctls         map[string]string `json:",omitempty"` // List of Namespaced sysctls used for the container
 	Runtime         string            `json:",omitempty"` // Runtime to use with this container
+	EgressPolicy    *EgressPolicy     `json:",omitempty"` // Destinations the container can connect to on bridge networks
 
 	// Applicable to Windows
 	ConsoleSize [2]uint   // Initial console size (height,width)
@@ -0,0 +1,65 @@
+package container // import "github.com/docker/docker/api/types/container"
+
+import "time"
+
+// Fields of the processes of a container which can be selected when listing
+// them. The PID of processes is always returned.
+const (
+	ProcessFieldPID        = "pid"
+	ProcessFieldPPID       = "ppid"
+	ProcessFieldUser       = "user"
+	ProcessFieldUID        = "uid"
+	ProcessFieldHostUID    = "host_uid"
+	ProcessFieldState      = "state"
+	ProcessFieldCPUPercent = "cpu"
+	ProcessFieldRSS        = "rss"
+	ProcessFieldStartTime  = "start_time"
+	ProcessFieldCmdline    = "cmdline"
+	ProcessFieldThreads    = "threads"
+)
+
+// ProcessFields lists all the fields of the processes of a container.
+var ProcessFields = []string{
+	ProcessFieldPID,
+	ProcessFieldPPID,
+	ProcessFieldUser,
+	ProcessFieldUID,
+	ProcessFieldHostUID,
+	ProcessFieldState,
+	ProcessFieldCPUPercent,
+	ProcessFieldRSS,
+	ProcessFieldStartTime,
+	ProcessFieldCmdline,
+	ProcessFieldThreads,
+}
+
+// Process is a process running in a container. Only the fields selected when
+// listing the processes are set.
+type Process struct {
+	// PID of the process, as seen from the host.
+	PID int `json:"Pid"`
+	// PID of the parent process, as seen from the host.
+	PPID int `json:"PPid,omitempty"`
+	// Name of the user running the process in the container, looked up in
+	// the /etc/passwd file of the container.
+	User string `json:",omitempty"`
+	// UID of the user running the process, in the user namespace of the
+	// container.
+	UID *uint32 `json:"Uid,omitempty"`
+	// UID of the user running the process, in the user namespace of the host.
+	HostUID *uint32 `json:"HostUid,omitempty"`
+	// State of the process, for example "R" (running) or "S" (sleeping).
+	State string `json:",omitempty"`
+	// Share of the CPU time the process used since it started, in percent.
+	CPUPercent *float64 `json:"CpuPercent,omitempty"`
+	// Resident set size of the process in bytes.
+	RSS *uint64 `json:"Rss,omitempty"`
+	// Time the process was started.
+	StartTime *time.Time `json:",omitempty"`
+	// Command line of the process.
+	Cmdline []string `json:",omitempty"`
+	// Number of threads of the process.
+	Threads int `json:",omitempty"`
+	// Child processes of the process, if the processes are listed as a tree.
+	Children []Process `json:",omitempty"`
+}
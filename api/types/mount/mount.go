@@ -17,13 +17,16 @@ const (
 	TypeTmpfs Type = "tmpfs"
 	// TypeNamedPipe is the type for mounting Windows named pipes
 	TypeNamedPipe Type = "npipe"
+	// TypeImage is the type for mounting the root filesystem of an image
+	// read-only
+	TypeImage Type = "image"
 )
 
 // Mount represents a mount (volume).
 type Mount struct {
 	Type Type `json:",omitempty"`
 	// Source specifies the name of the mount. Depending on mount type, this
-	// may be a volume name or a host path, or even ignored.
+	// may be a volume name, an image reference or a host path, or even ignored.
 	// Source is not supported for tmpfs (must be an empty value)
 	Source      string      `json:",omitempty"`
 	Target      string      `json:",omitempty"`
@@ -85,7 +88,10 @@ type BindOptions struct {
 
 // VolumeOptions represents the options for a mount of type volume.
 type VolumeOptions struct {
-	NoCopy       bool              `json:",omitempty"`
+	NoCopy bool `json:",omitempty"`
+	// Subpath is the path of the directory inside the volume to mount,
+	// relative to the root of the volume.
+	Subpath      string            `json:",omitempty"`
 	Labels       map[string]string `json:",omitempty"`
 	DriverConfig *Driver           `json:",omitempty"`
 }
@@ -69,6 +69,11 @@ type MemoryStats struct {
 	// number of times memory usage hits limits.
 	Failcnt uint64 `json:"failcnt,omitempty"`
 	Limit   uint64 `json:"limit,omitempty"`
+	// number of times the memory usage of the cgroup reached its limit, and
+	// the OOM killer was invoked. Only set on cgroup v2 hosts.
+	OOMEvents uint64 `json:"oom_events,omitempty"`
+	// number of processes of the cgroup killed by the OOM killer.
+	OOMKillEvents uint64 `json:"oom_kill_events,omitempty"`
 
 	// Windows Memory Stats
 	// See https://technet.microsoft.com/en-us/magazine/ff382715.aspx
@@ -138,6 +143,15 @@ type NetworkStats struct {
 	EndpointID string `json:"endpoint_id,omitempty"`
 	// Instance ID. Not used on Linux.
 	InstanceID string `json:"instance_id,omitempty"`
+	// Rate limits of the bytes received and sent, in bytes per second, as
+	// configured in the host config of the container. Linux only, and not
+	// set if there is no limit.
+	RxRateLimit uint64 `json:"rx_rate_limit,omitempty"`
+	TxRateLimit uint64 `json:"tx_rate_limit,omitempty"`
+	// Maximum number of connections tracked in each direction, as configured
+	// in the host config of the container. Linux only, and not set if there
+	// is no limit.
+	ConntrackLimit uint64 `json:"conntrack_limit,omitempty"`
 }
 
 // PidsStats contains the stats of a container's pids
@@ -149,6 +163,35 @@ type PidsStats struct {
 	Limit uint64 `json:"limit,omitempty"`
 }
 
+// PressureData is the pressure stall information of a resource, for either
+// some or all of the tasks of a cgroup.
+type PressureData struct {
+	// Percentage of time tasks were stalled on the resource in the last
+	// 10, 60, and 300 seconds.
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	// Total time tasks were stalled on the resource.
+	// Units: microseconds.
+	Total uint64 `json:"total"`
+}
+
+// Pressure is the pressure stall information of a resource. Some is the share
+// of time at least some tasks were stalled on the resource, Full the share of
+// time all non-idle tasks were stalled simultaneously.
+type Pressure struct {
+	Some *PressureData `json:"some,omitempty"`
+	Full *PressureData `json:"full,omitempty"`
+}
+
+// PressureStats contains the pressure stall information (PSI) of a container's
+// cgroup. Only available on cgroup v2 hosts with a kernel supporting PSI.
+type PressureStats struct {
+	CPU    *Pressure `json:"cpu,omitempty"`
+	Memory *Pressure `json:"memory,omitempty"`
+	IO     *Pressure `json:"io,omitempty"`
+}
+
 // Stats is Ultimate struct aggregating all types of stats of one container
 type Stats struct {
 	// Common stats
@@ -156,8 +199,9 @@ type Stats struct {
 	PreRead time.Time `json:"preread"`
 
 	// Linux specific stats, not populated on Windows.
-	PidsStats  PidsStats  `json:"pids_stats,omitempty"`
-	BlkioStats BlkioStats `json:"blkio_stats,omitempty"`
+	PidsStats     PidsStats      `json:"pids_stats,omitempty"`
+	BlkioStats    BlkioStats     `json:"blkio_stats,omitempty"`
+	PressureStats *PressureStats `json:"pressure_stats,omitempty"`
 
 	// Windows specific stats, not populated on Linux.
 	NumProcs     uint32       `json:"num_procs"`
@@ -50,7 +50,8 @@ type ImageInspect struct {
 
 // ImageMetadata contains engine-local data about the image
 type ImageMetadata struct {
-	LastTagTime time.Time `json:",omitempty"`
+	LastTagTime  time.Time `json:",omitempty"`
+	LastUsedTime time.Time `json:",omitempty"`
 }
 
 // Container contains response of Engine API:
@@ -174,6 +175,7 @@ type Info struct {
 	SystemTime         string
 	LoggingDriver      string
 	CgroupDriver       string
+	CgroupVersion      string `json:",omitempty"`
 	NEventsListener    int
 	KernelVersion      string
 	OperatingSystem    string
@@ -354,6 +356,7 @@ type ContainerJSONBase struct {
 	GraphDriver     GraphDriverData
 	SizeRw          *int64 `json:",omitempty"`
 	SizeRootFs      *int64 `json:",omitempty"`
+	DebugTarget     string `json:",omitempty"`
 }
 
 // ContainerJSON is newly used struct along with MountPoint
@@ -536,6 +539,47 @@ type VolumesPruneReport struct {
 	SpaceReclaimed uint64
 }
 
+// VolumeSnapshot is a point-in-time copy of a volume.
+type VolumeSnapshot struct {
+	// Name of the snapshot, which is unique per volume
+	Name string
+	// Date/Time the snapshot was taken
+	CreatedAt string
+}
+
+// VolumeSnapshotCreateRequest contains the request for Engine API:
+// POST "/volumes/{name}/snapshots"
+type VolumeSnapshotCreateRequest struct {
+	Name string
+}
+
+// VolumeCloneRequest contains the request for Engine API:
+// POST "/volumes/{name}/clone"
+type VolumeCloneRequest struct {
+	// Name of the new volume. If not specified, Docker generates a name.
+	Name string
+	// Snapshot to clone the volume from. If not specified, the current data
+	// of the volume is cloned.
+	Snapshot string `json:",omitempty"`
+	// Labels of the new volume
+	Labels map[string]string `json:",omitempty"`
+	// DriverOpts are the driver options of the new volume
+	DriverOpts map[string]string `json:",omitempty"`
+}
+
+// VolumeRestoreRequest contains the request for Engine API:
+// POST "/volumes/{name}/restore"
+type VolumeRestoreRequest struct {
+	Snapshot string
+}
+
+// VolumeResizeRequest contains the request for Engine API:
+// POST "/volumes/{name}/resize"
+type VolumeResizeRequest struct {
+	// Size is the new size of the volume in bytes
+	Size int64
+}
+
 // ImagesPruneReport contains the response for Engine API:
 // POST "/images/prune"
 type ImagesPruneReport struct {
@@ -589,6 +633,43 @@ type PushResult struct {
 	Size   int
 }
 
+// ImageSaveRequest is the request body of Engine API:
+// POST "/images/get"
+type ImageSaveRequest struct {
+	// Names are the images to save.
+	Names []string
+	// ExcludeLayers are the ChainIDs of layers whose content is left out
+	// of the archive, because the daemon the archive is loaded into
+	// already has them.
+	ExcludeLayers []string `json:",omitempty"`
+}
+
+// Transfer contains response of Engine API:
+// GET "/transfers"
+type Transfer struct {
+	// ID is the identifier of the transfer.
+	ID string
+	// Action is either "pull" or "push".
+	Action string
+	// Ref is the reference of the image being transferred.
+	Ref string
+	// Status is one of "running", "complete", "failed" or "cancelled".
+	Status string
+	// Error is the error the transfer failed with, if any.
+	Error string `json:",omitempty"`
+	// Created is the time the transfer was started.
+	Created time.Time
+	// Finished is the time the transfer finished, if it is not running.
+	Finished *time.Time `json:",omitempty"`
+}
+
+// TransferStarted is sent as auxiliary message at the start of the progress
+// stream of an image pull or push, to identify the transfer so that clients
+// can reattach to it.
+type TransferStarted struct {
+	TransferID string
+}
+
 // BuildResult contains the image id of a successful build
 type BuildResult struct {
 	ID string
@@ -14,10 +14,21 @@ type Volume struct {
 	// Required: true
 	Driver string `json:"Driver"`
 
+	// driver capabilities
+	DriverCapabilities *VolumeDriverCapabilities `json:"DriverCapabilities,omitempty"`
+
 	// User-defined key/value metadata.
 	// Required: true
 	Labels map[string]string `json:"Labels"`
 
+	// Date/Time the volume was last mounted. Omitted if the volume was
+	// never mounted, or if this information is not available.
+	LastMountedAt string `json:"LastMountedAt,omitempty"`
+
+	// Date/Time the volume was last unmounted. Omitted if the volume was
+	// never unmounted, or if this information is not available.
+	LastUnmountedAt string `json:"LastUnmountedAt,omitempty"`
+
 	// Mount path of the volume on the host.
 	// Required: true
 	Mountpoint string `json:"Mountpoint"`
@@ -47,8 +58,43 @@ type Volume struct {
 	UsageData *VolumeUsageData `json:"UsageData,omitempty"`
 }
 
+// VolumeDriverCapabilities The optional features supported by the volume driver
+// used by the volume. Omitted if the capabilities of the volume driver are
+// not known.
+//
+// swagger:model VolumeDriverCapabilities
+type VolumeDriverCapabilities struct {
+
+	// Indicates that the driver accepts per-mount options (read-only and
+	// subpath).
+	//
+	// Required: true
+	MountOptions bool `json:"MountOptions"`
+
+	// Version of the volume driver protocol implemented by the driver.
+	// `0` for drivers implementing the original protocol, which do not
+	// support any optional feature.
+	//
+	// Required: true
+	ProtocolVersion int64 `json:"ProtocolVersion"`
+
+	// Indicates that the driver can change the size of volumes.
+	// Required: true
+	Resize bool `json:"Resize"`
+
+	// Indicates that the driver can snapshot, clone and restore volumes.
+	// Required: true
+	Snapshot bool `json:"Snapshot"`
+
+	// Indicates that the driver reports the disk usage of volumes.
+	// Required: true
+	Usage bool `json:"Usage"`
+}
+
 // VolumeUsageData Usage details about the volume. This information is used by the
-// `GET /system/df` endpoint, and omitted in other endpoints.
+// `GET /system/df` endpoint, and by the `GET /volumes/{name}` endpoint
+// for volumes whose driver reports the disk usage of volumes. It is
+// omitted in other endpoints.
 //
 // swagger:model VolumeUsageData
 type VolumeUsageData struct {
@@ -61,9 +107,17 @@ type VolumeUsageData struct {
 
 	// Amount of disk space used by the volume (in bytes). This information
 	// is only available for volumes created with the `"local"` volume
-	// driver. For volumes created with other volume drivers, this field
-	// is set to `-1` ("not available")
+	// driver, or with volume drivers reporting the disk usage of volumes.
+	// For volumes created with other volume drivers, this field is set to
+	// `-1` ("not available")
 	//
 	// Required: true
 	Size int64 `json:"Size"`
+
+	// Maximum amount of disk space the volume can use (in bytes). This
+	// information is only available for volumes created with the
+	// `"local"` volume driver and the `size` option, or with volume
+	// drivers reporting a size limit, and omitted otherwise.
+	//
+	Limit int64 `json:"Limit,omitempty"`
 }
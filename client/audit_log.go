@@ -0,0 +1,30 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/audit"
+)
+
+// AuditLog returns the entries of the audit log of the daemon.
+func (cli *Client) AuditLog(ctx context.Context, options types.AuditLogOptions) ([]audit.Entry, error) {
+	if err := cli.NewVersionError("1.41", "audit log"); err != nil {
+		return nil, err
+	}
+	query, err := buildEventsQueryParams(cli.version, types.EventsOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cli.get(ctx, "/audit", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []audit.Entry
+	err = json.NewDecoder(resp.body).Decode(&entries)
+	return entries, err
+}
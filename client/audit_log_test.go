@@ -0,0 +1,65 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestAuditLogError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.AuditLog(context.Background(), types.AuditLogOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestAuditLogVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.40",
+	}
+	_, err := client.AuditLog(context.Background(), types.AuditLogOptions{})
+	assert.Check(t, is.Error(err, `"audit log" requires API version 1.41, but the Docker daemon API version is 1.40`))
+}
+
+func TestAuditLog(t *testing.T) {
+	expectedURL := "/audit"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != expectedURL {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if f := req.URL.Query().Get("filters"); f != `{"container":{"web":true}}` {
+				return nil, fmt.Errorf("unexpected filters %q", f)
+			}
+			b, err := json.Marshal([]audit.Entry{{Action: audit.RequestAction, Container: "web"}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	entries, err := client.AuditLog(context.Background(), types.AuditLogOptions{
+		Filters: filters.NewArgs(filters.Arg("container", "web")),
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Container, "web"))
+}
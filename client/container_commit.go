@@ -42,6 +42,17 @@ func (cli *Client) ContainerCommit(ctx context.Context, container string, option
 	if !options.Pause {
 		query.Set("pause", "0")
 	}
+	if options.Snapshot || options.Incremental {
+		if err := cli.NewVersionError("1.41", "snapshot commit"); err != nil {
+			return types.IDResponse{}, err
+		}
+		if options.Snapshot {
+			query.Set("snapshot", "1")
+		}
+		if options.Incremental {
+			query.Set("incremental", "1")
+		}
+	}
 
 	var response types.IDResponse
 	resp, err := cli.post(ctx, "/commit", query, options.Config, nil)
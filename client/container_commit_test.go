@@ -98,3 +98,45 @@ func TestContainerCommit(t *testing.T) {
 		t.Fatalf("expected `new_container_id`, got %s", r.ID)
 	}
 }
+
+func TestContainerCommitSnapshot(t *testing.T) {
+	client := &Client{
+		version: "1.41",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			if snapshot := query.Get("snapshot"); snapshot != "1" {
+				return nil, fmt.Errorf("snapshot not set in URL query properly. Expected '1', got %s", snapshot)
+			}
+			if incremental := query.Get("incremental"); incremental != "1" {
+				return nil, fmt.Errorf("incremental not set in URL query properly. Expected '1', got %s", incremental)
+			}
+			b, err := json.Marshal(types.IDResponse{ID: "new_image_id"})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	r, err := client.ContainerCommit(context.Background(), "container_id", types.ContainerCommitOptions{
+		Snapshot:    true,
+		Incremental: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "new_image_id" {
+		t.Fatalf("expected `new_image_id`, got %s", r.ID)
+	}
+
+	client.version = "1.40"
+	_, err = client.ContainerCommit(context.Background(), "container_id", types.ContainerCommitOptions{
+		Snapshot: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires API version 1.41") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
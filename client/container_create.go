@@ -24,6 +24,21 @@ func (cli *Client) ContainerCreate(ctx context.Context, config *container.Config
 	if err := cli.NewVersionError("1.25", "stop timeout"); config != nil && config.StopTimeout != nil && err != nil {
 		return response, err
 	}
+	if err := cli.NewVersionError("1.41", "stop sequence"); config != nil && len(config.StopSequence) > 0 && err != nil {
+		return response, err
+	}
+	if err := cli.NewVersionError("1.41", "pre-stop hook"); config != nil && config.PreStop != nil && err != nil {
+		return response, err
+	}
+	if err := cli.NewVersionError("1.41", "container dependencies"); hostConfig != nil && len(hostConfig.DependsOn) > 0 && err != nil {
+		return response, err
+	}
+	if err := cli.NewVersionError("1.41", "network limits"); hostConfig != nil && hasNetworkLimits(hostConfig.Resources) && err != nil {
+		return response, err
+	}
+	if err := cli.NewVersionError("1.41", "egress policy"); hostConfig != nil && hostConfig.EgressPolicy != nil && err != nil {
+		return response, err
+	}
 
 	// When using API 1.24 and under, the client is responsible for removing the container
 	if hostConfig != nil && versions.LessThan(cli.ClientVersion(), "1.25") {
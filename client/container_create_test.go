@@ -123,3 +123,61 @@ func TestContainerCreateAutoRemove(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestContainerCreateStopSequenceVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.40",
+	}
+	_, err := client.ContainerCreate(context.Background(), &container.Config{
+		StopSequence: []container.StopSignalStep{{Signal: "SIGTERM", Timeout: 5}},
+	}, nil, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `"stop sequence" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err = client.ContainerCreate(context.Background(), &container.Config{
+		PreStop: &container.PreStopHook{Cmd: []string{"drain"}},
+	}, nil, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `"pre-stop hook" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestContainerCreateDependsOnVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.40",
+	}
+	_, err := client.ContainerCreate(context.Background(), &container.Config{}, &container.HostConfig{
+		DependsOn: []container.Dependency{{Container: "db", Condition: container.DependencyHealthy}},
+	}, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `"container dependencies" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestContainerCreateNetworkLimitsVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.40",
+	}
+	_, err := client.ContainerCreate(context.Background(), &container.Config{}, &container.HostConfig{
+		Resources: container.Resources{NetworkConntrackMax: 1000},
+	}, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `"network limits" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestContainerCreateEgressPolicyVersion(t *testing.T) {
+	client := &Client{
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+		version: "1.40",
+	}
+	_, err := client.ContainerCreate(context.Background(), &container.Config{}, &container.HostConfig{
+		EgressPolicy: &container.EgressPolicy{Allow: []container.EgressRule{{CIDR: "10.0.0.0/8"}}},
+	}, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `"egress policy" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
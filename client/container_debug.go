@@ -0,0 +1,28 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerDebug creates a debug container, which joins the namespaces of the
+// given running container. The debug container is started like other
+// containers, and is removed when it exits.
+func (cli *Client) ContainerDebug(ctx context.Context, containerID string, config types.ContainerDebugConfig) (container.ContainerCreateCreatedBody, error) {
+	var response container.ContainerCreateCreatedBody
+	if err := cli.NewVersionError("1.41", "container debug"); err != nil {
+		return response, err
+	}
+
+	serverResp, err := cli.post(ctx, "/containers/"+containerID+"/debug", nil, config, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return response, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&response)
+	return response, err
+}
@@ -0,0 +1,76 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestContainerDebugError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerDebug(context.Background(), "nothing", types.ContainerDebugConfig{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestContainerDebug(t *testing.T) {
+	expectedURL := "/containers/container_id/debug"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var config types.ContainerDebugConfig
+			if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+				return nil, err
+			}
+			if config.Image != "busybox" {
+				return nil, fmt.Errorf("expected a debug config with Image == 'busybox', got %v", config)
+			}
+			b, err := json.Marshal(container.ContainerCreateCreatedBody{
+				ID: "debug_id",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	r, err := client.ContainerDebug(context.Background(), "container_id", types.ContainerDebugConfig{
+		Image: "busybox",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "debug_id" {
+		t.Fatalf("expected `debug_id`, got %s", r.ID)
+	}
+
+	client.version = "1.40"
+	_, err = client.ContainerDebug(context.Background(), "container_id", types.ContainerDebugConfig{})
+	if err == nil || !strings.Contains(err.Error(), "requires API version 1.41") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
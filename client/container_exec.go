@@ -3,6 +3,7 @@ package client // import "github.com/docker/docker/client"
 import (
 	"context"
 	"encoding/json"
+	"net/url"
 
 	"github.com/docker/docker/api/types"
 )
@@ -14,6 +15,9 @@ func (cli *Client) ContainerExecCreate(ctx context.Context, container string, co
 	if err := cli.NewVersionError("1.25", "env"); len(config.Env) != 0 && err != nil {
 		return response, err
 	}
+	if err := cli.NewVersionError("1.41", "detachable"); config.Detachable && err != nil {
+		return response, err
+	}
 
 	resp, err := cli.post(ctx, "/containers/"+container+"/exec", nil, config, nil)
 	defer ensureReaderClosed(resp)
@@ -40,6 +44,39 @@ func (cli *Client) ContainerExecAttach(ctx context.Context, execID string, confi
 	return cli.postHijacked(ctx, "/exec/"+execID+"/start", nil, config, headers)
 }
 
+// ContainerExecReattach attaches a connection to a detachable exec process,
+// which was already started. If options.Logs is set, the output buffered by
+// the server is returned first. It's up to the caller to close the hijacked
+// connection by calling types.HijackedResponse.Close.
+func (cli *Client) ContainerExecReattach(ctx context.Context, execID string, options types.ContainerAttachOptions) (types.HijackedResponse, error) {
+	if err := cli.NewVersionError("1.41", "exec reattach"); err != nil {
+		return types.HijackedResponse{}, err
+	}
+
+	query := url.Values{}
+	if options.Stream {
+		query.Set("stream", "1")
+	}
+	if options.Stdin {
+		query.Set("stdin", "1")
+	}
+	if options.Stdout {
+		query.Set("stdout", "1")
+	}
+	if options.Stderr {
+		query.Set("stderr", "1")
+	}
+	if options.DetachKeys != "" {
+		query.Set("detachKeys", options.DetachKeys)
+	}
+	if options.Logs {
+		query.Set("logs", "1")
+	}
+
+	headers := map[string][]string{"Content-Type": {"text/plain"}}
+	return cli.postHijacked(ctx, "/exec/"+execID+"/attach", query, nil, headers)
+}
+
 // ContainerExecInspect returns information about a specific exec process on the docker host.
 func (cli *Client) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
 	var response types.ContainerExecInspect
@@ -164,3 +164,20 @@ func TestContainerExecInspect(t *testing.T) {
 		t.Fatalf("expected ContainerID `container_id`, got %s", inspect.ContainerID)
 	}
 }
+
+func TestContainerExecDetachableVersion(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerExecCreate(context.Background(), "container_id", types.ExecConfig{
+		Detachable: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), `"detachable" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err = client.ContainerExecReattach(context.Background(), "exec_id", types.ContainerAttachOptions{})
+	if err == nil || !strings.Contains(err.Error(), `"exec reattach" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerProcesses returns the processes running in a container, with the
+// fields selected in options.
+func (cli *Client) ContainerProcesses(ctx context.Context, containerID string, options types.ContainerProcessesOptions) ([]container.Process, error) {
+	if err := cli.NewVersionError("1.41", "container processes"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if len(options.Fields) > 0 {
+		query.Set("fields", strings.Join(options.Fields, ","))
+	}
+	if options.Tree {
+		query.Set("tree", "1")
+	}
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/processes", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, wrapResponseError(err, resp, "container", containerID)
+	}
+
+	var processes []container.Process
+	err = json.NewDecoder(resp.body).Decode(&processes)
+	return processes, err
+}
@@ -0,0 +1,69 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestContainerProcessesError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerProcesses(context.Background(), "nothing", types.ContainerProcessesOptions{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestContainerProcesses(t *testing.T) {
+	expectedURL := "/containers/container_id/processes"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			if fields := query.Get("fields"); fields != "pid,ppid" {
+				return nil, fmt.Errorf("fields not set in URL query properly. Expected 'pid,ppid', got %v", fields)
+			}
+			if tree := query.Get("tree"); tree != "1" {
+				return nil, fmt.Errorf("tree not set in URL query properly. Expected '1', got %v", tree)
+			}
+
+			b, err := json.Marshal([]container.Process{
+				{PID: 10, PPID: 1, Children: []container.Process{{PID: 11, PPID: 10}}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	processes, err := client.ContainerProcesses(context.Background(), "container_id", types.ContainerProcessesOptions{
+		Fields: []string{"pid", "ppid"},
+		Tree:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 1 || processes[0].PID != 10 || len(processes[0].Children) != 1 || processes[0].Children[0].PID != 11 {
+		t.Fatalf("unexpected processes: %+v", processes)
+	}
+}
@@ -7,9 +7,15 @@ import (
 	"github.com/docker/docker/api/types/container"
 )
 
-// ContainerUpdate updates resources of a container
+// ContainerUpdate updates resources and settings of a container
 func (cli *Client) ContainerUpdate(ctx context.Context, containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
 	var response container.ContainerUpdateOKBody
+	if err := cli.NewVersionError("1.41", "updating labels, healthcheck, log configuration, published ports or devices"); err != nil && updatesSettings(updateConfig) {
+		return response, err
+	}
+	if err := cli.NewVersionError("1.41", "network limits"); err != nil && hasNetworkLimits(updateConfig.Resources) {
+		return response, err
+	}
 	serverResp, err := cli.post(ctx, "/containers/"+containerID+"/update", nil, updateConfig, nil)
 	defer ensureReaderClosed(serverResp)
 	if err != nil {
@@ -19,3 +25,21 @@ func (cli *Client) ContainerUpdate(ctx context.Context, containerID string, upda
 	err = json.NewDecoder(serverResp.body).Decode(&response)
 	return response, err
 }
+
+// updatesSettings returns whether updateConfig updates any of the settings
+// which can only be updated since API version 1.41.
+func updatesSettings(updateConfig container.UpdateConfig) bool {
+	return updateConfig.Labels != nil ||
+		updateConfig.Healthcheck != nil ||
+		updateConfig.LogConfig != nil ||
+		updateConfig.PortBindings != nil ||
+		updateConfig.Devices != nil
+}
+
+// hasNetworkLimits returns whether resources set any of the network limits,
+// which were added in API version 1.41.
+func hasNetworkLimits(resources container.Resources) bool {
+	return resources.NetworkIngressRate != 0 ||
+		resources.NetworkEgressRate != 0 ||
+		resources.NetworkConntrackMax != 0
+}
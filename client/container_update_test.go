@@ -60,3 +60,24 @@ func TestContainerUpdate(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestContainerUpdateSettingsVersion(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("unexpected request to %s", req.URL)
+		}),
+	}
+	_, err := client.ContainerUpdate(context.Background(), "container_id", container.UpdateConfig{
+		Labels: map[string]string{"foo": "bar"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires API version 1.41") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err = client.ContainerUpdate(context.Background(), "container_id", container.UpdateConfig{
+		Resources: container.Resources{NetworkEgressRate: 1024 * 1024},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"network limits" requires API version 1.41`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// ImageLayers returns the ChainIDs of the layers the docker host has. They can
+// be passed to ImageSaveExcludingLayers on another host, to transfer images to
+// this host without sending the layers it already has.
+func (cli *Client) ImageLayers(ctx context.Context) ([]string, error) {
+	var layers []string
+	if err := cli.NewVersionError("1.41", "image layers"); err != nil {
+		return layers, err
+	}
+	resp, err := cli.get(ctx, "/images/layers", url.Values{}, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return layers, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&layers)
+	return layers, err
+}
@@ -0,0 +1,29 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImageSaveExcludingLayers retrieves one or more images from the docker host
+// as an io.ReadCloser, like ImageSave, but leaves out the content of the layers
+// with the given ChainIDs. The result can be loaded with ImageLoad by a docker
+// host which has those layers, as returned by its ImageLayers.
+// It's up to the caller to store the images and close the stream.
+func (cli *Client) ImageSaveExcludingLayers(ctx context.Context, imageIDs []string, excludeLayers []string) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.41", "image save excluding layers"); err != nil {
+		return nil, err
+	}
+	req := types.ImageSaveRequest{
+		Names:         imageIDs,
+		ExcludeLayers: excludeLayers,
+	}
+	resp, err := cli.post(ctx, "/images/get", url.Values{}, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
@@ -0,0 +1,70 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestImageSaveExcludingLayersError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ImageSaveExcludingLayers(context.Background(), []string{"nothing"}, nil)
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestImageSaveExcludingLayers(t *testing.T) {
+	expectedURL := "/images/get"
+	expectedRequest := types.ImageSaveRequest{
+		Names:         []string{"image_id1", "image_id2"},
+		ExcludeLayers: []string{"sha256:0f3d4bb8c8b1c8a8e1cf3d4bc5a5e2d1e0b3f1a6c4d2e8f7a9b0c1d2e3f4a5b6"},
+	}
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(r.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, r.URL)
+			}
+			if r.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", r.Method)
+			}
+			var req types.ImageSaveRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(req, expectedRequest) {
+				return nil, fmt.Errorf("expected request %v, got %v", expectedRequest, req)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	saveResponse, err := client.ImageSaveExcludingLayers(context.Background(), expectedRequest.Names, expectedRequest.ExcludeLayers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := ioutil.ReadAll(saveResponse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveResponse.Close()
+	if string(response) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(response))
+	}
+}
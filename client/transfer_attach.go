@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// TransferAttach attaches to the progress of a running image pull or push.
+// The progress is returned as a stream of JSON messages, in the same format
+// as the output of ImagePull and ImagePush. Closing the stream detaches from
+// the transfer without cancelling it.
+// It's up to the caller to handle the io.ReadCloser and close it properly.
+func (cli *Client) TransferAttach(ctx context.Context, transferID string) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.41", "transfer attach"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.post(ctx, "/transfers/"+transferID+"/attach", url.Values{}, nil, nil)
+	if err != nil {
+		return nil, wrapResponseError(err, resp, "transfer", transferID)
+	}
+	return resp.body, nil
+}
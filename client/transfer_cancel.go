@@ -0,0 +1,16 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+)
+
+// TransferCancel cancels a running image pull or push.
+func (cli *Client) TransferCancel(ctx context.Context, transferID string) error {
+	if err := cli.NewVersionError("1.41", "transfer cancel"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/transfers/"+transferID, url.Values{}, nil)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "transfer", transferID)
+}
@@ -0,0 +1,26 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// TransferList returns the image pulls and pushes which are running in the
+// docker host, or which finished recently.
+func (cli *Client) TransferList(ctx context.Context) ([]types.Transfer, error) {
+	var transfers []types.Transfer
+	if err := cli.NewVersionError("1.41", "transfer list"); err != nil {
+		return transfers, err
+	}
+	resp, err := cli.get(ctx, "/transfers", url.Values{}, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return transfers, err
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&transfers)
+	return transfers, err
+}
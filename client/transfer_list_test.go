@@ -0,0 +1,75 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestTransferListError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.TransferList(context.Background())
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestTransferList(t *testing.T) {
+	expectedURL := "/transfers"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "GET" {
+				return nil, fmt.Errorf("expected GET method, got %s", req.Method)
+			}
+			b, err := json.Marshal([]types.Transfer{
+				{
+					ID:     "transfer_id1",
+					Action: "pull",
+					Ref:    "busybox:latest",
+					Status: "running",
+				},
+				{
+					ID:     "transfer_id2",
+					Action: "push",
+					Ref:    "example.com/busybox:latest",
+					Status: "failed",
+					Error:  "unauthorized",
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	transfers, err := client.TransferList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 {
+		t.Fatalf("expected 2 transfers, got %v", transfers)
+	}
+	if transfers[1].Error != "unauthorized" {
+		t.Fatalf("expected error of second transfer to be returned, got %q", transfers[1].Error)
+	}
+}
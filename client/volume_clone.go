@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeClone creates a new volume from a volume, or from one of its
+// snapshots.
+func (cli *Client) VolumeClone(ctx context.Context, volumeID string, options types.VolumeCloneRequest) (types.Volume, error) {
+	var volume types.Volume
+	if err := cli.NewVersionError("1.41", "volume clone"); err != nil {
+		return volume, err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/clone", nil, options, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return volume, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	return volume, err
+}
@@ -0,0 +1,71 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeCloneError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.VolumeClone(context.Background(), "volume_id", types.VolumeCloneRequest{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestVolumeClone(t *testing.T) {
+	expectedURL := "/volumes/volume_id/clone"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var body types.VolumeCloneRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			if body.Name != "clone" || body.Snapshot != "snapshot" {
+				return nil, fmt.Errorf("unexpected clone request: %+v", body)
+			}
+			content, err := json.Marshal(types.Volume{
+				Name:   "clone",
+				Driver: "local",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	volume, err := client.VolumeClone(context.Background(), "volume_id", types.VolumeCloneRequest{
+		Name:     "clone",
+		Snapshot: "snapshot",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.Name != "clone" {
+		t.Fatalf("expected volume.Name to be 'clone', got %s", volume.Name)
+	}
+}
@@ -0,0 +1,48 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+)
+
+// CopyToVolume copies content into the volume, creating the volume if it
+// does not exist.
+// Note that `content` must be a Reader for a TAR archive
+func (cli *Client) CopyToVolume(ctx context.Context, volumeID, dstPath string, content io.Reader, options types.CopyToVolumeOptions) error {
+	if err := cli.NewVersionError("1.41", "volume copy"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("path", filepath.ToSlash(dstPath)) // Normalize the paths used in the API.
+	// Do not allow for an existing directory to be overwritten by a non-directory and vice versa.
+	if !options.AllowOverwriteDirWithFile {
+		query.Set("noOverwriteDirNonDir", "true")
+	}
+	if options.Driver != "" {
+		query.Set("driver", options.Driver)
+	}
+
+	response, err := cli.putRaw(ctx, "/volumes/"+volumeID+"/archive", query, content, nil)
+	defer ensureReaderClosed(response)
+	return wrapResponseError(err, response, "volume:path", volumeID+":"+dstPath)
+}
+
+// CopyFromVolume gets the content from the volume and returns it as a Reader
+// for a TAR archive. It's up to the caller to close the reader.
+func (cli *Client) CopyFromVolume(ctx context.Context, volumeID, srcPath string) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.41", "volume copy"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("path", filepath.ToSlash(srcPath)) // Normalize the paths used in the API.
+
+	response, err := cli.get(ctx, "/volumes/"+volumeID+"/archive", query, nil)
+	if err != nil {
+		return nil, wrapResponseError(err, response, "volume:path", volumeID+":"+srcPath)
+	}
+	return response.body, nil
+}
@@ -0,0 +1,123 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestCopyToVolumeError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	err := client.CopyToVolume(context.Background(), "volume_id", "/path", bytes.NewReader([]byte("")), types.CopyToVolumeOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+}
+
+func TestCopyToVolumeNotFoundError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "Not found")),
+	}
+	err := client.CopyToVolume(context.Background(), "volume_id", "/path", bytes.NewReader([]byte("")), types.CopyToVolumeOptions{})
+	if !IsErrNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestCopyToVolume(t *testing.T) {
+	expectedURL := "/volumes/volume_id/archive"
+	expectedPath := "/path/to/dir"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "PUT" {
+				return nil, fmt.Errorf("expected PUT method, got %s", req.Method)
+			}
+			query := req.URL.Query()
+			if path := query.Get("path"); path != expectedPath {
+				return nil, fmt.Errorf("path not set in URL query properly, expected '%s', got %s", expectedPath, path)
+			}
+			if noOverwriteDirNonDir := query.Get("noOverwriteDirNonDir"); noOverwriteDirNonDir != "true" {
+				return nil, fmt.Errorf("noOverwriteDirNonDir not set in URL query properly, expected true, got %s", noOverwriteDirNonDir)
+			}
+			if driver := query.Get("driver"); driver != "local" {
+				return nil, fmt.Errorf("driver not set in URL query properly, expected 'local', got %s", driver)
+			}
+			content, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if err := req.Body.Close(); err != nil {
+				return nil, err
+			}
+			if string(content) != "content" {
+				return nil, fmt.Errorf("expected content to be 'content', got %s", string(content))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(""))),
+			}, nil
+		}),
+	}
+	err := client.CopyToVolume(context.Background(), "volume_id", expectedPath, bytes.NewReader([]byte("content")), types.CopyToVolumeOptions{
+		Driver: "local",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyFromVolumeError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.CopyFromVolume(context.Background(), "volume_id", "/path")
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+}
+
+func TestCopyFromVolume(t *testing.T) {
+	expectedURL := "/volumes/volume_id/archive"
+	expectedPath := "/path/to/file"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "GET" {
+				return nil, fmt.Errorf("expected GET method, got %s", req.Method)
+			}
+			if path := req.URL.Query().Get("path"); path != expectedPath {
+				return nil, fmt.Errorf("path not set in URL query properly, expected '%s', got %s", expectedPath, path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("content"))),
+			}, nil
+		}),
+	}
+	r, err := client.CopyFromVolume(context.Background(), "volume_id", expectedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "content" {
+		t.Fatalf("expected content to be 'content', got %s", string(content))
+	}
+}
@@ -0,0 +1,18 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeResize changes the size of a volume to the given size in bytes.
+func (cli *Client) VolumeResize(ctx context.Context, volumeID string, size int64) error {
+	if err := cli.NewVersionError("1.41", "volume resize"); err != nil {
+		return err
+	}
+	body := types.VolumeResizeRequest{Size: size}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/resize", nil, body, nil)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "volume", volumeID)
+}
@@ -0,0 +1,19 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeRestore replaces the data of a volume with the data of one of its
+// snapshots.
+func (cli *Client) VolumeRestore(ctx context.Context, volumeID, snapshot string) error {
+	if err := cli.NewVersionError("1.41", "volume restore"); err != nil {
+		return err
+	}
+	body := types.VolumeRestoreRequest{Snapshot: snapshot}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/restore", nil, body, nil)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "volume", volumeID)
+}
@@ -0,0 +1,24 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeSnapshotCreate creates a snapshot with the given name of a volume.
+func (cli *Client) VolumeSnapshotCreate(ctx context.Context, volumeID, name string) (types.VolumeSnapshot, error) {
+	var snapshot types.VolumeSnapshot
+	if err := cli.NewVersionError("1.41", "volume snapshot create"); err != nil {
+		return snapshot, err
+	}
+	body := types.VolumeSnapshotCreateRequest{Name: name}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/snapshots", nil, body, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return snapshot, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&snapshot)
+	return snapshot, err
+}
@@ -0,0 +1,68 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeSnapshotCreateError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.VolumeSnapshotCreate(context.Background(), "volume_id", "snapshot")
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestVolumeSnapshotCreate(t *testing.T) {
+	expectedURL := "/volumes/volume_id/snapshots"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != "POST" {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var body types.VolumeSnapshotCreateRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			if body.Name != "snapshot" {
+				return nil, fmt.Errorf("expected snapshot name 'snapshot', got %s", body.Name)
+			}
+			content, err := json.Marshal(types.VolumeSnapshot{
+				Name:      "snapshot",
+				CreatedAt: "2020-01-01T00:00:00Z",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	snapshot, err := client.VolumeSnapshotCreate(context.Background(), "volume_id", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Name != "snapshot" {
+		t.Fatalf("expected snapshot.Name to be 'snapshot', got %s", snapshot.Name)
+	}
+}
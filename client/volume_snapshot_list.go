@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeSnapshotList returns the snapshots of a volume.
+func (cli *Client) VolumeSnapshotList(ctx context.Context, volumeID string) ([]types.VolumeSnapshot, error) {
+	var snapshots []types.VolumeSnapshot
+	if err := cli.NewVersionError("1.41", "volume snapshot list"); err != nil {
+		return snapshots, err
+	}
+	resp, err := cli.get(ctx, "/volumes/"+volumeID+"/snapshots", nil, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return snapshots, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&snapshots)
+	return snapshots, err
+}
@@ -0,0 +1,15 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+)
+
+// VolumeSnapshotRemove removes a snapshot of a volume.
+func (cli *Client) VolumeSnapshotRemove(ctx context.Context, volumeID, name string) error {
+	if err := cli.NewVersionError("1.41", "volume snapshot remove"); err != nil {
+		return err
+	}
+	resp, err := cli.delete(ctx, "/volumes/"+volumeID+"/snapshots/"+name, nil, nil)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "volume snapshot", name)
+}
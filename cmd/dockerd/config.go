@@ -73,6 +73,13 @@ func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 	flags.StringVar(&conf.CorsHeaders, "api-cors-header", "", "Set CORS headers in the Engine API")
 	flags.IntVar(&maxConcurrentDownloads, "max-concurrent-downloads", config.DefaultMaxConcurrentDownloads, "Set the max concurrent downloads for each pull")
 	flags.IntVar(&maxConcurrentUploads, "max-concurrent-uploads", config.DefaultMaxConcurrentUploads, "Set the max concurrent uploads for each push")
+	flags.StringVar(&conf.PushCompression, "push-compression", "gzip", "Compression of image layers pushed to a registry (gzip, zstd)")
+	flags.IntVar(&conf.ImageGCHighThreshold, "image-gc-high-threshold", 0, "Disk usage percentage of the data-root above which unused images are removed (0 disables)")
+	flags.IntVar(&conf.ImageGCLowThreshold, "image-gc-low-threshold", 0, "Disk usage percentage of the data-root to which unused images are removed")
+	flags.StringVar(&conf.AuditLog, "audit-log", "", "Path of the audit log of API requests and exec sessions")
+	conf.AuditLogMaxSize = opts.MemBytes(config.DefaultAuditLogMaxSize)
+	flags.Var(&conf.AuditLogMaxSize, "audit-log-max-size", "Size of the audit log above which it is rotated")
+	flags.IntVar(&conf.AuditLogMaxFiles, "audit-log-max-files", config.DefaultAuditLogMaxFiles, "Number of audit log files kept")
 	flags.IntVar(&conf.ShutdownTimeout, "shutdown-timeout", defaultShutdownTimeout, "Set the default shutdown timeout")
 	flags.IntVar(&conf.NetworkDiagnosticPort, "network-diagnostic-port", 0, "TCP port number of the network diagnostic server")
 	flags.MarkHidden("network-diagnostic-port")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/rootless"
 	"github.com/docker/go-units"
 	"github.com/pkg/errors"
@@ -24,6 +25,7 @@ func installConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 	installUnixConfigFlags(conf, flags)
 
 	conf.Ulimits = make(map[string]*units.Ulimit)
+	conf.VolumeKeyProviders = make(map[string]string)
 	conf.NetworkConfig.DefaultAddressPools = opts.PoolsOpt{}
 
 	// Set default value for `--default-shm-size`
@@ -64,6 +66,11 @@ func installConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 	// rootless needs to be explicitly specified for running "rootful" dockerd in rootless dockerd (#38702)
 	// Note that defaultUserlandProxyPath and honorXDG are configured according to the value of rootless.RunningWithRootlessKit, not the value of --rootless.
 	flags.BoolVar(&conf.Rootless, "rootless", rootless.RunningWithRootlessKit(), "Enable rootless mode; typically used with RootlessKit (experimental)")
-	flags.StringVar(&conf.CgroupNamespaceMode, "default-cgroupns-mode", config.DefaultCgroupNamespaceMode, `Default mode for containers cgroup namespace ("host" | "private")`)
+	defaultCgroupNamespaceMode := config.DefaultCgroupNamespaceMode
+	if sysinfo.IsCgroup2UnifiedMode() {
+		defaultCgroupNamespaceMode = config.DefaultCgroupV2NamespaceMode
+	}
+	flags.StringVar(&conf.CgroupNamespaceMode, "default-cgroupns-mode", defaultCgroupNamespaceMode, `Default mode for containers cgroup namespace ("host" | "private")`)
+	flags.Var(opts.NewNamedMapOpts("volume-key-providers", conf.VolumeKeyProviders, nil), "volume-key-provider", "Key provider of encrypted volumes, as the name and the path of its helper program (name=path)")
 	return nil
 }
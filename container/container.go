@@ -42,7 +42,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const configFileName = "config.v2.json"
+const (
+	configFileName = "config.v2.json"
+
+	// DefaultPreStopTimeout is the default time, in seconds, to wait for the
+	// pre-stop hook of a container to exit.
+	DefaultPreStopTimeout = 30
+)
 
 // ExitStatus provides exit reasons for a container.
 type ExitStatus struct {
@@ -88,6 +94,12 @@ type Container struct {
 	DependencyStore        agentexec.DependencyGetter `json:"-"`
 	SecretReferences       []*swarmtypes.SecretReference
 	ConfigReferences       []*swarmtypes.ConfigReference
+	// LastSnapshotCommit is the last commit of the container made from a
+	// snapshot of its RW layer, which incremental commits are based on.
+	LastSnapshotCommit *SnapshotCommit `json:",omitempty"`
+	// DebugTarget is the ID of the container debugged by the container, if
+	// it is a debug container.
+	DebugTarget string `json:",omitempty"`
 	// logDriver for closing
 	LogDriver      logger.Logger  `json:"-"`
 	LogCopier      *logger.Copier `json:"-"`
@@ -108,6 +120,16 @@ type Container struct {
 	SharedEndpointList       []string `json:"-"`
 }
 
+// SnapshotCommit is a commit of a container made from a snapshot of its RW
+// layer.
+type SnapshotCommit struct {
+	// SnapshotID is the ID of the snapshot the image was created from. The
+	// snapshot is kept to compute the changes of the next incremental commit.
+	SnapshotID string
+	// ImageID is the ID of the image created by the commit.
+	ImageID image.ID
+}
+
 // NewBaseContainer creates a new container with its
 // basic configuration.
 func NewBaseContainer(id, root string) *Container {
@@ -477,6 +499,9 @@ func (container *Container) UnmountVolumes(volumeEventLog func(name, action stri
 			continue
 		}
 
+		if volumeMount.Type == mounttypes.TypeImage {
+			continue
+		}
 		attributes := map[string]string{
 			"driver":    volumeMount.Volume.DriverName(),
 			"container": container.ID,
@@ -507,14 +532,55 @@ func (container *Container) StopSignal() int {
 	return int(stopSignal)
 }
 
+// IsStopSignal returns whether the signal stops the container, rather than
+// being handled by it. Any signal stops the container if no stop signal nor
+// stop sequence is configured.
+func (container *Container) IsStopSignal(sig syscall.Signal) bool {
+	if sig == syscall.SIGKILL || (container.Config.StopSignal == "" && len(container.Config.StopSequence) == 0) {
+		return true
+	}
+	if container.Config.StopSignal != "" {
+		if s, _ := signal.ParseSignal(container.Config.StopSignal); s == sig {
+			return true
+		}
+	}
+	for _, step := range container.Config.StopSequence {
+		if s, _ := signal.ParseSignal(step.Signal); s == sig {
+			return true
+		}
+	}
+	return false
+}
+
 // StopTimeout returns the timeout (in seconds) used to stop the container.
+// It is the sum of the timeouts of the stop sequence of the container if
+// no stop timeout is configured.
 func (container *Container) StopTimeout() int {
 	if container.Config.StopTimeout != nil {
 		return *container.Config.StopTimeout
 	}
+	if len(container.Config.StopSequence) > 0 {
+		var timeout int
+		for _, step := range container.Config.StopSequence {
+			timeout += step.Timeout
+		}
+		return timeout
+	}
 	return DefaultStopTimeout
 }
 
+// PreStopTimeout returns the timeout (in seconds) of the pre-stop hook of the
+// container, or 0 if it has none.
+func (container *Container) PreStopTimeout() int {
+	if container.Config.PreStop == nil {
+		return 0
+	}
+	if container.Config.PreStop.Timeout != nil {
+		return *container.Config.PreStop.Timeout
+	}
+	return DefaultPreStopTimeout
+}
+
 // InitDNSHostConfig ensures that the dns fields are never nil.
 // New containers don't ever have those fields nil,
 // but pre created containers can still have those nil values.
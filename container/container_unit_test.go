@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
@@ -124,3 +125,34 @@ func TestContainerLogPathSetForRingLogger(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, c.LogPath, expectedLogPath)
 }
+
+func TestContainerStopSequence(t *testing.T) {
+	c := &Container{
+		Config: &container.Config{
+			StopSignal: "SIGQUIT",
+			StopSequence: []container.StopSignalStep{
+				{Signal: "SIGTERM", Timeout: 5},
+				{Signal: "SIGINT", Timeout: 3},
+			},
+		},
+	}
+	if s := c.StopTimeout(); s != 8 {
+		t.Fatalf("Expected 8, got %v", s)
+	}
+	for _, sig := range []syscall.Signal{syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL} {
+		if !c.IsStopSignal(sig) {
+			t.Fatalf("Expected %v to be a stop signal", sig)
+		}
+	}
+	if c.IsStopSignal(syscall.SIGHUP) {
+		t.Fatal("Expected SIGHUP not to be a stop signal")
+	}
+
+	if s := c.PreStopTimeout(); s != 0 {
+		t.Fatalf("Expected 0, got %v", s)
+	}
+	c.Config.PreStop = &container.PreStopHook{Cmd: []string{"drain"}}
+	if s := c.PreStopTimeout(); s != DefaultPreStopTimeout {
+		t.Fatalf("Expected %v, got %v", DefaultPreStopTimeout, s)
+	}
+}
@@ -345,6 +345,18 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	if resources.PidsLimit != nil {
 		cResources.PidsLimit = resources.PidsLimit
 	}
+	if resources.Devices != nil {
+		cResources.Devices = resources.Devices
+	}
+	if resources.NetworkIngressRate != 0 {
+		cResources.NetworkIngressRate = networkLimit(resources.NetworkIngressRate)
+	}
+	if resources.NetworkEgressRate != 0 {
+		cResources.NetworkEgressRate = networkLimit(resources.NetworkEgressRate)
+	}
+	if resources.NetworkConntrackMax != 0 {
+		cResources.NetworkConntrackMax = networkLimit(resources.NetworkConntrackMax)
+	}
 
 	// update HostConfig of container
 	if hostConfig.RestartPolicy.Name != "" {
@@ -357,6 +369,15 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	return nil
 }
 
+// networkLimit returns the network limit to store for the updated limit l. A
+// negative limit removes the limit.
+func networkLimit(l int64) int64 {
+	if l < 0 {
+		return 0
+	}
+	return l
+}
+
 // DetachAndUnmount uses a detached mount on all mount destinations, then
 // unmounts each volume normally.
 // This is used from daemon/archive for `docker cp`
@@ -163,7 +163,10 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 		resources.CPUCount != 0 ||
 		resources.CPUPercent != 0 ||
 		resources.IOMaximumIOps != 0 ||
-		resources.IOMaximumBandwidth != 0 {
+		resources.IOMaximumBandwidth != 0 ||
+		resources.NetworkIngressRate != 0 ||
+		resources.NetworkEgressRate != 0 ||
+		resources.NetworkConntrackMax != 0 {
 		return fmt.Errorf("resource updating isn't supported on Windows")
 	}
 	// update HostConfig of container
@@ -0,0 +1,39 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// LogAudit appends an entry to the audit log, if it is enabled.
+func (daemon *Daemon) LogAudit(e audit.Entry) {
+	if daemon.auditLog == nil {
+		return
+	}
+	if err := daemon.auditLog.Log(e); err != nil {
+		logrus.WithError(err).WithField("entry", e).Error("Error writing audit log entry")
+	}
+}
+
+// AuditLog returns the entries of the audit log between since and until, if
+// not zero, matching the filters.
+func (daemon *Daemon) AuditLog(since, until time.Time, ef filters.Args) ([]audit.Entry, error) {
+	if daemon.auditLog == nil {
+		return nil, errdefs.Unavailable(errors.New("the audit log is not enabled on this daemon"))
+	}
+	return daemon.auditLog.Query(since, until, ef)
+}
+
+// ContainerID returns the ID of the container with the given name or ID.
+func (daemon *Daemon) ContainerID(name string) (string, error) {
+	c, err := daemon.GetContainer(name)
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
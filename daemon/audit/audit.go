@@ -0,0 +1,276 @@
+// Package audit implements the audit log of the daemon, an append-only log
+// of the API requests changing the state of the daemon and of the exec
+// processes started through the API.
+package audit // import "github.com/docker/docker/daemon/audit"
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var acceptedFilters = map[string]bool{
+	"action":    true,
+	"container": true,
+	"exec":      true,
+	"user":      true,
+}
+
+// Log is an audit log written to a file as JSON lines. The file is rotated
+// once it reaches its maximum size.
+type Log struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	f        *os.File
+	size     int64
+}
+
+// New opens the audit log at path, rotated once it is larger than maxSize
+// bytes and keeping at most maxFiles files. The log is not rotated if
+// maxSize is not positive.
+func New(path string, maxSize int64, maxFiles int) (*Log, error) {
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+	l := &Log{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "error opening audit log")
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrap(err, "error opening audit log")
+	}
+	l.f, l.size = f, fi.Size()
+	return nil
+}
+
+// Log appends an entry to the audit log.
+func (l *Log) Log(e audit.Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return errors.New("audit log is closed")
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(b)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.f.Write(b)
+	l.size += int64(n)
+	return errors.Wrap(err, "error writing audit log")
+}
+
+func (l *Log) rotate() error {
+	if err := l.f.Close(); err != nil {
+		logrus.WithError(err).Warn("Error closing audit log")
+	}
+	l.f = nil
+
+	if l.maxFiles > 1 {
+		if err := os.Remove(l.rotatedPath(l.maxFiles - 1)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "error removing oldest audit log file")
+		}
+		for i := l.maxFiles - 1; i > 1; i-- {
+			if err := os.Rename(l.rotatedPath(i-1), l.rotatedPath(i)); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "error rotating audit log")
+			}
+		}
+		if err := os.Rename(l.path, l.rotatedPath(1)); err != nil {
+			return errors.Wrap(err, "error rotating audit log")
+		}
+	} else if err := os.Remove(l.path); err != nil {
+		return errors.Wrap(err, "error rotating audit log")
+	}
+	return l.open()
+}
+
+func (l *Log) rotatedPath(i int) string {
+	return l.path + "." + strconv.Itoa(i)
+}
+
+// maxQueryEntries is the maximum number of entries returned by Query. The
+// most recent entries are returned if more entries match.
+var maxQueryEntries = 10000
+
+// Query returns the entries of the audit log between since and until, if
+// not zero, matching the filters, oldest first. At most maxQueryEntries
+// entries are returned.
+func (l *Log) Query(since, until time.Time, ef filters.Args) ([]audit.Entry, error) {
+	if err := ef.Validate(acceptedFilters); err != nil {
+		return nil, err
+	}
+
+	files, err := l.openFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	var entries []audit.Entry
+	for _, f := range files {
+		s := bufio.NewScanner(f)
+		s.Buffer(nil, 1024*1024)
+		for s.Scan() {
+			var e audit.Entry
+			if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+				logrus.WithError(err).WithField("file", f.Name()).Warn("Skipping invalid audit log entry")
+				continue
+			}
+			if (!since.IsZero() && e.Time.Before(since)) || (!until.IsZero() && e.Time.After(until)) {
+				continue
+			}
+			if match(e, ef) {
+				entries = append(entries, e)
+				if len(entries) >= 2*maxQueryEntries {
+					entries = append(entries[:0], entries[len(entries)-maxQueryEntries:]...)
+				}
+			}
+		}
+		if err := s.Err(); err != nil {
+			return nil, errors.Wrap(err, "error reading audit log")
+		}
+	}
+	// The entries of requests are written once the request is done, and
+	// may follow the entries written in the meantime.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	if len(entries) > maxQueryEntries {
+		entries = entries[len(entries)-maxQueryEntries:]
+	}
+	return entries, nil
+}
+
+// queryFile is a file of the audit log opened by Query. Reads stop at the
+// size of the file when it was opened.
+type queryFile struct {
+	*os.File
+	r io.Reader
+}
+
+func (f *queryFile) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// openFiles opens the files of the audit log, oldest first. The lock is only
+// held while opening the files, so that they are not rotated in the
+// meantime: rotating the log renames or removes the files, which does not
+// affect the opened ones.
+func (l *Log) openFiles() ([]*queryFile, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var files []*queryFile
+	for i := l.maxFiles - 1; i >= 0; i-- {
+		path := l.path
+		if i > 0 {
+			path = l.rotatedPath(i)
+		}
+		f, err := os.Open(path)
+		if err == nil {
+			var fi os.FileInfo
+			if fi, err = f.Stat(); err == nil {
+				files = append(files, &queryFile{File: f, r: io.LimitReader(f, fi.Size())})
+				continue
+			}
+			f.Close()
+		}
+		if os.IsNotExist(err) {
+			continue
+		}
+		for _, f := range files {
+			f.Close()
+		}
+		return nil, errors.Wrap(err, "error reading audit log")
+	}
+	return files, nil
+}
+
+func match(e audit.Entry, ef filters.Args) bool {
+	if !ef.ExactMatch("action", e.Action) {
+		return false
+	}
+	if ef.Contains("container") && (e.Container == "" || !matchPrefix(ef.Get("container"), e.Container)) {
+		return false
+	}
+	if ef.Contains("exec") && (e.ExecID == "" || !matchPrefix(ef.Get("exec"), e.ExecID)) {
+		return false
+	}
+	if ef.Contains("user") {
+		var users []string
+		if e.Caller.CommonName != "" {
+			users = append(users, e.Caller.CommonName)
+		}
+		if e.Caller.UID != nil {
+			users = append(users, strconv.Itoa(*e.Caller.UID))
+		}
+		found := false
+		for _, u := range users {
+			if ef.ExactMatch("user", u) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPrefix returns whether value matches one of the filter values, or is
+// prefixed by one of them, so that IDs can be given in their short form.
+func matchPrefix(filterValues []string, value string) bool {
+	for _, v := range filterValues {
+		if v == value || (v != "" && strings.HasPrefix(value, v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes the audit log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
@@ -0,0 +1,122 @@
+package audit // import "github.com/docker/docker/daemon/audit"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newTestLog(t *testing.T, maxSize int64, maxFiles int) (*Log, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "audit-test")
+	assert.NilError(t, err)
+	path := filepath.Join(dir, "audit.log")
+	l, err := New(path, maxSize, maxFiles)
+	assert.NilError(t, err)
+	return l, path, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLogRotate(t *testing.T) {
+	l, path, cleanup := newTestLog(t, 300, 3)
+	defer cleanup()
+
+	for i := 0; i < 10; i++ {
+		assert.NilError(t, l.Log(audit.Entry{Time: time.Unix(int64(i), 0), Action: audit.RequestAction, Endpoint: "/containers/create"}))
+	}
+
+	fi, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Check(t, fi.Size() <= 300)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+	_, err = os.Stat(path + ".2")
+	assert.NilError(t, err)
+	_, err = os.Stat(path + ".3")
+	assert.Check(t, os.IsNotExist(err))
+
+	// The oldest entries were dropped with the oldest file, the others are
+	// returned in order.
+	entries, err := l.Query(time.Time{}, time.Time{}, filters.NewArgs())
+	assert.NilError(t, err)
+	assert.Assert(t, len(entries) > 0 && len(entries) < 10)
+	for i, e := range entries {
+		assert.Check(t, is.Equal(e.Time.Unix(), int64(10-len(entries)+i)))
+	}
+}
+
+func TestLogQuery(t *testing.T) {
+	l, _, cleanup := newTestLog(t, 0, 1)
+	defer cleanup()
+
+	uid := 1000
+	exitCode := 0
+	for _, e := range []audit.Entry{
+		{Time: time.Unix(1, 0), Action: audit.RequestAction, Caller: audit.Caller{CommonName: "alice"}, Container: "web"},
+		{Time: time.Unix(2, 0), Action: audit.RequestAction, Caller: audit.Caller{UID: &uid}, Container: "0123456789ab", ExecID: "abcdef", Command: []string{"sh"}},
+		{Time: time.Unix(3, 0), Action: audit.ExecDieAction, Caller: audit.Caller{UID: &uid}, Container: "0123456789ab", ExecID: "abcdef", ExitCode: &exitCode},
+	} {
+		assert.NilError(t, l.Log(e))
+	}
+
+	for _, tc := range []struct {
+		since, until int64
+		filters      filters.Args
+		expected     []int64
+	}{
+		{filters: filters.NewArgs(), expected: []int64{1, 2, 3}},
+		{since: 2, filters: filters.NewArgs(), expected: []int64{2, 3}},
+		{until: 1, filters: filters.NewArgs(), expected: []int64{1}},
+		{filters: filters.NewArgs(filters.Arg("user", "alice")), expected: []int64{1}},
+		{filters: filters.NewArgs(filters.Arg("user", "1000")), expected: []int64{2, 3}},
+		{filters: filters.NewArgs(filters.Arg("container", "0123")), expected: []int64{2, 3}},
+		{filters: filters.NewArgs(filters.Arg("exec", "abcdef"), filters.Arg("action", audit.ExecDieAction)), expected: []int64{3}},
+	} {
+		var since, until time.Time
+		if tc.since != 0 {
+			since = time.Unix(tc.since, 0)
+		}
+		if tc.until != 0 {
+			until = time.Unix(tc.until, 0)
+		}
+		entries, err := l.Query(since, until, tc.filters)
+		assert.NilError(t, err)
+		var times []int64
+		for _, e := range entries {
+			times = append(times, e.Time.Unix())
+		}
+		assert.Check(t, is.DeepEqual(times, tc.expected), "filters: %v", tc.filters)
+	}
+
+	_, err := l.Query(time.Time{}, time.Time{}, filters.NewArgs(filters.Arg("invalid", "foo")))
+	assert.Check(t, is.ErrorContains(err, "Invalid filter"))
+}
+
+func TestLogQueryMaxEntries(t *testing.T) {
+	defer func(max int) { maxQueryEntries = max }(maxQueryEntries)
+	maxQueryEntries = 3
+
+	l, _, cleanup := newTestLog(t, 300, 3)
+	defer cleanup()
+
+	for i := 0; i < 10; i++ {
+		assert.NilError(t, l.Log(audit.Entry{Time: time.Unix(int64(i), 0), Action: audit.RequestAction}))
+	}
+
+	// The most recent entries are returned.
+	entries, err := l.Query(time.Time{}, time.Time{}, filters.NewArgs())
+	assert.NilError(t, err)
+	var times []int64
+	for _, e := range entries {
+		times = append(times, e.Time.Unix())
+	}
+	assert.Check(t, is.DeepEqual(times, []int64{7, 8, 9}))
+}
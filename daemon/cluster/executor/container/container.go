@@ -197,6 +197,8 @@ func (c *containerConfig) config() *enginecontainer.Config {
 	genericEnvs := genericresource.EnvFormat(c.task.AssignedGenericResources, "DOCKER_RESOURCE")
 	env := append(c.spec().Env, genericEnvs...)
 
+	// The ContainerSpec of services has no StopSequence nor PreStop, the
+	// containers of services are stopped with their StopSignal only.
 	config := &enginecontainer.Config{
 		Labels:       c.labels(),
 		StopSignal:   c.spec().StopSignal,
@@ -9,8 +9,11 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/builder/dockerfile"
+	containerpkg "github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/layer"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // merge merges two Config, the image container configuration (defaults values),
@@ -110,6 +113,12 @@ func merge(userConf, imageConf *containertypes.Config) error {
 	if userConf.StopSignal == "" {
 		userConf.StopSignal = imageConf.StopSignal
 	}
+	if len(userConf.StopSequence) == 0 {
+		userConf.StopSequence = imageConf.StopSequence
+	}
+	if userConf.PreStop == nil {
+		userConf.PreStop = imageConf.PreStop
+	}
 	return nil
 }
 
@@ -118,6 +127,9 @@ func merge(userConf, imageConf *containertypes.Config) error {
 // applying that config over the existing container config.
 func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateImageConfig) (string, error) {
 	start := time.Now()
+	if c.Incremental && !c.Snapshot {
+		return "", errdefs.InvalidParameter(errors.New("incremental commits must be made from a snapshot"))
+	}
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return "", err
@@ -138,7 +150,33 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		return "", errdefs.Conflict(err)
 	}
 
-	if c.Pause && !container.IsPaused() {
+	parentImageID := string(container.ImageID)
+	var snapshot, since string
+	if c.Snapshot {
+		prev := container.LastSnapshotCommit
+		snapshot, err = container.RWLayer.Snapshot()
+		if err != nil {
+			if err == layer.ErrSnapshotNotSupported {
+				return "", errdefs.NotImplemented(errors.Wrapf(err, "cannot commit container %s from a snapshot", container.ID))
+			}
+			return "", err
+		}
+		defer func() {
+			if snapshot != "" {
+				if err := container.RWLayer.RemoveSnapshot(snapshot); err != nil {
+					logrus.WithError(err).WithField("container", container.ID).Error("Error removing snapshot")
+				}
+			}
+		}()
+		// Incremental commits only add the changes since the last snapshot
+		// commit, on top of the image it created, if it still exists.
+		if c.Incremental && prev != nil {
+			if _, err := daemon.imageService.GetImage(string(prev.ImageID)); err == nil {
+				since = prev.SnapshotID
+				parentImageID = string(prev.ImageID)
+			}
+		}
+	} else if c.Pause && !container.IsPaused() {
 		daemon.containerPause(container)
 		defer daemon.containerUnpause(container)
 	}
@@ -162,11 +200,17 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		ContainerID:         container.ID,
 		ContainerMountLabel: container.MountLabel,
 		ContainerOS:         container.OS,
-		ParentImageID:       string(container.ImageID),
+		ParentImageID:       parentImageID,
+		Snapshot:            snapshot,
+		SnapshotSince:       since,
 	})
 	if err != nil {
 		return "", err
 	}
+	if snapshot != "" {
+		daemon.setLastSnapshotCommit(container, &containerpkg.SnapshotCommit{SnapshotID: snapshot, ImageID: id})
+		snapshot = ""
+	}
 
 	var imageRef string
 	if c.Repo != "" {
@@ -183,3 +227,21 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 	containerActions.WithValues("commit").UpdateSince(start)
 	return id.String(), nil
 }
+
+// setLastSnapshotCommit records the last snapshot commit of the container, and
+// removes the snapshot of the previous one, which is not needed anymore.
+func (daemon *Daemon) setLastSnapshotCommit(container *containerpkg.Container, commit *containerpkg.SnapshotCommit) {
+	container.Lock()
+	prev := container.LastSnapshotCommit
+	container.LastSnapshotCommit = commit
+	if err := container.CheckpointTo(daemon.containersReplica); err != nil {
+		logrus.WithError(err).WithField("container", container.ID).Error("Error saving last snapshot commit")
+	}
+	container.Unlock()
+
+	if prev != nil {
+		if err := container.RWLayer.RemoveSnapshot(prev.SnapshotID); err != nil && err != layer.ErrSnapshotDoesNotExist {
+			logrus.WithError(err).WithField("container", container.ID).Error("Error removing snapshot")
+		}
+	}
+}
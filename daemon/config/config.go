@@ -36,6 +36,11 @@ const (
 	StockRuntimeName = "runc"
 	// DefaultShmSize is the default value for container's shm size
 	DefaultShmSize = int64(67108864)
+	// DefaultAuditLogMaxSize is the default size of the audit log above
+	// which it is rotated
+	DefaultAuditLogMaxSize = int64(100 * 1024 * 1024)
+	// DefaultAuditLogMaxFiles is the default number of audit log files kept
+	DefaultAuditLogMaxFiles = 5
 	// DefaultNetworkMtu is the default value for network MTU
 	DefaultNetworkMtu = 1500
 	// DisableNetworkBridge is the default value of the option to disable network bridge
@@ -172,6 +177,30 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// PushCompression is the compression applied to image layers when they
+	// are pushed to a registry. Either "gzip" (default) or "zstd". Images
+	// with zstd compressed layers are pushed with OCI image manifests.
+	PushCompression string `json:"push-compression,omitempty"`
+
+	// ImageGCHighThreshold is the disk usage of the data-root filesystem,
+	// in percent, above which unused images are garbage collected, least
+	// recently used first. Garbage collection is disabled if it is 0.
+	ImageGCHighThreshold int `json:"image-gc-high-threshold,omitempty"`
+
+	// ImageGCLowThreshold is the disk usage of the data-root filesystem,
+	// in percent, to which image garbage collection frees space.
+	ImageGCLowThreshold int `json:"image-gc-low-threshold,omitempty"`
+
+	// AuditLog is the path of the audit log, recording the API requests
+	// changing the state of the daemon and the exec processes started
+	// through the API. The audit log is disabled if it is empty.
+	AuditLog string `json:"audit-log,omitempty"`
+
+	// AuditLogMaxSize is the size of the audit log above which it is
+	// rotated, and AuditLogMaxFiles the number of files kept.
+	AuditLogMaxSize  opts.MemBytes `json:"audit-log-max-size,omitempty"`
+	AuditLogMaxFiles int           `json:"audit-log-max-files,omitempty"`
+
 	// ShutdownTimeout is the timeout value (in seconds) the daemon will wait for the container
 	// to stop when daemon is being shutdown
 	ShutdownTimeout int `json:"shutdown-timeout,omitempty"`
@@ -534,7 +563,9 @@ func findConfigurationConflicts(config map[string]interface{}, flags *pflag.Flag
 
 // Validate validates some specific configs.
 // such as config.DNS, config.Labels, config.DNSSearch,
-// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads.
+// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads,
+// config.PushCompression, config.ImageGCHighThreshold,
+// config.ImageGCLowThreshold and the audit log settings.
 func Validate(config *Config) error {
 	// validate DNS
 	for _, dns := range config.DNS {
@@ -564,6 +595,29 @@ func Validate(config *Config) error {
 	if config.MaxConcurrentUploads != nil && *config.MaxConcurrentUploads < 0 {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
+	// validate PushCompression
+	switch config.PushCompression {
+	case "", "gzip", "zstd":
+	default:
+		return fmt.Errorf("invalid push compression: %s", config.PushCompression)
+	}
+	// validate image garbage collection thresholds
+	if config.ImageGCHighThreshold < 0 || config.ImageGCHighThreshold > 100 {
+		return fmt.Errorf("invalid image gc high threshold: %d", config.ImageGCHighThreshold)
+	}
+	if config.ImageGCLowThreshold < 0 || config.ImageGCLowThreshold > 100 {
+		return fmt.Errorf("invalid image gc low threshold: %d", config.ImageGCLowThreshold)
+	}
+	if config.ImageGCHighThreshold > 0 && config.ImageGCLowThreshold >= config.ImageGCHighThreshold {
+		return fmt.Errorf("image gc low threshold (%d) must be lower than the high threshold (%d)", config.ImageGCLowThreshold, config.ImageGCHighThreshold)
+	}
+	// validate audit log rotation
+	if config.AuditLogMaxSize < 0 {
+		return fmt.Errorf("invalid audit log max size: %d", config.AuditLogMaxSize)
+	}
+	if config.AuditLogMaxFiles < 0 {
+		return fmt.Errorf("invalid audit log max files: %d", config.AuditLogMaxFiles)
+	}
 
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
@@ -313,6 +313,21 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCHighThreshold: 101,
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCHighThreshold: 80,
+					ImageGCLowThreshold:  80,
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -388,6 +403,14 @@ func TestValidateConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGCHighThreshold: 90,
+					ImageGCLowThreshold:  80,
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -13,6 +13,8 @@ import (
 const (
 	// DefaultCgroupNamespaceMode is the default for a container's CgroupnsMode, if not set otherwise
 	DefaultCgroupNamespaceMode = "host" // TODO: change to private
+	// DefaultCgroupV2NamespaceMode is the default for a container's CgroupnsMode on hosts using cgroup v2
+	DefaultCgroupV2NamespaceMode = "private"
 	// DefaultIpcMode is default for container's IpcMode, if not set otherwise
 	DefaultIpcMode = "private"
 )
@@ -43,6 +45,9 @@ type Config struct {
 	// ResolvConf is the path to the configuration of the host resolver
 	ResolvConf string `json:"resolv-conf,omitempty"`
 	Rootless   bool   `json:"rootless,omitempty"`
+	// VolumeKeyProviders are the paths of the helper programs providing the
+	// keys of encrypted volumes, by key provider name
+	VolumeKeyProviders map[string]string `json:"volume-key-providers,omitempty"`
 }
 
 // BridgeConfig stores all the bridge driver specific
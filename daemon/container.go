@@ -262,6 +262,22 @@ func validateContainerConfig(config *containertypes.Config, platform string) err
 			return err
 		}
 	}
+	for _, step := range config.StopSequence {
+		if _, err := signal.ParseSignal(step.Signal); err != nil {
+			return err
+		}
+		if step.Timeout < 0 {
+			return errors.Errorf("Timeout of %s in StopSequence cannot be negative", step.Signal)
+		}
+	}
+	if hook := config.PreStop; hook != nil {
+		if len(hook.Cmd) == 0 {
+			return errors.New("Cmd in PreStop cannot be empty")
+		}
+		if hook.Timeout != nil && *hook.Timeout <= 0 {
+			return errors.New("Timeout in PreStop must be positive")
+		}
+	}
 	// Validate if Env contains empty variable or not (e.g., ``, `=foo`)
 	for _, env := range config.Env {
 		if _, err := opts.ValidateEnv(env); err != nil {
@@ -302,6 +318,14 @@ func validateHostConfig(hostConfig *containertypes.HostConfig, platform string)
 	if !hostConfig.Isolation.IsValid() {
 		return errors.Errorf("invalid isolation '%s' on %s", hostConfig.Isolation, runtime.GOOS)
 	}
+	for _, dep := range hostConfig.DependsOn {
+		if dep.Container == "" {
+			return errors.New("invalid dependency: the container cannot be empty")
+		}
+		if !dep.Condition.IsValid() {
+			return errors.Errorf("invalid condition '%s' of dependency %s", dep.Condition, dep.Container)
+		}
+	}
 	return nil
 }
 
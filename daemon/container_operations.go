@@ -803,6 +803,19 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 	if err := ep.Join(sb, joinOptions...); err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			daemon.removeNetworkLimits(ep)
+			daemon.removeEgressPolicy(ep)
+		}
+	}()
+
+	if err := daemon.applyNetworkLimits(container, n, ep, sb); err != nil {
+		return err
+	}
+	if err := daemon.applyEgressPolicy(container, n, ep, sb); err != nil {
+		return err
+	}
 
 	if !container.Managed {
 		// add container name/alias to DNS
@@ -895,6 +908,8 @@ func (daemon *Daemon) disconnectFromNetwork(container *container.Container, n li
 		if err != nil {
 			return err
 		}
+		daemon.removeNetworkLimits(ep)
+		daemon.removeEgressPolicy(ep)
 		return ep.Delete(force)
 	}
 
@@ -902,6 +917,8 @@ func (daemon *Daemon) disconnectFromNetwork(container *container.Container, n li
 		return fmt.Errorf("container %s is not connected to network %s", container.ID, n.Name())
 	}
 
+	daemon.removeNetworkLimits(ep)
+	daemon.removeEgressPolicy(ep)
 	if err := ep.Leave(sbox); err != nil {
 		return fmt.Errorf("container %s failed to leave network %s: %v", container.ID, n.Name(), err)
 	}
@@ -1024,6 +1041,10 @@ func (daemon *Daemon) releaseNetwork(container *container.Container) {
 		return
 	}
 
+	for _, ep := range sb.Endpoints() {
+		daemon.removeNetworkLimits(ep)
+		daemon.removeEgressPolicy(ep)
+	}
 	if err := sb.Delete(); err != nil {
 		logrus.Errorf("Error deleting sandbox id %s for container %s: %v", sid, container.ID, err)
 	}
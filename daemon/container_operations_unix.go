@@ -90,6 +90,14 @@ func (daemon *Daemon) getPidContainer(container *container.Container) (*containe
 	return container, daemon.checkContainer(container, containerIsRunning, containerIsNotRestarting)
 }
 
+func (daemon *Daemon) getUTSContainer(id string) (*container.Container, error) {
+	container, err := daemon.GetContainer(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot join UTS of a non running container: %s", id)
+	}
+	return container, daemon.checkContainer(container, containerIsRunning, containerIsNotRestarting)
+}
+
 func containerIsRunning(c *container.Container) error {
 	if !c.IsRunning() {
 		return errdefs.Conflict(errors.Errorf("container %s is not running", c.ID))
@@ -25,6 +25,7 @@ type createOpts struct {
 	params                  types.ContainerCreateConfig
 	managed                 bool
 	ignoreImagesArgsEscaped bool
+	debugTarget             string
 }
 
 // CreateManagedContainer creates a container that is managed by a Service
@@ -153,9 +154,16 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 		return nil, errdefs.InvalidParameter(err)
 	}
 
+	for _, dep := range opts.params.HostConfig.DependsOn {
+		if _, err := daemon.GetContainer(dep.Container); err != nil {
+			return nil, errors.Wrapf(err, "cannot get dependency %s", dep.Container)
+		}
+	}
+
 	if container, err = daemon.newContainer(opts.params.Name, os, opts.params.Config, opts.params.HostConfig, imgID, opts.managed); err != nil {
 		return nil, err
 	}
+	container.DebugTarget = opts.debugTarget
 	defer func() {
 		if retErr != nil {
 			if err := daemon.cleanupContainer(container, true, true); err != nil {
@@ -223,6 +231,11 @@ func (daemon *Daemon) create(opts createOpts) (retC *container.Container, retErr
 	if err := daemon.Register(container); err != nil {
 		return nil, err
 	}
+	// The container may be a dependency of existing containers, through its
+	// name, and close a cycle.
+	if cycle := daemon.dependencyCycle(container); cycle != nil {
+		return nil, dependencyCycleError(cycle)
+	}
 	stateCtr.set(container.ID, "stopped")
 	daemon.LogContainerEvent(container, "create")
 	return container, nil
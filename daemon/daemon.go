@@ -33,6 +33,7 @@ import (
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/audit"
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/discovery"
 	"github.com/docker/docker/daemon/events"
@@ -54,8 +55,10 @@ import (
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/libcontainerd"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/locker"
+	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/plugingetter"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/pkg/system"
@@ -115,6 +118,8 @@ type Daemon struct {
 	cluster               Cluster
 	genericResources      []swarm.GenericResource
 	metricsPluginListener net.Listener
+	auditLog              *audit.Log
+	egressLogOnce         sync.Once
 
 	machineMemory uint64
 
@@ -461,6 +466,9 @@ func (daemon *Daemon) restore() error {
 	if err != nil {
 		return fmt.Errorf("Error initializing network controller: %v", err)
 	}
+	daemon.cleanupEndpointChains()
+	daemon.cleanupNetworkLimitIfbs()
+	daemon.restoreEgressLog(containers)
 
 	// Now that all the containers are registered, register the links
 	for _, c := range containers {
@@ -478,35 +486,58 @@ func (daemon *Daemon) restore() error {
 	}
 	group.Wait()
 
-	for c, notifier := range restartContainers {
-		group.Add(1)
-		go func(c *container.Container, chNotify chan struct{}) {
-			_ = sem.Acquire(context.Background(), 1)
-			logrus.Debugf("Starting container %s", c.ID)
-
-			// ignore errors here as this is a best effort to wait for children to be
-			//   running before we try to start the container
-			children := daemon.children(c)
-			timeout := time.NewTimer(5 * time.Second)
-			defer timeout.Stop()
-
-			for _, child := range children {
-				if notifier, exists := restartContainers[child]; exists {
-					select {
-					case <-notifier:
-					case <-timeout.C:
-					}
+	startContainer := func(c *container.Container, chNotify chan struct{}) {
+		_ = sem.Acquire(context.Background(), 1)
+		logrus.Debugf("Starting container %s", c.ID)
+
+		// ignore errors here as this is a best effort to wait for children to be
+		//   running before we try to start the container
+		children := daemon.children(c)
+		timeout := time.NewTimer(5 * time.Second)
+		defer timeout.Stop()
+
+		for _, child := range children {
+			if notifier, exists := restartContainers[child]; exists {
+				select {
+				case <-notifier:
+				case <-timeout.C:
 				}
 			}
+		}
 
-			// Make sure networks are available before starting
-			daemon.waitForNetworks(c)
-			if err := daemon.containerStart(c, "", "", true); err != nil {
-				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
-			}
-			close(chNotify)
+		// Make sure networks are available before starting
+		daemon.waitForNetworks(c)
+		if err := daemon.containerStart(c, "", "", true); err != nil {
+			logrus.Errorf("Failed to start container %s: %s", c.ID, err)
+		}
+		close(chNotify)
 
-			sem.Release(1)
+		sem.Release(1)
+	}
+
+	for c, notifier := range restartContainers {
+		if len(c.HostConfig.DependsOn) > 0 {
+			// Waiting for the dependencies may take up to dependencyTimeout,
+			// so the container is started in the background, without
+			// delaying the start of the daemon.
+			go func(c *container.Container, chNotify chan struct{}) {
+				if err := daemon.waitForDependencies(c, restartContainers); err != nil {
+					logrus.WithError(err).Errorf("Not starting container %s", c.ID)
+					close(chNotify)
+					return
+				}
+				if daemon.IsShuttingDown() {
+					close(chNotify)
+					return
+				}
+				startContainer(c, chNotify)
+			}(c, notifier)
+			continue
+		}
+
+		group.Add(1)
+		go func(c *container.Container, chNotify chan struct{}) {
+			startContainer(c, chNotify)
 			group.Done()
 		}(c, notifier)
 	}
@@ -973,6 +1004,9 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	if err != nil {
 		return nil, err
 	}
+	if err := d.registerVolumeKeyProviders(config); err != nil {
+		return nil, err
+	}
 
 	trustKey, err := loadOrCreateTrustKey(config.TrustKeyPath)
 	if err != nil {
@@ -1031,12 +1065,22 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 
 	d.EventsService = events.New()
 	d.root = config.Root
+	if config.AuditLog != "" {
+		if d.auditLog, err = audit.New(config.AuditLog, config.AuditLogMaxSize.Value(), config.AuditLogMaxFiles); err != nil {
+			return nil, err
+		}
+	}
 	d.idMapping = idMapping
 	d.seccompEnabled = sysInfo.Seccomp
 	d.apparmorEnabled = sysInfo.AppArmor
 
 	d.linkIndex = newLinkIndex()
 
+	pushCompression := archive.Gzip
+	if config.PushCompression == "zstd" {
+		pushCompression = archive.Zstd
+	}
+
 	// TODO: imageStore, distributionMetadataStore, and ReferenceStore are only
 	// used above to run migration. They could be initialized in ImageService
 	// if migration is called from daemon/images. layerStore might move as well.
@@ -1048,6 +1092,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		LayerStores:               layerStores,
 		MaxConcurrentDownloads:    *config.MaxConcurrentDownloads,
 		MaxConcurrentUploads:      *config.MaxConcurrentUploads,
+		PushCompression:           pushCompression,
 		ReferenceStore:            rs,
 		RegistryService:           registryService,
 		TrustKey:                  trustKey,
@@ -1065,6 +1110,14 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	}
 	close(d.startupDone)
 
+	// image garbage collection must only start once all containers have
+	// been restored, so that images used by containers are not removed.
+	d.imageService.StartImageGC(images.ImageGCConfig{
+		Root:          config.Root,
+		HighThreshold: config.ImageGCHighThreshold,
+		LowThreshold:  config.ImageGCLowThreshold,
+	})
+
 	// FIXME: this method never returns an error
 	info, _ := d.SystemInfo()
 
@@ -1081,6 +1134,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	).Set(1)
 	engineCpus.Set(float64(info.NCPU))
 	engineMemory.Set(float64(info.MemTotal))
+	pressureCtr.setDaemon(d)
 
 	gd := ""
 	for os, driver := range d.graphDrivers {
@@ -1126,8 +1180,9 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 
 // ShutdownTimeout returns the timeout (in seconds) before containers are forcibly
 // killed during shutdown. The default timeout can be configured both on the daemon
-// and per container, and the longest timeout will be used. A grace-period of
-// 5 seconds is added to the configured timeout.
+// and per container, and the longest timeout will be used. The timeout of the
+// pre-stop hook of the containers, and a grace-period of 5 seconds are added to
+// the configured timeout.
 //
 // A negative (-1) timeout means "indefinitely", which means that containers
 // are not forcibly killed, and the daemon shuts down after all containers exit.
@@ -1146,6 +1201,7 @@ func (daemon *Daemon) ShutdownTimeout() int {
 		if stopTimeout < 0 {
 			return -1
 		}
+		stopTimeout += c.PreStopTimeout()
 		if stopTimeout+graceTimeout > shutdownTimeout {
 			shutdownTimeout = stopTimeout + graceTimeout
 		}
@@ -1217,6 +1273,12 @@ func (daemon *Daemon) Shutdown() error {
 		daemon.containerdCli.Close()
 	}
 
+	if daemon.auditLog != nil {
+		if err := daemon.auditLog.Close(); err != nil {
+			logrus.Errorf("Error closing audit log: %v", err)
+		}
+	}
+
 	return daemon.cleanupMounts()
 }
 
@@ -1290,6 +1352,13 @@ func prepareTempDir(rootDir string, rootIdentity idtools.Identity) (string, erro
 	var tmpDir string
 	if tmpDir = os.Getenv("DOCKER_TMPDIR"); tmpDir == "" {
 		tmpDir = filepath.Join(rootDir, "tmp")
+		// The subpaths of volumes are mounted in the temporary directory;
+		// unmount the ones left behind, so that the content of the volumes
+		// is not deleted with the directory.
+		if err := mount.RecursiveUnmount(tmpDir + string(filepath.Separator)); err != nil {
+			logrus.WithError(err).Warnf("failed to unmount the mounts in %s, not deleting it", tmpDir)
+			return tmpDir, idtools.MkdirAllAndChown(tmpDir, 0700, rootIdentity)
+		}
 		newName := tmpDir + "-old"
 		if err := os.Rename(tmpDir, newName); err == nil {
 			go func() {
@@ -119,6 +119,28 @@ func getMemoryResources(config containertypes.Resources) *specs.LinuxMemory {
 	return &memory
 }
 
+// adaptResourcesForCgroup2 removes the settings which have no equivalent in
+// cgroup v2 from the resources of a container, if the host uses the cgroup v2
+// unified hierarchy. Containers created on a cgroup v1 host may still have
+// them. The runtime maps the other settings to the interface files of cgroup
+// v2, for example the CPU shares to cpu.weight, and the memory and swap limit
+// to memory.max and memory.swap.max.
+func adaptResourcesForCgroup2(r *specs.LinuxResources) {
+	if !sysinfo.IsCgroup2UnifiedMode() {
+		return
+	}
+	if r.Memory != nil {
+		r.Memory.Kernel = nil
+		r.Memory.KernelTCP = nil
+		r.Memory.Swappiness = nil
+		r.Memory.DisableOOMKiller = nil
+	}
+	if r.CPU != nil {
+		r.CPU.RealtimePeriod = nil
+		r.CPU.RealtimeRuntime = nil
+	}
+}
+
 func getPidsLimit(config containertypes.Resources) *specs.LinuxPids {
 	if config.PidsLimit == nil {
 		return nil
@@ -657,6 +679,30 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 	if hostConfig.NetworkMode.IsHost() && len(hostConfig.PortBindings) > 0 {
 		warnings = append(warnings, "Published ports are discarded when using host network mode")
 	}
+	if hostConfig.NetworkIngressRate > 0 || hostConfig.NetworkEgressRate > 0 || hostConfig.NetworkConntrackMax > 0 {
+		if hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsNone() || hostConfig.NetworkMode.IsContainer() {
+			warnings = append(warnings, fmt.Sprintf("Network limits are discarded when using %s network mode", hostConfig.NetworkMode.NetworkName()))
+		}
+		if hostConfig.NetworkConntrackMax > 0 && !daemon.configStore.BridgeConfig.EnableIPTables {
+			return warnings, fmt.Errorf("NetworkConntrackMax requires iptables to be enabled")
+		}
+	}
+	if hostConfig.EgressPolicy != nil {
+		if hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsContainer() {
+			return warnings, fmt.Errorf("EgressPolicy is not supported in %s network mode", hostConfig.NetworkMode.NetworkName())
+		}
+		if hostConfig.NetworkMode.IsUserDefined() {
+			if n, err := daemon.FindNetwork(hostConfig.NetworkMode.NetworkName()); err == nil && n.Type() != "bridge" {
+				return warnings, fmt.Errorf("EgressPolicy is not supported on network %s of type %s", n.Name(), n.Type())
+			}
+		}
+		if !daemon.configStore.BridgeConfig.EnableIPTables {
+			return warnings, fmt.Errorf("EgressPolicy requires iptables to be enabled")
+		}
+		if err := validateEgressPolicy(hostConfig.EgressPolicy); err != nil {
+			return warnings, err
+		}
+	}
 
 	// check for various conflicting options with user namespaces
 	if daemon.configStore.RemappedRoot != "" && hostConfig.UsernsMode.IsPrivate() {
@@ -694,6 +740,9 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 	if !hostConfig.CgroupnsMode.Valid() {
 		return warnings, fmt.Errorf("invalid cgroup namespace mode: %v", hostConfig.CgroupnsMode)
 	}
+	if !hostConfig.UTSMode.Valid() {
+		return warnings, fmt.Errorf("invalid UTS namespace mode: %v", hostConfig.UTSMode)
+	}
 	if hostConfig.CgroupnsMode.IsPrivate() {
 		if !sysInfo.CgroupNamespaces {
 			warnings = append(warnings, "Your kernel does not support cgroup namespaces.  Cgroup namespace setting discarded.")
@@ -778,6 +827,9 @@ func verifyDaemonSettings(conf *config.Config) error {
 			return fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+	if sysinfo.IsCgroup2UnifiedMode() && (conf.CPURealtimePeriod != 0 || conf.CPURealtimeRuntime != 0) {
+		return fmt.Errorf("cpu-rt-period and cpu-rt-runtime are not supported on hosts using cgroup v2")
+	}
 
 	if conf.DefaultRuntime == "" {
 		conf.DefaultRuntime = config.StockRuntimeName
@@ -795,6 +847,12 @@ func checkSystem() error {
 	return checkKernel()
 }
 
+// registerVolumeKeyProviders registers the key providers of encrypted volumes
+// configured with --volume-key-provider with the local volume driver.
+func (daemon *Daemon) registerVolumeKeyProviders(config *config.Config) error {
+	return daemon.volumes.RegisterKeyHelpers(config.VolumeKeyProviders)
+}
+
 // configureMaxThreads sets the Go runtime max threads threshold
 // which is 90% of the kernel setting from /proc/sys/kernel/threads-max
 func configureMaxThreads(config *config.Config) error {
@@ -1371,6 +1429,9 @@ func (daemon *Daemon) stats(c *container.Container) (*types.StatsJSON, error) {
 	if !c.IsRunning() {
 		return nil, errNotRunning(c.ID)
 	}
+	if sysinfo.IsCgroup2UnifiedMode() {
+		return daemon.statsV2(c)
+	}
 	cs, err := daemon.containerd.Stats(context.Background(), c.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "container not found") {
@@ -1471,6 +1532,10 @@ func (daemon *Daemon) stats(c *container.Container) (*types.StatsJSON, error) {
 		}
 	}
 
+	if err := readPressureStats(c.GetPID(), &s.Stats); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Debug("Error reading pressure stats of container")
+	}
+
 	return s, nil
 }
 
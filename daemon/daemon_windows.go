@@ -186,6 +186,15 @@ func verifyPlatformContainerResources(resources *containertypes.Resources, isHyp
 	if len(resources.Ulimits) != 0 {
 		return warnings, fmt.Errorf("invalid option: Windows does not support Ulimits")
 	}
+	if resources.NetworkIngressRate != 0 {
+		return warnings, fmt.Errorf("invalid option: Windows does not support NetworkIngressRate")
+	}
+	if resources.NetworkEgressRate != 0 {
+		return warnings, fmt.Errorf("invalid option: Windows does not support NetworkEgressRate")
+	}
+	if resources.NetworkConntrackMax != 0 {
+		return warnings, fmt.Errorf("invalid option: Windows does not support NetworkConntrackMax")
+	}
 	return warnings, nil
 }
 
@@ -206,6 +215,10 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, fmt.Errorf("Windows client operating systems earlier than version 1809 can only run Hyper-V containers")
 	}
 
+	if hostConfig.EgressPolicy != nil {
+		return warnings, fmt.Errorf("invalid option: Windows does not support EgressPolicy")
+	}
+
 	w, err := verifyPlatformContainerResources(&hostConfig.Resources, hyperv)
 	warnings = append(warnings, w...)
 	return warnings, err
@@ -269,6 +282,10 @@ func configureMaxThreads(config *config.Config) error {
 	return nil
 }
 
+func (daemon *Daemon) registerVolumeKeyProviders(config *config.Config) error {
+	return nil
+}
+
 func (daemon *Daemon) initNetworkController(config *config.Config, activeSandboxes map[string]interface{}) (libnetwork.NetworkController, error) {
 	netOptions, err := daemon.networkOptions(config, nil, nil)
 	if err != nil {
@@ -0,0 +1,23 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/sirupsen/logrus"
+)
+
+// removeDebugContainers removes the debug containers of target, when it stops.
+func (daemon *Daemon) removeDebugContainers(target *container.Container) {
+	for _, c := range daemon.containers.List() {
+		if c.DebugTarget != target.ID {
+			continue
+		}
+		go func(id string) {
+			err := daemon.ContainerRm(id, &types.ContainerRmConfig{ForceRemove: true, RemoveVolume: true})
+			if err != nil && !errdefs.IsNotFound(err) {
+				logrus.WithError(err).WithField("container", id).Debug("Error removing debug container")
+			}
+		}(c.ID)
+	}
+}
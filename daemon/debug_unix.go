@@ -0,0 +1,117 @@
+// +build linux freebsd
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// debugTargetPath is the path at which the root filesystem of the debugged
+// container is mounted in debug containers.
+const debugTargetPath = "/target"
+
+// ContainerDebug creates a debug container for the running container name. The
+// debug container runs the given image, and shares the pid, network, ipc and
+// uts namespaces of the debugged container, whose root filesystem is mounted at
+// /target. It is started like other containers, and is removed when it exits,
+// or when the debugged container stops.
+func (daemon *Daemon) ContainerDebug(name string, config *types.ContainerDebugConfig) (containertypes.ContainerCreateCreatedBody, error) {
+	if config.Image == "" {
+		return containertypes.ContainerCreateCreatedBody{}, errdefs.InvalidParameter(errors.New("an image is required to debug a container"))
+	}
+
+	target, err := daemon.getDebugTarget(name)
+	if err != nil {
+		return containertypes.ContainerCreateCreatedBody{}, err
+	}
+	if target.DebugTarget != "" {
+		return containertypes.ContainerCreateCreatedBody{}, errdefs.InvalidParameter(errors.Errorf("container %s is a debug container", target.ID))
+	}
+
+	hostConfig, warnings := debugHostConfig(target)
+	hostConfig.Privileged = config.Privileged
+	hostConfig.CapAdd = config.CapAdd
+
+	resp, err := daemon.containerCreate(createOpts{
+		params: types.ContainerCreateConfig{
+			Config: &containertypes.Config{
+				Image:        config.Image,
+				Cmd:          config.Cmd,
+				Env:          config.Env,
+				User:         config.User,
+				WorkingDir:   config.WorkingDir,
+				Tty:          config.Tty,
+				OpenStdin:    config.OpenStdin,
+				StdinOnce:    config.OpenStdin,
+				AttachStdin:  config.OpenStdin,
+				AttachStdout: true,
+				AttachStderr: true,
+			},
+			HostConfig: hostConfig,
+		},
+		debugTarget: target.ID,
+	})
+	resp.Warnings = append(resp.Warnings, warnings...)
+	if err != nil {
+		return resp, err
+	}
+
+	daemon.LogContainerEventWithAttributes(target, "debug", map[string]string{
+		"debugContainer": resp.ID,
+	})
+	return resp, nil
+}
+
+// getDebugTarget looks up the container name to debug, which must be running.
+func (daemon *Daemon) getDebugTarget(name string) (*container.Container, error) {
+	c, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := daemon.checkContainer(c, containerIsRunning, containerIsNotRestarting); err != nil {
+		return nil, errors.Wrap(err, "cannot debug container "+c.ID)
+	}
+	if c.BaseFS == nil {
+		return nil, errdefs.Conflict(errors.Errorf("cannot debug container %s: its root filesystem is not mounted", c.ID))
+	}
+	return c, nil
+}
+
+// debugHostConfig returns the host configuration of a debug container for
+// target, which joins its namespaces, and warnings for the namespaces of target
+// which cannot be joined.
+func debugHostConfig(target *container.Container) (*containertypes.HostConfig, []string) {
+	var warnings []string
+	joinTarget := "container:" + target.ID
+	hostConfig := &containertypes.HostConfig{
+		AutoRemove: true,
+		PidMode:    containertypes.PidMode(joinTarget),
+		UTSMode:    containertypes.UTSMode(joinTarget),
+		UsernsMode: target.HostConfig.UsernsMode,
+		Runtime:    target.HostConfig.Runtime,
+	}
+	if target.HostConfig.UTSMode.IsHost() {
+		hostConfig.UTSMode = "host"
+	}
+
+	switch networkMode := target.HostConfig.NetworkMode; {
+	case networkMode.IsHost(), networkMode.IsContainer():
+		hostConfig.NetworkMode = networkMode
+	default:
+		hostConfig.NetworkMode = containertypes.NetworkMode(joinTarget)
+	}
+
+	switch ipcMode := target.HostConfig.IpcMode; {
+	case ipcMode.IsHost(), ipcMode.IsContainer():
+		hostConfig.IpcMode = ipcMode
+	case ipcMode.IsShareable():
+		hostConfig.IpcMode = containertypes.IpcMode(joinTarget)
+	default:
+		warnings = append(warnings, "The IPC namespace of the container is not shareable, the debug container uses its own IPC namespace")
+	}
+	return hostConfig, warnings
+}
@@ -0,0 +1,42 @@
+// +build linux freebsd
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDebugHostConfig(t *testing.T) {
+	target := &container.Container{
+		ID: "target",
+		HostConfig: &containertypes.HostConfig{
+			NetworkMode: "bridge",
+			IpcMode:     "shareable",
+			Runtime:     "runc",
+		},
+	}
+	hostConfig, warnings := debugHostConfig(target)
+	assert.Check(t, is.Len(warnings, 0))
+	assert.Check(t, hostConfig.AutoRemove)
+	assert.Check(t, is.Equal(hostConfig.PidMode, containertypes.PidMode("container:target")))
+	assert.Check(t, is.Equal(hostConfig.UTSMode, containertypes.UTSMode("container:target")))
+	assert.Check(t, is.Equal(hostConfig.NetworkMode, containertypes.NetworkMode("container:target")))
+	assert.Check(t, is.Equal(hostConfig.IpcMode, containertypes.IpcMode("container:target")))
+	assert.Check(t, is.Equal(hostConfig.Runtime, "runc"))
+
+	target.HostConfig = &containertypes.HostConfig{
+		NetworkMode: "host",
+		IpcMode:     "private",
+		UTSMode:     "host",
+	}
+	hostConfig, warnings = debugHostConfig(target)
+	assert.Check(t, is.Len(warnings, 1))
+	assert.Check(t, is.Equal(hostConfig.UTSMode, containertypes.UTSMode("host")))
+	assert.Check(t, is.Equal(hostConfig.NetworkMode, containertypes.NetworkMode("host")))
+	assert.Check(t, is.Equal(hostConfig.IpcMode, containertypes.IpcMode("")))
+}
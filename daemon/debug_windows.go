@@ -0,0 +1,13 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// ContainerDebug is not supported on Windows.
+func (daemon *Daemon) ContainerDebug(name string, config *types.ContainerDebugConfig) (containertypes.ContainerCreateCreatedBody, error) {
+	return containertypes.ContainerCreateCreatedBody{}, errdefs.NotImplemented(errors.New("debug containers are not supported on Windows"))
+}
@@ -0,0 +1,163 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dependencyTimeout is the time given to the dependencies of a container
+	// to meet their condition when the daemon restarts the container.
+	dependencyTimeout = 5 * time.Minute
+
+	// dependencyPollInterval is the interval at which the health of the
+	// dependencies is checked while waiting for them.
+	dependencyPollInterval = 500 * time.Millisecond
+)
+
+// dependencyCycle returns the names of the containers forming a cycle in the
+// dependencies of the container, if any.
+func (daemon *Daemon) dependencyCycle(c *container.Container) []string {
+	var (
+		path    []*container.Container
+		visited = make(map[string]bool)
+		visit   func(c *container.Container) []string
+	)
+	visit = func(c *container.Container) []string {
+		for i, p := range path {
+			if p.ID == c.ID {
+				var cycle []string
+				for _, p := range append(path[i:], c) {
+					cycle = append(cycle, strings.TrimPrefix(p.Name, "/"))
+				}
+				return cycle
+			}
+		}
+		if visited[c.ID] {
+			return nil
+		}
+		visited[c.ID] = true
+
+		path = append(path, c)
+		for _, dep := range c.HostConfig.DependsOn {
+			d, err := daemon.GetContainer(dep.Container)
+			if err != nil {
+				continue
+			}
+			if cycle := visit(d); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		return nil
+	}
+	return visit(c)
+}
+
+func dependencyCycleError(cycle []string) error {
+	return errdefs.InvalidParameter(errors.Errorf("dependency cycle between containers: %s", strings.Join(cycle, " -> ")))
+}
+
+// checkDependencies returns an error if a dependency of the container does
+// not meet its condition.
+func (daemon *Daemon) checkDependencies(c *container.Container) error {
+	if len(c.HostConfig.DependsOn) == 0 {
+		return nil
+	}
+	if cycle := daemon.dependencyCycle(c); cycle != nil {
+		return dependencyCycleError(cycle)
+	}
+	for _, dep := range c.HostConfig.DependsOn {
+		d, err := daemon.GetContainer(dep.Container)
+		if err != nil {
+			return errdefs.Conflict(errors.Wrapf(err, "cannot get dependency %s", dep.Container))
+		}
+		if err := dependencyMet(d, dep.Condition); err != nil {
+			return errdefs.Conflict(err)
+		}
+	}
+	return nil
+}
+
+// dependencyMet returns an error if the container does not meet the
+// condition.
+func dependencyMet(d *container.Container, condition containertypes.DependencyCondition) error {
+	d.Lock()
+	defer d.Unlock()
+
+	name := strings.TrimPrefix(d.Name, "/")
+	switch condition {
+	case containertypes.DependencyHealthy:
+		if !d.Running || d.Health == nil || d.Health.Status() != types.Healthy {
+			return errors.Errorf("dependency %s is not healthy", name)
+		}
+	case containertypes.DependencyCompletedSuccessfully:
+		if d.Running || d.FinishedAt.IsZero() || d.ExitCode() != 0 {
+			return errors.Errorf("dependency %s did not complete successfully", name)
+		}
+	default:
+		if !d.Running {
+			return errors.Errorf("dependency %s is not running", name)
+		}
+	}
+	return nil
+}
+
+// waitForDependencies waits for the dependencies of the container to meet
+// their condition, when the daemon restarts the container. The dependencies
+// being restarted too are waited for first, through their notifier.
+func (daemon *Daemon) waitForDependencies(c *container.Container, restarting map[*container.Container]chan struct{}) error {
+	if len(c.HostConfig.DependsOn) == 0 {
+		return nil
+	}
+	if cycle := daemon.dependencyCycle(c); cycle != nil {
+		return dependencyCycleError(cycle)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyTimeout)
+	defer cancel()
+
+	for _, dep := range c.HostConfig.DependsOn {
+		d, err := daemon.GetContainer(dep.Container)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get dependency %s", dep.Container)
+		}
+		if err := waitForDependency(ctx, d, dep.Condition, restarting[d]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitForDependency(ctx context.Context, d *container.Container, condition containertypes.DependencyCondition, started chan struct{}) error {
+	if started != nil {
+		select {
+		case <-started:
+		case <-ctx.Done():
+			return errors.Errorf("timeout waiting for dependency %s to start", strings.TrimPrefix(d.Name, "/"))
+		}
+	}
+
+	switch condition {
+	case containertypes.DependencyCompletedSuccessfully:
+		<-d.Wait(ctx, container.WaitConditionNotRunning)
+	case containertypes.DependencyHealthy:
+		ticker := time.NewTicker(dependencyPollInterval)
+		defer ticker.Stop()
+		for dependencyMet(d, condition) != nil && d.IsRunning() {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return errors.Errorf("timeout waiting for dependency %s to be healthy", strings.TrimPrefix(d.Name, "/"))
+			}
+		}
+	}
+	return dependencyMet(d, condition)
+}
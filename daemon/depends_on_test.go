@@ -0,0 +1,78 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/truncindex"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newDependencyTestDaemon(t *testing.T, containers ...*container.Container) *Daemon {
+	containersReplica, err := container.NewViewDB()
+	assert.NilError(t, err)
+
+	daemon := &Daemon{
+		containers:        container.NewMemoryStore(),
+		containersReplica: containersReplica,
+		idIndex:           truncindex.NewTruncIndex([]string{}),
+	}
+	for _, c := range containers {
+		daemon.containers.Add(c.ID, c)
+		assert.NilError(t, daemon.idIndex.Add(c.ID))
+		_, err := daemon.reserveName(c.ID, c.Name)
+		assert.NilError(t, err)
+	}
+	return daemon
+}
+
+func newDependencyTestContainer(id, name string, deps ...string) *container.Container {
+	c := container.NewBaseContainer(id, "")
+	c.Name = "/" + name
+	c.HostConfig = &containertypes.HostConfig{}
+	for _, dep := range deps {
+		c.HostConfig.DependsOn = append(c.HostConfig.DependsOn, containertypes.Dependency{Container: dep})
+	}
+	return c
+}
+
+func TestDependencyCycle(t *testing.T) {
+	a := newDependencyTestContainer("aaaaaaaaaaaa", "web", "api")
+	b := newDependencyTestContainer("bbbbbbbbbbbb", "api", "db", "cache")
+	c := newDependencyTestContainer("cccccccccccc", "db")
+	d := newDependencyTestContainer("dddddddddddd", "cache", "web")
+	e := newDependencyTestContainer("eeeeeeeeeeee", "worker", "db", "missing")
+	daemon := newDependencyTestDaemon(t, a, b, c, d, e)
+
+	assert.Check(t, is.DeepEqual(daemon.dependencyCycle(a), []string{"web", "api", "cache", "web"}))
+	assert.Check(t, is.DeepEqual(daemon.dependencyCycle(b), []string{"api", "cache", "web", "api"}))
+	assert.Check(t, is.Nil(daemon.dependencyCycle(c)))
+	assert.Check(t, is.Nil(daemon.dependencyCycle(e)))
+
+	err := dependencyCycleError(daemon.dependencyCycle(a))
+	assert.Check(t, is.Error(err, "dependency cycle between containers: web -> api -> cache -> web"))
+}
+
+func TestDependencyMet(t *testing.T) {
+	c := newDependencyTestContainer("cccccccccccc", "db")
+
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyStarted), "dependency db is not running"))
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyCompletedSuccessfully), "dependency db did not complete successfully"))
+
+	c.SetRunning(42, true)
+	assert.Check(t, dependencyMet(c, containertypes.DependencyStarted))
+	assert.Check(t, dependencyMet(c, ""))
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyHealthy), "dependency db is not healthy"))
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyCompletedSuccessfully), "dependency db did not complete successfully"))
+
+	c.SetStopped(&container.ExitStatus{ExitCode: 1, ExitedAt: time.Now()})
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyCompletedSuccessfully), "dependency db did not complete successfully"))
+
+	c.SetRunning(42, false)
+	c.SetStopped(&container.ExitStatus{ExitCode: 0, ExitedAt: time.Now()})
+	assert.Check(t, dependencyMet(c, containertypes.DependencyCompletedSuccessfully))
+	assert.Check(t, is.Error(dependencyMet(c, containertypes.DependencyStarted), "dependency db is not running"))
+}
@@ -0,0 +1,198 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// egressLogGroup is the nflog group the connections denied by the egress
+	// policies are logged to.
+	egressLogGroup = 2375
+
+	// egressLogPrefix is the prefix of the nflog messages of the denied
+	// connections, followed by the truncated ID of the container.
+	egressLogPrefix = "docker-egress:"
+
+	// egressLogCopyRange is the number of bytes of the denied packets copied
+	// to the nflog messages, enough for the IP header and the ports.
+	egressLogCopyRange = 128
+)
+
+// Constants of the nfnetlink_log subsystem, from linux/netfilter/nfnetlink_log.h.
+const (
+	nfnlSubsysULOG      = 4
+	nfulnlMsgPacket     = 0
+	nfulnlMsgConfig     = 1
+	nfulaPayload        = 9
+	nfulaPrefix         = 10
+	nfulaCfgCmd         = 1
+	nfulaCfgMode        = 2
+	nfulnlCfgCmdBind    = 1
+	nfulnlCopyPacket    = 2
+	nfnetlinkV0         = 0
+	nlaTypeMask         = ^uint16(unix.NLA_F_NESTED | unix.NLA_F_NET_BYTEORDER)
+	egressLogPacketType = nfnlSubsysULOG<<8 | nfulnlMsgPacket
+)
+
+// deniedConnection is a connection denied by an egress policy.
+type deniedConnection struct {
+	container   string
+	destination string
+	protocol    string
+	port        int
+}
+
+// startEgressLog starts listening for the connections denied by the egress
+// policies, which are reported as container events. It is started once, when
+// an egress policy is first applied.
+func (daemon *Daemon) startEgressLog() {
+	daemon.egressLogOnce.Do(func() {
+		s, err := bindNflogGroup(egressLogGroup)
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to listen for the connections denied by the egress policies")
+			return
+		}
+		go daemon.watchEgressLog(s)
+	})
+}
+
+func (daemon *Daemon) watchEgressLog(s *nl.NetlinkSocket) {
+	defer s.Close()
+	for {
+		msgs, err := s.Receive()
+		if err != nil {
+			if err == unix.EINTR || err == unix.ENOBUFS {
+				continue
+			}
+			logrus.WithError(err).Warn("Stopped listening for the connections denied by the egress policies")
+			return
+		}
+		for _, m := range msgs {
+			if m.Header.Type != egressLogPacketType {
+				continue
+			}
+			conn, ok := parseEgressLogMessage(m.Data)
+			if !ok {
+				continue
+			}
+			c, err := daemon.GetContainer(conn.container)
+			if err != nil {
+				continue
+			}
+			attributes := map[string]string{
+				"destination": conn.destination,
+				"protocol":    conn.protocol,
+			}
+			if conn.port != 0 {
+				attributes["port"] = strconv.Itoa(conn.port)
+			}
+			daemon.LogContainerEventWithAttributes(c, "egress_denied", attributes)
+		}
+	}
+}
+
+// bindNflogGroup returns a netlink socket receiving the packets logged to the
+// nflog group.
+func bindNflogGroup(group uint16) (*nl.NetlinkSocket, error) {
+	s, err := nl.Subscribe(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := make([]byte, 6)
+	binary.BigEndian.PutUint32(mode, egressLogCopyRange)
+	mode[4] = nfulnlCopyPacket
+	for _, attr := range []*nl.RtAttr{
+		nl.NewRtAttr(nfulaCfgCmd, []byte{nfulnlCfgCmdBind}),
+		nl.NewRtAttr(nfulaCfgMode, mode),
+	} {
+		req := nl.NewNetlinkRequest(nfnlSubsysULOG<<8|nfulnlMsgConfig, unix.NLM_F_ACK)
+		req.AddData(&nl.Nfgenmsg{NfgenFamily: unix.AF_UNSPEC, Version: nfnetlinkV0, ResId: nl.Swap16(group)})
+		req.AddData(attr)
+		if err := s.Send(req); err != nil {
+			s.Close()
+			return nil, err
+		}
+		if err := receiveAck(s, req.Seq); err != nil {
+			s.Close()
+			return nil, errors.Wrapf(err, "failed to bind nflog group %d", group)
+		}
+	}
+	return s, nil
+}
+
+func receiveAck(s *nl.NetlinkSocket, seq uint32) error {
+	for {
+		msgs, err := s.Receive()
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != seq || m.Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			if len(m.Data) < 4 {
+				return errors.New("short netlink acknowledgement")
+			}
+			if errno := int32(nl.NativeEndian().Uint32(m.Data[0:4])); errno != 0 {
+				return syscall.Errno(-errno)
+			}
+			return nil
+		}
+	}
+}
+
+// parseEgressLogMessage parses the nflog message of a denied connection,
+// whose payload is the first bytes of the IPv4 packet.
+func parseEgressLogMessage(b []byte) (deniedConnection, bool) {
+	var conn deniedConnection
+	if len(b) < nl.SizeofNfgenmsg {
+		return conn, false
+	}
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return conn, false
+	}
+	var payload []byte
+	for _, a := range attrs {
+		switch a.Attr.Type & nlaTypeMask {
+		case nfulaPrefix:
+			if prefix := strings.TrimRight(string(a.Value), "\x00"); strings.HasPrefix(prefix, egressLogPrefix) {
+				conn.container = strings.TrimPrefix(prefix, egressLogPrefix)
+			}
+		case nfulaPayload:
+			payload = a.Value
+		}
+	}
+	if conn.container == "" || len(payload) < 20 || payload[0]>>4 != 4 {
+		return conn, false
+	}
+
+	conn.destination = net.IP(payload[16:20]).String()
+	hlen := int(payload[0]&0x0f) * 4
+	switch proto := payload[9]; proto {
+	case unix.IPPROTO_TCP:
+		conn.protocol = "tcp"
+	case unix.IPPROTO_UDP:
+		conn.protocol = "udp"
+	case unix.IPPROTO_SCTP:
+		conn.protocol = "sctp"
+	case unix.IPPROTO_ICMP:
+		conn.protocol = "icmp"
+	default:
+		conn.protocol = strconv.Itoa(int(proto))
+	}
+	if (conn.protocol == "tcp" || conn.protocol == "udp" || conn.protocol == "sctp") && len(payload) >= hlen+4 {
+		conn.port = int(binary.BigEndian.Uint16(payload[hlen+2 : hlen+4]))
+	}
+	return conn, true
+}
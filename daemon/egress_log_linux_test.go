@@ -0,0 +1,54 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func egressLogMessage(prefix string, payload []byte) []byte {
+	b := (&nl.Nfgenmsg{NfgenFamily: unix.AF_INET}).Serialize()
+	b = append(b, nl.NewRtAttr(nfulaPrefix, nl.ZeroTerminated(prefix)).Serialize()...)
+	return append(b, nl.NewRtAttr(nfulaPayload, payload).Serialize()...)
+}
+
+func ipv4Packet(proto byte, dst [4]byte, transport ...byte) []byte {
+	p := make([]byte, 20)
+	p[0] = 0x45
+	p[9] = proto
+	copy(p[12:16], []byte{172, 17, 0, 2})
+	copy(p[16:20], dst[:])
+	return append(p, transport...)
+}
+
+func TestParseEgressLogMessage(t *testing.T) {
+	tcp := ipv4Packet(unix.IPPROTO_TCP, [4]byte{10, 0, 0, 1}, 0xc3, 0x50, 0x01, 0xbb)
+	conn, ok := parseEgressLogMessage(egressLogMessage(egressLogPrefix+"0123456789ab", tcp))
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(conn, deniedConnection{container: "0123456789ab", destination: "10.0.0.1", protocol: "tcp", port: 443}))
+
+	icmp := ipv4Packet(unix.IPPROTO_ICMP, [4]byte{8, 8, 8, 8}, 8, 0, 0, 0)
+	conn, ok = parseEgressLogMessage(egressLogMessage(egressLogPrefix+"0123456789ab", icmp))
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(conn, deniedConnection{container: "0123456789ab", destination: "8.8.8.8", protocol: "icmp"}))
+
+	_, ok = parseEgressLogMessage(egressLogMessage("other:0123456789ab", tcp))
+	assert.Check(t, !ok)
+	_, ok = parseEgressLogMessage(egressLogMessage(egressLogPrefix+"0123456789ab", tcp[:12]))
+	assert.Check(t, !ok)
+	_, ok = parseEgressLogMessage([]byte{2})
+	assert.Check(t, !ok)
+}
+
+func TestEgressRules(t *testing.T) {
+	assert.Check(t, is.DeepEqual(egressRules("10.0.0.0/8", nil), [][]string{
+		{"-d", "10.0.0.0/8", "-j", "RETURN"},
+	}))
+	assert.Check(t, is.DeepEqual(egressRules("10.0.0.1", []egressPort{{proto: "tcp", start: 443, end: 443}, {proto: "udp", start: 8000, end: 8100}}), [][]string{
+		{"-d", "10.0.0.1", "-p", "tcp", "--dport", "443:443", "-j", "RETURN"},
+		{"-d", "10.0.0.1", "-p", "udp", "--dport", "8000:8100", "-j", "RETURN"},
+	}))
+}
@@ -0,0 +1,96 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+)
+
+// egressResolveTimeout is the maximum time to resolve the DNS name of an
+// egress rule.
+const egressResolveTimeout = 10 * time.Second
+
+// egressPort is a port range of an egress rule.
+type egressPort struct {
+	proto      string
+	start, end int
+}
+
+// parseEgressPorts parses the ports of an egress rule.
+func parseEgressPorts(ports []string) ([]egressPort, error) {
+	var parsed []egressPort
+	for _, p := range ports {
+		proto, rawPort := nat.SplitProtoPort(p)
+		switch strings.ToLower(proto) {
+		case "tcp", "udp", "sctp":
+		default:
+			return nil, errors.Errorf("invalid protocol in port %q", p)
+		}
+		start, end, err := nat.ParsePortRangeToInt(rawPort)
+		if err != nil || start == 0 {
+			return nil, errors.Errorf("invalid port %q", p)
+		}
+		parsed = append(parsed, egressPort{proto: strings.ToLower(proto), start: start, end: end})
+	}
+	return parsed, nil
+}
+
+// parseEgressCIDR parses the IPv4 network or address of an egress rule.
+func parseEgressCIDR(cidr string) (*net.IPNet, error) {
+	s := cidr
+	if !strings.Contains(s, "/") {
+		s += "/32"
+	}
+	_, n, err := net.ParseCIDR(s)
+	if err != nil || n.IP.To4() == nil {
+		return nil, errors.Errorf("invalid IPv4 network %q", cidr)
+	}
+	return n, nil
+}
+
+// resolveEgressHost resolves the DNS name of an egress rule to its IPv4
+// addresses, with the DNS configuration of the daemon.
+func resolveEgressHost(host string) ([]net.IP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), egressResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve %q", host)
+	}
+	var ips []net.IP
+	for _, a := range addrs {
+		if a.IP.To4() != nil {
+			ips = append(ips, a.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, errors.Errorf("no IPv4 address found for %q", host)
+	}
+	return ips, nil
+}
+
+// validateEgressPolicy validates the rules of the egress policy, which must
+// each have either a network or a DNS name. The DNS names are not resolved
+// until the container connects to a network, as the addresses could change in
+// the meantime.
+func validateEgressPolicy(policy *containertypes.EgressPolicy) error {
+	for _, r := range policy.Allow {
+		if (r.CIDR == "") == (r.Host == "") {
+			return errors.New("invalid egress rule: either CIDR or Host must be set")
+		}
+		if r.CIDR != "" {
+			if _, err := parseEgressCIDR(r.CIDR); err != nil {
+				return errors.Wrap(err, "invalid egress rule")
+			}
+		}
+		if _, err := parseEgressPorts(r.Ports); err != nil {
+			return errors.Wrap(err, "invalid egress rule")
+		}
+	}
+	return nil
+}
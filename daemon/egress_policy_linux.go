@@ -0,0 +1,255 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/libnetwork"
+	"github.com/docker/libnetwork/iptables"
+	"github.com/docker/libnetwork/resolvconf"
+	"github.com/docker/libnetwork/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// egressPolicyChainPrefix is the prefix of the iptables chains enforcing
+	// the egress policies of the endpoints, followed by the truncated ID of
+	// the endpoint.
+	egressPolicyChainPrefix = "DOCKER-EGRESS-"
+
+	// egressLogLimit is the maximum rate at which the connections denied on
+	// an endpoint are logged.
+	egressLogLimit = "10/second"
+
+	// bridgeNFCallIPTables is the sysctl making the packets bridged between
+	// the containers of a bridge network go through the iptables chains.
+	bridgeNFCallIPTables = "/proc/sys/net/bridge/bridge-nf-call-iptables"
+)
+
+// egressPolicyParents are the chains jumping to the chains of the egress
+// policies: FORWARD for the connections routed to other hosts, or bridged to
+// the containers of the same network, and INPUT for the connections to the
+// host itself, such as to the gateway of the network or to the ports
+// published by docker-proxy.
+var egressPolicyParents = []string{"FORWARD", "INPUT"}
+
+// applyEgressPolicy installs the iptables chain enforcing the egress policy of
+// the container on its endpoint ep, which joined the sandbox sb. The chain
+// returns to its parent chains for the connections from the endpoint to
+// allowed destinations and to the DNS servers of the container, and logs and
+// rejects the others. The packets of the endpoint are matched by the host side
+// of its veth pair, which the container cannot change. Only the endpoints on
+// bridge networks can be restricted: the other networks, and the endpoints
+// with an IPv6 address, as ip6tables rules are not installed, are refused.
+func (daemon *Daemon) applyEgressPolicy(c *container.Container, n libnetwork.Network, ep libnetwork.Endpoint, sb libnetwork.Sandbox) error {
+	policy := c.HostConfig.EgressPolicy
+	if policy == nil || n.Type() == "null" {
+		return nil
+	}
+	if n.Type() != "bridge" {
+		return errors.Errorf("EgressPolicy is not supported on network %s of type %s", n.Name(), n.Type())
+	}
+	if !daemon.configStore.BridgeConfig.EnableIPTables {
+		return errors.New("EgressPolicy requires iptables to be enabled")
+	}
+	if info := ep.Info(); info != nil && info.Iface() != nil && info.Iface().AddressIPv6() != nil {
+		return errors.Errorf("EgressPolicy is not supported on IPv6 network %s", n.Name())
+	}
+	veth, err := endpointHostVeth(ep, sb)
+	if err != nil {
+		return errors.Wrap(err, "failed to apply the egress policy")
+	}
+	if err := enableBridgeNetFiltering(); err != nil {
+		return err
+	}
+
+	chain := egressPolicyChain(ep)
+	if _, err := iptables.NewChain(chain, iptables.Filter, false); err != nil {
+		return err
+	}
+	if _, err := iptables.Raw("-t", string(iptables.Filter), "-F", chain); err != nil {
+		return err
+	}
+	rules := [][]string{
+		{"-m", "physdev", "!", "--physdev-in", veth, "-j", "RETURN"},
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "RETURN"},
+	}
+	allowed, err := daemon.egressPolicyRules(c, policy)
+	if err != nil {
+		return err
+	}
+	rules = append(rules, allowed...)
+	for _, args := range rules {
+		if err := iptables.ProgramRule(iptables.Filter, chain, iptables.Append, args); err != nil {
+			return err
+		}
+	}
+
+	// The denied connections are still rejected if they cannot be logged.
+	logArgs := []string{"-m", "limit", "--limit", egressLogLimit, "-j", "NFLOG", "--nflog-group", strconv.Itoa(egressLogGroup), "--nflog-prefix", egressLogPrefix + stringid.TruncateID(c.ID)}
+	if err := iptables.ProgramRule(iptables.Filter, chain, iptables.Append, logArgs); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("Failed to log the connections denied by the egress policy")
+	} else {
+		daemon.startEgressLog()
+	}
+	if err := iptables.ProgramRule(iptables.Filter, chain, iptables.Append, []string{"-j", "REJECT", "--reject-with", "icmp-admin-prohibited"}); err != nil {
+		return err
+	}
+
+	for _, parent := range egressPolicyParents {
+		if iptables.Exists(iptables.Filter, parent, "-j", chain) {
+			continue
+		}
+		if err := iptables.RawCombinedOutput("-I", parent, "-j", chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreEgressLog starts listening for the connections denied by the egress
+// policies of the running containers, which were kept running by live-restore.
+func (daemon *Daemon) restoreEgressLog(containers map[string]*container.Container) {
+	for _, c := range containers {
+		if c.HostConfig.EgressPolicy != nil && c.IsRunning() {
+			daemon.startEgressLog()
+			return
+		}
+	}
+}
+
+// removeEgressPolicy removes the iptables chain enforcing the egress policy on
+// the endpoint, if any.
+func (daemon *Daemon) removeEgressPolicy(ep libnetwork.Endpoint) {
+	if !daemon.configStore.BridgeConfig.EnableIPTables {
+		return
+	}
+	if err := removeEgressPolicyChain(egressPolicyChain(ep)); err != nil {
+		logrus.WithError(err).WithField("endpoint", ep.ID()).Warn("Failed to remove the egress policy of the endpoint")
+	}
+}
+
+// removeEgressPolicyChain removes the jumps to the chain of an egress policy
+// from its parent chains, and the chain.
+func removeEgressPolicyChain(chain string) error {
+	if !iptables.ExistChain(chain, iptables.Filter) {
+		return nil
+	}
+	for _, parent := range egressPolicyParents {
+		if !iptables.Exists(iptables.Filter, parent, "-j", chain) {
+			continue
+		}
+		if err := iptables.ProgramRule(iptables.Filter, parent, iptables.Delete, []string{"-j", chain}); err != nil {
+			return err
+		}
+	}
+	return iptables.RemoveExistingChain(chain, iptables.Filter)
+}
+
+// enableBridgeNetFiltering makes the packets bridged between the containers of
+// a bridge network go through iptables, so that the egress policies apply to
+// the connections to the other containers of the network. The br_netfilter
+// module is loaded if needed.
+func enableBridgeNetFiltering() error {
+	if _, err := os.Stat(bridgeNFCallIPTables); os.IsNotExist(err) {
+		if out, err := exec.Command("modprobe", "-va", "br_netfilter").CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "failed to load the br_netfilter module required by EgressPolicy: %s", out)
+		}
+	}
+	b, err := ioutil.ReadFile(bridgeNFCallIPTables)
+	if err != nil {
+		return errors.Wrap(err, "failed to enable bridge netfilter")
+	}
+	if strings.TrimSpace(string(b)) == "1" {
+		return nil
+	}
+	return errors.Wrap(ioutil.WriteFile(bridgeNFCallIPTables, []byte("1"), 0644), "failed to enable bridge netfilter")
+}
+
+func egressPolicyChain(ep libnetwork.Endpoint) string {
+	return egressPolicyChainPrefix + stringid.TruncateID(ep.ID())
+}
+
+// egressPolicyRules returns the iptables rules allowing the destinations of
+// the egress policy, and the DNS servers of the container. The DNS names are
+// resolved once by the daemon, and an error is returned if one of them cannot
+// be resolved. The addresses are not updated until the endpoint is connected
+// again.
+func (daemon *Daemon) egressPolicyRules(c *container.Container, policy *containertypes.EgressPolicy) ([][]string, error) {
+	var rules [][]string
+	for _, r := range policy.Allow {
+		var dests []string
+		if r.CIDR != "" {
+			n, err := parseEgressCIDR(r.CIDR)
+			if err != nil {
+				return nil, err
+			}
+			dests = append(dests, n.String())
+		} else {
+			ips, err := resolveEgressHost(r.Host)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to apply the egress policy")
+			}
+			for _, ip := range ips {
+				dests = append(dests, ip.String())
+			}
+		}
+		ports, err := parseEgressPorts(r.Ports)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dests {
+			rules = append(rules, egressRules(d, ports)...)
+		}
+	}
+
+	dnsPorts := []egressPort{{proto: "udp", start: 53, end: 53}, {proto: "tcp", start: 53, end: 53}}
+	for _, ns := range daemon.egressNameservers(c) {
+		rules = append(rules, egressRules(ns, dnsPorts)...)
+	}
+	return rules, nil
+}
+
+// egressRules returns the iptables rules allowing the ports of the
+// destination, or all its ports if none is set.
+func egressRules(dest string, ports []egressPort) [][]string {
+	if len(ports) == 0 {
+		return [][]string{{"-d", dest, "-j", "RETURN"}}
+	}
+	var rules [][]string
+	for _, p := range ports {
+		rules = append(rules, []string{"-d", dest, "-p", p.proto, "--dport", fmt.Sprintf("%d:%d", p.start, p.end), "-j", "RETURN"})
+	}
+	return rules
+}
+
+// egressNameservers returns the IPv4 DNS servers of the container, which the
+// embedded DNS server forwards the queries to on user-defined networks.
+func (daemon *Daemon) egressNameservers(c *container.Container) []string {
+	dns := c.HostConfig.DNS
+	if len(dns) == 0 {
+		dns = daemon.configStore.DNS
+	}
+	if len(dns) == 0 {
+		if f, err := resolvconf.GetSpecific(daemon.configStore.GetResolvConf()); err == nil {
+			dns = resolvconf.GetNameservers(f.Content, types.IPv4)
+		}
+	}
+
+	var nameservers []string
+	for _, ns := range dns {
+		if ip := net.ParseIP(ns); ip != nil && ip.To4() != nil && !ip.IsLoopback() {
+			nameservers = append(nameservers, ip.String())
+		}
+	}
+	return nameservers
+}
@@ -0,0 +1,64 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+var cmpEgressPort = cmp.AllowUnexported(egressPort{})
+
+func TestParseEgressPorts(t *testing.T) {
+	ports, err := parseEgressPorts([]string{"443", "8000-8100/udp", "5000/SCTP"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ports, []egressPort{
+		{proto: "tcp", start: 443, end: 443},
+		{proto: "udp", start: 8000, end: 8100},
+		{proto: "sctp", start: 5000, end: 5000},
+	}, cmpEgressPort))
+
+	for _, p := range []string{"0", "http", "80/icmp", "70000", "90-80"} {
+		_, err := parseEgressPorts([]string{p})
+		assert.Check(t, is.ErrorContains(err, "invalid"), p)
+	}
+}
+
+func TestParseEgressCIDR(t *testing.T) {
+	n, err := parseEgressCIDR("10.1.2.3")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(n.String(), "10.1.2.3/32"))
+
+	n, err = parseEgressCIDR("10.1.2.3/8")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(n.String(), "10.0.0.0/8"))
+
+	for _, cidr := range []string{"example.com", "10.0.0.0/33", "2001:db8::/32"} {
+		_, err := parseEgressCIDR(cidr)
+		assert.Check(t, is.ErrorContains(err, "invalid IPv4 network"), cidr)
+	}
+}
+
+func TestValidateEgressPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		rule        containertypes.EgressRule
+		expectedErr string
+	}{
+		{rule: containertypes.EgressRule{CIDR: "10.0.0.0/8"}},
+		{rule: containertypes.EgressRule{Host: "localhost", Ports: []string{"443"}}},
+		{rule: containertypes.EgressRule{Host: "does-not-exist.invalid"}},
+		{rule: containertypes.EgressRule{}, expectedErr: "either CIDR or Host must be set"},
+		{rule: containertypes.EgressRule{CIDR: "10.0.0.0/8", Host: "example.com"}, expectedErr: "either CIDR or Host must be set"},
+		{rule: containertypes.EgressRule{CIDR: "10.0.0.300"}, expectedErr: `invalid egress rule: invalid IPv4 network "10.0.0.300"`},
+		{rule: containertypes.EgressRule{Host: "localhost", Ports: []string{"443/icmp"}}, expectedErr: `invalid egress rule: invalid protocol in port "443/icmp"`},
+	} {
+		err := validateEgressPolicy(&containertypes.EgressPolicy{Allow: []containertypes.EgressRule{tc.rule}})
+		if tc.expectedErr == "" {
+			assert.Check(t, err)
+		} else {
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/container"
+	"github.com/docker/libnetwork"
+)
+
+func (daemon *Daemon) applyEgressPolicy(c *container.Container, n libnetwork.Network, ep libnetwork.Endpoint, sb libnetwork.Sandbox) error {
+	return nil
+}
+
+func (daemon *Daemon) removeEgressPolicy(ep libnetwork.Endpoint) {}
+
+func (daemon *Daemon) restoreEgressLog(containers map[string]*container.Container) {}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/container/stream"
@@ -16,6 +17,7 @@ import (
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/term"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
@@ -25,6 +27,10 @@ import (
 // Seconds to wait after sending TERM before trying KILL
 const termProcessTimeout = 10 * time.Second
 
+// detachableExecRetention is how long detachable execs are kept after they
+// exited, so that their exit code and output can be retrieved.
+const detachableExecRetention = time.Hour
+
 func (d *Daemon) registerExecCommand(container *container.Container, config *exec.Config) {
 	// Storing execs in container in order to kill them gracefully whenever the container is stopped or removed.
 	container.ExecCommands.Add(config.ID, config)
@@ -124,6 +130,9 @@ func (d *Daemon) ContainerExecCreate(name string, config *types.ExecConfig) (str
 	execConfig.Privileged = config.Privileged
 	execConfig.User = config.User
 	execConfig.WorkingDir = config.WorkingDir
+	if config.Detachable {
+		execConfig.SetDetachable(exec.DefaultOutputBufferSize)
+	}
 
 	linkedEnv, err := d.setupLinkedContainers(cntr)
 	if err != nil {
@@ -196,6 +205,9 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 		}
 	}()
 
+	// The input of a detachable exec is kept open for the clients attaching
+	// to it later on.
+	withStdin := ec.OpenStdin && (stdin != nil || ec.Detachable)
 	if ec.OpenStdin && stdin != nil {
 		r, w := io.Pipe()
 		go func() {
@@ -254,13 +266,17 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 		DetachKeys: ec.DetachKeys,
 		CloseStdin: true,
 	}
-	ec.StreamConfig.AttachStreams(&attachConfig)
+	if ec.Detachable {
+		attachExecStreams(ec, &attachConfig)
+	} else {
+		ec.StreamConfig.AttachStreams(&attachConfig)
+	}
 	attachErr := ec.StreamConfig.CopyStreams(ctx, &attachConfig)
 
 	// Synchronize with libcontainerd event loop
 	ec.Lock()
 	c.ExecCommands.Lock()
-	systemPid, err := d.containerd.Exec(ctx, c.ID, ec.ID, p, cStdin != nil, ec.InitializeStdio)
+	systemPid, err := d.containerd.Exec(ctx, c.ID, ec.ID, p, withStdin, ec.InitializeStdio)
 	// the exec context should be ready, or error happened.
 	// close the chan to notify readiness
 	close(ec.Started)
@@ -291,7 +307,7 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 		return ctx.Err()
 	case err := <-attachErr:
 		if err != nil {
-			if _, ok := err.(term.EscapeError); !ok {
+			if _, ok := err.(term.EscapeError); !ok && !ec.Detachable {
 				return errdefs.System(errors.Wrap(err, "exec attach failed"))
 			}
 			attributes := map[string]string{
@@ -303,6 +319,131 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	return nil
 }
 
+// ContainerExecReattach attaches the streams of a client to a detachable exec
+// instance which was started. If c.Logs is set, the buffered output of the exec
+// is written to the client first; the output remains available after the exec
+// exited. If c.Stream is set, the client is attached to the exec until it exits
+// or the client detaches. The exec keeps running when the client detaches.
+func (d *Daemon) ContainerExecReattach(name string, c *backend.ContainerAttachConfig) error {
+	ec := d.execCommands.Get(name)
+	if ec == nil {
+		return errExecNotFound(name)
+	}
+	cntr := d.containers.Get(ec.ContainerID)
+	if cntr == nil {
+		return errExecNotFound(name)
+	}
+	if !ec.Detachable {
+		return errdefs.InvalidParameter(errors.Errorf("exec %s is not detachable", ec.ID))
+	}
+
+	keys := ec.DetachKeys
+	if c.DetachKeys != "" {
+		var err error
+		keys, err = term.ToBytes(c.DetachKeys)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Errorf("Invalid detach keys (%s) provided", c.DetachKeys))
+		}
+	}
+
+	ec.Lock()
+	started := ec.Running || ec.ExitCode != nil
+	ec.Unlock()
+	if !started {
+		return errdefs.Conflict(errors.Errorf("exec %s has not been started", ec.ID))
+	}
+
+	cfg := stream.AttachConfig{
+		TTY:        ec.Tty,
+		DetachKeys: keys,
+		UseStdin:   c.UseStdin && ec.OpenStdin && c.Stream,
+		UseStdout:  c.UseStdout && ec.OpenStdout,
+		UseStderr:  c.UseStderr && ec.OpenStderr,
+		CloseStdin: true,
+	}
+	buffered := attachExecStreams(ec, &cfg)
+
+	inStream, outStream, errStream, err := c.GetStreams()
+	if err != nil {
+		closeExecOutput(&cfg)
+		return err
+	}
+	defer inStream.Close()
+
+	if !ec.Tty && c.MuxStreams {
+		errStream = stdcopy.NewStdWriter(errStream, stdcopy.Stderr)
+		outStream = stdcopy.NewStdWriter(outStream, stdcopy.Stdout)
+	}
+	if cfg.UseStdin {
+		r, w := io.Pipe()
+		go func() {
+			defer w.Close()
+			defer logrus.Debug("Closing buffered stdin pipe")
+			pools.Copy(w, inStream)
+		}()
+		cfg.Stdin = r
+	}
+	if cfg.UseStdout {
+		cfg.Stdout = outStream
+	}
+	if cfg.UseStderr {
+		cfg.Stderr = errStream
+	}
+
+	if c.Logs {
+		for _, o := range buffered {
+			if o.Stream == "stdout" && cfg.Stdout != nil {
+				cfg.Stdout.Write(o.Data)
+			}
+			if o.Stream == "stderr" && cfg.Stderr != nil {
+				cfg.Stderr.Write(o.Data)
+			}
+		}
+	}
+	if !c.Stream {
+		closeExecOutput(&cfg)
+		return nil
+	}
+
+	attributes := map[string]string{
+		"execID": ec.ID,
+	}
+	d.LogContainerEventWithAttributes(cntr, "exec_attach", attributes)
+
+	if err := <-ec.StreamConfig.CopyStreams(context.Background(), &cfg); err != nil {
+		if _, ok := errors.Cause(err).(term.EscapeError); !ok {
+			logrus.WithError(err).WithField("exec", ec.ID).Debug("exec attach ended")
+		}
+		d.LogContainerEventWithAttributes(cntr, "exec_detach", attributes)
+	}
+	return nil
+}
+
+// attachExecStreams connects the attach config to the streams of the
+// detachable exec ec, and returns its buffered output.
+func attachExecStreams(ec *exec.Config, cfg *stream.AttachConfig) []exec.Output {
+	if cfg.UseStdin {
+		cfg.CStdin = ec.StreamConfig.StdinPipe()
+	}
+	buffered, stdout, stderr := ec.Output.Attach(cfg.UseStdout, cfg.UseStderr)
+	if stdout != nil {
+		cfg.CStdout = stdout
+	}
+	if stderr != nil {
+		cfg.CStderr = stderr
+	}
+	return buffered
+}
+
+func closeExecOutput(cfg *stream.AttachConfig) {
+	if cfg.CStdout != nil {
+		cfg.CStdout.Close()
+	}
+	if cfg.CStderr != nil {
+		cfg.CStderr.Close()
+	}
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (d *Daemon) execCommandGC() {
@@ -312,6 +453,9 @@ func (d *Daemon) execCommandGC() {
 			liveExecCommands = d.containerExecIds()
 		)
 		for id, config := range d.execCommands.Commands() {
+			if config.Detachable && !d.detachableExecExpired(config) {
+				continue
+			}
 			if config.CanRemove {
 				cleaned++
 				d.execCommands.Delete(id, config.Pid)
@@ -327,6 +471,18 @@ func (d *Daemon) execCommandGC() {
 	}
 }
 
+// detachableExecExpired returns whether the detachable exec ec can be removed,
+// because its container was removed, or it exited more than the retention
+// period ago.
+func (d *Daemon) detachableExecExpired(ec *exec.Config) bool {
+	if d.containers.Get(ec.ContainerID) == nil {
+		return true
+	}
+	ec.Lock()
+	defer ec.Unlock()
+	return !ec.FinishedAt.IsZero() && time.Since(ec.FinishedAt) > detachableExecRetention
+}
+
 // containerExecIds returns a list of all the current exec ids that are in use
 // and running inside a container.
 func (d *Daemon) containerExecIds() map[string]struct{} {
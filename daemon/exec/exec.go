@@ -4,6 +4,7 @@ import (
 	"context"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/containerd/containerd/cio"
 	"github.com/docker/docker/container/stream"
@@ -35,6 +36,9 @@ type Config struct {
 	WorkingDir   string
 	Env          []string
 	Pid          int
+	Detachable   bool
+	Output       *OutputBuffer
+	FinishedAt   time.Time
 }
 
 // NewConfig initializes the a new exec configuration
@@ -64,6 +68,15 @@ func (i *rio) Wait() {
 	i.IO.Wait()
 }
 
+// SetDetachable makes the exec detachable: its output is kept in a buffer of
+// the given size, and clients can attach to it after it was started.
+func (c *Config) SetDetachable(size int) {
+	c.Detachable = true
+	c.Output = NewOutputBuffer(size)
+	c.StreamConfig.Stdout().Add(c.Output.Writer("stdout"))
+	c.StreamConfig.Stderr().Add(c.Output.Writer("stderr"))
+}
+
 // InitializeStdio is called by libcontainerd to connect the stdio.
 func (c *Config) InitializeStdio(iop *cio.DirectIO) (cio.IO, error) {
 	c.StreamConfig.CopyToPipe(iop)
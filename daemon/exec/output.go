@@ -0,0 +1,168 @@
+package exec // import "github.com/docker/docker/daemon/exec"
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+// DefaultOutputBufferSize is the number of bytes of output kept for
+// detachable execs.
+const DefaultOutputBufferSize = 1024 * 1024
+
+// Output is a chunk of the output of an exec process on one of its streams.
+type Output struct {
+	Stream string
+	Data   []byte
+}
+
+// OutputBuffer keeps the most recent output of an exec process, so that it
+// can be replayed to the clients attaching to the exec, and copies the output
+// to the attached clients.
+type OutputBuffer struct {
+	mu      sync.Mutex
+	max     int
+	size    int
+	chunks  []Output
+	streams map[string]*outputStream
+}
+
+type outputStream struct {
+	closed      bool
+	subscribers []*ioutils.BytesPipe
+}
+
+// NewOutputBuffer creates an output buffer keeping at most size bytes.
+func NewOutputBuffer(size int) *OutputBuffer {
+	return &OutputBuffer{
+		max: size,
+		streams: map[string]*outputStream{
+			"stdout": {},
+			"stderr": {},
+		},
+	}
+}
+
+// Writer returns a writer for the given stream, "stdout" or "stderr", of the
+// process. Closing the writer closes the readers of the stream returned by
+// Attach, but the buffered output is kept.
+func (b *OutputBuffer) Writer(stream string) io.WriteCloser {
+	return &outputWriter{b: b, stream: stream}
+}
+
+// Attach returns the buffered output, and readers of the output written from
+// then on to the stdout and stderr streams, if requested. The readers of a
+// stream return EOF once the stream is closed.
+func (b *OutputBuffer) Attach(stdout, stderr bool) (buffered []Output, stdoutReader, stderrReader io.ReadCloser) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	buffered = make([]Output, len(b.chunks))
+	copy(buffered, b.chunks)
+	if stdout {
+		stdoutReader = b.subscribe("stdout")
+	}
+	if stderr {
+		stderrReader = b.subscribe("stderr")
+	}
+	return buffered, stdoutReader, stderrReader
+}
+
+func (b *OutputBuffer) subscribe(stream string) io.ReadCloser {
+	s := b.streams[stream]
+	if s.closed {
+		return ioutil.NopCloser(strings.NewReader(""))
+	}
+	p := ioutils.NewBytesPipe()
+	s.subscribers = append(s.subscribers, p)
+	return p
+}
+
+// write buffers p and copies it to the readers of the stream. The readers are
+// written to without holding the lock, as a reader which does not keep up
+// blocks the write until it reads, or is closed.
+func (b *OutputBuffer) write(stream string, p []byte) {
+	b.mu.Lock()
+	b.append(stream, p)
+	s := b.streams[stream]
+	subscribers := make([]*ioutils.BytesPipe, len(s.subscribers))
+	copy(subscribers, s.subscribers)
+	b.mu.Unlock()
+
+	// Readers which are gone are closed, and dropped on their next write.
+	var gone []*ioutils.BytesPipe
+	for _, sub := range subscribers {
+		if _, err := sub.Write(p); err != nil {
+			gone = append(gone, sub)
+		}
+	}
+	if len(gone) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, g := range gone {
+		for i, sub := range s.subscribers {
+			if sub == g {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// append adds p to the buffered output, dropping the oldest output beyond the
+// size of the buffer.
+func (b *OutputBuffer) append(stream string, p []byte) {
+	if len(p) > b.max {
+		p = p[len(p)-b.max:]
+	}
+	if n := len(b.chunks); n > 0 && b.chunks[n-1].Stream == stream {
+		b.chunks[n-1].Data = append(b.chunks[n-1].Data, p...)
+	} else {
+		b.chunks = append(b.chunks, Output{Stream: stream, Data: append([]byte(nil), p...)})
+	}
+	b.size += len(p)
+
+	for b.size > b.max {
+		first := &b.chunks[0]
+		if drop := b.size - b.max; drop < len(first.Data) {
+			first.Data = first.Data[drop:]
+			b.size -= drop
+			break
+		}
+		b.size -= len(first.Data)
+		b.chunks = b.chunks[1:]
+	}
+}
+
+func (b *OutputBuffer) close(stream string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := b.streams[stream]
+	for _, sub := range s.subscribers {
+		sub.Close()
+	}
+	s.subscribers = nil
+	s.closed = true
+}
+
+type outputWriter struct {
+	b      *OutputBuffer
+	stream string
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.b.write(w.stream, p)
+	return len(p), nil
+}
+
+func (w *outputWriter) Close() error {
+	w.b.close(w.stream)
+	return nil
+}
@@ -0,0 +1,102 @@
+package exec // import "github.com/docker/docker/daemon/exec"
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestOutputBufferRing(t *testing.T) {
+	b := NewOutputBuffer(8)
+	stdout, stderr := b.Writer("stdout"), b.Writer("stderr")
+
+	stdout.Write([]byte("abc"))
+	stdout.Write([]byte("def"))
+	stderr.Write([]byte("gh"))
+	buffered, _, _ := b.Attach(false, false)
+	assert.Check(t, is.DeepEqual(buffered, []Output{
+		{Stream: "stdout", Data: []byte("abcdef")},
+		{Stream: "stderr", Data: []byte("gh")},
+	}))
+
+	stdout.Write([]byte("ijk"))
+	buffered, _, _ = b.Attach(false, false)
+	assert.Check(t, is.DeepEqual(buffered, []Output{
+		{Stream: "stdout", Data: []byte("def")},
+		{Stream: "stderr", Data: []byte("gh")},
+		{Stream: "stdout", Data: []byte("ijk")},
+	}))
+
+	stderr.Write([]byte("0123456789"))
+	buffered, _, _ = b.Attach(false, false)
+	assert.Check(t, is.DeepEqual(buffered, []Output{
+		{Stream: "stderr", Data: []byte("23456789")},
+	}))
+}
+
+func TestOutputBufferAttach(t *testing.T) {
+	b := NewOutputBuffer(1024)
+	stdout, stderr := b.Writer("stdout"), b.Writer("stderr")
+
+	stdout.Write([]byte("before"))
+	buffered, stdoutReader, stderrReader := b.Attach(true, false)
+	assert.Check(t, is.Nil(stderrReader))
+	assert.Check(t, is.DeepEqual(buffered, []Output{{Stream: "stdout", Data: []byte("before")}}))
+
+	stdout.Write([]byte("after"))
+	stderr.Write([]byte("error"))
+	assert.NilError(t, stdout.Close())
+
+	out, err := ioutil.ReadAll(stdoutReader)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "after"))
+
+	// The output remains available once the process exited.
+	buffered, stdoutReader, _ = b.Attach(true, false)
+	assert.Check(t, is.Len(buffered, 2))
+	out, err = ioutil.ReadAll(stdoutReader)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), ""))
+}
+
+func TestOutputBufferSlowReader(t *testing.T) {
+	b := NewOutputBuffer(1024)
+	stdout := b.Writer("stdout")
+
+	// The reader does not read, so the writes block once its pipe is full.
+	_, stdoutReader, _ := b.Attach(true, false)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for i := 0; i < 64; i++ {
+			stdout.Write(make([]byte, 64*1024))
+		}
+	}()
+
+	// The other clients can still attach while the writer is blocked.
+	attached := make(chan struct{})
+	go func() {
+		defer close(attached)
+		for i := 0; i < 10; i++ {
+			b.Attach(false, false)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	select {
+	case <-attached:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting to attach")
+	}
+
+	// Closing the reader unblocks the writer, and drops the reader.
+	assert.NilError(t, stdoutReader.Close())
+	select {
+	case <-written:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the writes")
+	}
+	assert.Check(t, is.Len(b.streams["stdout"].subscribers, 0))
+}
@@ -584,6 +584,12 @@ func (d *Driver) Create(id, parent string, opts *graphdriver.CreateOpts) error {
 	return label.Relabel(path.Join(subvolumes, id), mountLabel, false)
 }
 
+// Snapshot creates the layer id as a btrfs snapshot of the subvolume of
+// layer, which is atomic.
+func (d *Driver) Snapshot(id, layer, parent string) error {
+	return d.Create(id, layer, nil)
+}
+
 // Parse btrfs storage options
 func (d *Driver) parseStorageOpt(storageOpt map[string]string, driver *Driver) error {
 	// Read size to change the subvolume disk quota per container
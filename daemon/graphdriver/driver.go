@@ -131,6 +131,35 @@ type DiffGetterDriver interface {
 	DiffGetter(id string) (FileGetCloser, error)
 }
 
+// ParentSetterDriver is the interface for layered file system drivers whose
+// layer contents do not depend on the parent layer. A layer can be created
+// without a parent and have its diff applied before the parent exists, and
+// is then attached to the parent with SetParent.
+type ParentSetterDriver interface {
+	Driver
+	// SetParent attaches the layer id, which must have been created without
+	// a parent, to the given parent layer.
+	SetParent(id, parent string) error
+}
+
+// Snapshotter is the interface for graph drivers which can take a
+// point-in-time snapshot of a layer while it is in use, without freezing the
+// processes using it.
+type Snapshotter interface {
+	// Snapshot creates the layer id with the content layer has at the time
+	// of the call. The changes of id relative to parent, which is the parent
+	// of layer, are the changes of layer. It returns ErrNotSupported if the
+	// driver cannot take snapshots on the backing filesystem.
+	Snapshot(id, layer, parent string) error
+}
+
+// SnapshotDriver is the interface for layered file system drivers which can
+// take snapshots of layers.
+type SnapshotDriver interface {
+	Driver
+	Snapshotter
+}
+
 // FileGetCloser extends the storage.FileGetter interface with a Close method
 // for cleaning up.
 type FileGetCloser interface {
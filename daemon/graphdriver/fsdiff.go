@@ -173,3 +173,13 @@ func (gdw *NaiveDiffDriver) DiffSize(id, parent string) (size int64, err error)
 
 	return archive.ChangesSize(layerFs.Path(), changes), nil
 }
+
+// Snapshot takes a snapshot of a layer if the wrapped driver implements
+// Snapshotter, and returns ErrNotSupported otherwise.
+func (gdw *NaiveDiffDriver) Snapshot(id, layer, parent string) error {
+	snapshotter, ok := gdw.ProtoDriver.(Snapshotter)
+	if !ok {
+		return ErrNotSupported
+	}
+	return snapshotter.Snapshot(id, layer, parent)
+}
@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/daemon/graphdriver/copy"
 	"github.com/docker/docker/daemon/graphdriver/overlayutils"
 	"github.com/docker/docker/daemon/graphdriver/quota"
 	"github.com/docker/docker/pkg/archive"
@@ -459,6 +460,70 @@ func (d *Driver) create(id, parent string, opts *graphdriver.CreateOpts) (retErr
 	return nil
 }
 
+// SetParent attaches the layer id, which was created without a parent, to
+// the given parent layer. As overlay layers only hold the changes relative to
+// their parent, a diff can be applied to the layer before calling SetParent.
+func (d *Driver) SetParent(id, parent string) error {
+	if parent == "" {
+		return nil
+	}
+	dir := d.dir(id)
+	if _, err := os.Lstat(path.Join(dir, lowerFile)); err == nil {
+		return fmt.Errorf("layer %s already has a parent", id)
+	}
+
+	rootUID, rootGID, err := idtools.GetRootUIDGID(d.uidMaps, d.gidMaps)
+	if err != nil {
+		return err
+	}
+	root := idtools.Identity{UID: rootUID, GID: rootGID}
+
+	if err := idtools.MkdirAndChown(path.Join(dir, workDirName), 0700, root); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	lower, err := d.getLower(parent)
+	if err != nil {
+		return err
+	}
+	if lower != "" {
+		if err := ioutil.WriteFile(path.Join(dir, lowerFile), []byte(lower), 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotAttempts is the number of times taking a snapshot of a layer is
+// attempted, if files are removed from the layer while they are copied.
+const snapshotAttempts = 3
+
+// Snapshot creates the layer id with a copy of the changes of layer, relative
+// to parent. Overlay has no native snapshots, so the upper directory of layer
+// is copied file by file, without freezing the processes using it; files
+// being written to during the copy may be captured in any state.
+func (d *Driver) Snapshot(id, layer, parent string) error {
+	for attempt := 1; ; attempt++ {
+		err := d.snapshot(id, layer, parent)
+		if err == nil || !os.IsNotExist(err) || attempt == snapshotAttempts {
+			return err
+		}
+		logger.WithError(err).Debugf("file removed while taking snapshot of layer %s, retrying", layer)
+	}
+}
+
+func (d *Driver) snapshot(id, layer, parent string) (retErr error) {
+	if err := d.create(id, parent, nil); err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			d.Remove(id)
+		}
+	}()
+	return copy.DirCopy(path.Join(d.dir(layer), diffDirName), path.Join(d.dir(id), diffDirName), copy.Content, true)
+}
+
 // Parse overlay storage options
 func (d *Driver) parseStorageOpt(storageOpt map[string]string, driver *Driver) error {
 	// Read size to set the disk project quota per container
@@ -55,6 +55,7 @@ import (
 	"io/ioutil"
 	"path"
 	"path/filepath"
+	"sync"
 	"unsafe"
 
 	rsystem "github.com/opencontainers/runc/libcontainer/system"
@@ -63,6 +64,45 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+var (
+	projectIDAllocatorsMu sync.Mutex
+	// projectIDAllocators holds the project id allocator of each filesystem,
+	// keyed by device number.
+	projectIDAllocators = make(map[uint64]*projectIDAllocator)
+)
+
+// getProjectIDAllocator - get the project id allocator shared by all
+// Controls on the filesystem of device dev
+func getProjectIDAllocator(dev uint64) *projectIDAllocator {
+	projectIDAllocatorsMu.Lock()
+	defer projectIDAllocatorsMu.Unlock()
+
+	a, ok := projectIDAllocators[dev]
+	if !ok {
+		a = &projectIDAllocator{}
+		projectIDAllocators[dev] = a
+	}
+	return a
+}
+
+// reserve - make sure projectID is never handed out by the allocator
+func (a *projectIDAllocator) reserve(projectID uint32) {
+	a.mu.Lock()
+	if a.nextProjectID <= projectID {
+		a.nextProjectID = projectID + 1
+	}
+	a.mu.Unlock()
+}
+
+// next - allocate the next free project id
+func (a *projectIDAllocator) next() uint32 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	projectID := a.nextProjectID
+	a.nextProjectID++
+	return projectID
+}
+
 // NewControl - initialize project quota support.
 // Test to make sure that quota can be set on a test dir and find
 // the first project id to be used for the next container create.
@@ -132,11 +172,20 @@ func NewControl(basePath string) (*Control, error) {
 		return nil, err
 	}
 
+	//
+	// Project ids are allocated per filesystem, as other Controls on the
+	// same filesystem (with a different base path) share the id space
+	//
+	var stat unix.Stat_t
+	if err := unix.Stat(basePath, &stat); err != nil {
+		return nil, err
+	}
 	q := Control{
 		backingFsBlockDev: backingFsBlockDev,
-		nextProjectID:     minProjectID + 1,
+		projectIDs:        getProjectIDAllocator(uint64(stat.Dev)),
 		quotas:            make(map[string]uint32),
 	}
+	q.projectIDs.reserve(minProjectID)
 
 	//
 	// get first project id to be used for next container
@@ -146,7 +195,7 @@ func NewControl(basePath string) (*Control, error) {
 		return nil, err
 	}
 
-	logrus.Debugf("NewControl(%s): nextProjectID = %d", basePath, q.nextProjectID)
+	logrus.Debugf("NewControl(%s): minProjectID = %d", basePath, minProjectID)
 	return &q, nil
 }
 
@@ -156,7 +205,7 @@ func (q *Control) SetQuota(targetPath string, quota Quota) error {
 
 	projectID, ok := q.quotas[targetPath]
 	if !ok {
-		projectID = q.nextProjectID
+		projectID = q.projectIDs.next()
 
 		//
 		// assign project id to new container directory
@@ -167,7 +216,6 @@ func (q *Control) SetQuota(targetPath string, quota Quota) error {
 		}
 
 		q.quotas[targetPath] = projectID
-		q.nextProjectID++
 	}
 
 	//
@@ -292,9 +340,7 @@ func (q *Control) findNextProjectID(home string) error {
 		if projid > 0 {
 			q.quotas[path] = projid
 		}
-		if q.nextProjectID <= projid {
-			q.nextProjectID = projid + 1
-		}
+		q.projectIDs.reserve(projid)
 	}
 
 	return nil
@@ -58,6 +58,7 @@ func TestBlockDev(t *testing.T) {
 	t.Run("testSmallerThanQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testSmallerThanQuota)))
 	t.Run("testBiggerThanQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testBiggerThanQuota)))
 	t.Run("testRetrieveQuota", wrapMountTest(imageFileName, true, wrapQuotaTest(testRetrieveQuota)))
+	t.Run("testSharedFilesystem", wrapMountTest(imageFileName, true, testSharedFilesystem))
 }
 
 func wrapMountTest(imageFileName string, enableQuota bool, testFunc func(t *testing.T, mountPoint, backingFsDev string)) func(*testing.T) {
@@ -150,3 +151,32 @@ func testRetrieveQuota(t *testing.T, ctrl *Control, homeDir, testDir, testSubDir
 	assert.NilError(t, ctrl.GetQuota(testSubDir, &q))
 	assert.Check(t, is.Equal(uint64(testQuotaSize), q.Size))
 }
+
+func testSharedFilesystem(t *testing.T, mountPoint, backingFsDev string) {
+	// Two Controls on the same filesystem (e.g. the storage driver and the
+	// local volume driver) must not hand out the same project id
+	homeA, err := ioutil.TempDir(mountPoint, "home-a")
+	assert.NilError(t, err)
+	homeB, err := ioutil.TempDir(mountPoint, "home-b")
+	assert.NilError(t, err)
+
+	ctrlA, err := NewControl(homeA)
+	assert.NilError(t, err)
+	ctrlB, err := NewControl(homeB)
+	assert.NilError(t, err)
+
+	dirA, err := ioutil.TempDir(homeA, "quota-test")
+	assert.NilError(t, err)
+	dirB, err := ioutil.TempDir(homeB, "quota-test")
+	assert.NilError(t, err)
+
+	assert.NilError(t, ctrlA.SetQuota(dirA, Quota{testQuotaSize}))
+	assert.NilError(t, ctrlB.SetQuota(dirB, Quota{2 * testQuotaSize}))
+	assert.Check(t, ctrlA.quotas[dirA] != ctrlB.quotas[dirB])
+
+	var q Quota
+	assert.NilError(t, ctrlA.GetQuota(dirA, &q))
+	assert.Check(t, is.Equal(uint64(testQuotaSize), q.Size))
+	assert.NilError(t, ctrlB.GetQuota(dirB, &q))
+	assert.Check(t, is.Equal(uint64(2*testQuotaSize), q.Size))
+}
@@ -2,6 +2,8 @@
 
 package quota // import "github.com/docker/docker/daemon/graphdriver/quota"
 
+import "sync"
+
 // Quota limit params - currently we only control blocks hard limit
 type Quota struct {
 	Size uint64
@@ -11,6 +13,15 @@ type Quota struct {
 // who wants to apply project quotas to container dirs
 type Control struct {
 	backingFsBlockDev string
-	nextProjectID     uint32
+	projectIDs        *projectIDAllocator
 	quotas            map[string]uint32
 }
+
+// projectIDAllocator hands out project ids on a single filesystem. It is
+// shared by every Control on that filesystem (e.g. the storage driver and
+// the local volume driver), so that they never assign the same project id
+// to different directories.
+type projectIDAllocator struct {
+	mu            sync.Mutex
+	nextProjectID uint32
+}
@@ -138,6 +138,13 @@ func (d *Driver) Create(id, parent string, opts *graphdriver.CreateOpts) error {
 	return d.create(id, parent, 0)
 }
 
+// Snapshot creates the layer id with a copy of the content of layer. The
+// files of layer are copied one by one, without freezing the processes using
+// it; files being written to during the copy may be captured in any state.
+func (d *Driver) Snapshot(id, layer, parent string) error {
+	return d.create(id, layer, 0)
+}
+
 func (d *Driver) create(id, parent string, size uint64) error {
 	dir := d.dir(id)
 	rootIDs := d.idMapping.RootPair()
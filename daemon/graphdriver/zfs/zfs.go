@@ -293,6 +293,12 @@ func (d *Driver) Create(id, parent string, opts *graphdriver.CreateOpts) error {
 	return d.create(id, parent, storageOpt)
 }
 
+// Snapshot creates the layer id as a clone of a zfs snapshot of the
+// filesystem of layer, which is atomic.
+func (d *Driver) Snapshot(id, layer, parent string) error {
+	return d.Create(id, layer, nil)
+}
+
 func (d *Driver) create(id, parent string, storageOpt map[string]string) error {
 	name := d.zfsPath(id)
 	quota, err := parseStorageOpt(storageOpt)
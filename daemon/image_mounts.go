@@ -0,0 +1,75 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	volumemounts "github.com/docker/docker/volume/mounts"
+	"github.com/pkg/errors"
+)
+
+// imageMountDriverName is reported as the driver of image mounts.
+const imageMountDriverName = "image"
+
+// registerImageMount resolves the image of a new image mount point of a
+// container, and sets the volume providing its root filesystem. The image ID
+// is stored as the name of the mount point, so that the mount keeps using the
+// same image if the image reference is updated.
+func (daemon *Daemon) registerImageMount(containerOS string, mp *volumemounts.MountPoint) error {
+	v, err := daemon.imageVolume(mp.Name)
+	if err != nil {
+		return err
+	}
+	if os := v.img.OperatingSystem(); os != containerOS {
+		return errdefs.InvalidParameter(errors.Errorf("cannot mount %s image %s into %s container", os, mp.Name, containerOS))
+	}
+	mp.Name = v.Name()
+	mp.Volume = v
+	return nil
+}
+
+func (daemon *Daemon) imageVolume(refOrID string) (*imageVolume, error) {
+	img, err := daemon.imageService.GetImage(refOrID)
+	if err != nil {
+		return nil, err
+	}
+	return &imageVolume{img: img, daemon: daemon}, nil
+}
+
+// imageVolume provides the root filesystem of an image to image mount points.
+// It implements volume.Volume, so that image mounts are set up and released
+// like volume mounts. The root filesystem of the image is mounted read-only
+// by the layer store, and shared by all containers mounting the image.
+type imageVolume struct {
+	img    *image.Image
+	daemon *Daemon
+}
+
+func (v *imageVolume) Name() string {
+	return v.img.ID().String()
+}
+
+func (v *imageVolume) DriverName() string {
+	return imageMountDriverName
+}
+
+func (v *imageVolume) Path() string {
+	return ""
+}
+
+func (v *imageVolume) Mount(id string) (string, error) {
+	return v.daemon.imageService.MountImage(v.img)
+}
+
+func (v *imageVolume) Unmount(id string) error {
+	return v.daemon.imageService.UnmountImage(v.img)
+}
+
+func (v *imageVolume) CreatedAt() (time.Time, error) {
+	return v.img.Created, nil
+}
+
+func (v *imageVolume) Status() map[string]interface{} {
+	return nil
+}
@@ -18,7 +18,13 @@ func (i *ImageService) CommitImage(c backend.CommitConfig) (image.ID, error) {
 	if !ok {
 		return "", system.ErrNotSupportedOperatingSystem
 	}
-	rwTar, err := exportContainerRw(layerStore, c.ContainerID, c.ContainerMountLabel)
+	var err error
+	var rwTar io.ReadCloser
+	if c.Snapshot != "" {
+		rwTar, err = exportContainerSnapshot(layerStore, c.ContainerID, c.Snapshot, c.SnapshotSince)
+	} else {
+		rwTar, err = exportContainerRw(layerStore, c.ContainerID, c.ContainerMountLabel)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -105,6 +111,28 @@ func exportContainerRw(layerStore layer.Store, id, mountLabel string) (arch io.R
 		nil
 }
 
+// exportContainerSnapshot returns a tar archive of the changes of a snapshot of
+// the RW layer of a container, from the parent of the RW layer or, if since is
+// not empty, from the snapshot since.
+func exportContainerSnapshot(layerStore layer.Store, id, snapshot, since string) (arch io.ReadCloser, err error) {
+	rwlayer, err := layerStore.GetRWLayer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := rwlayer.SnapshotTarStream(snapshot, since)
+	if err != nil {
+		layerStore.ReleaseRWLayer(rwlayer)
+		return nil, err
+	}
+	return ioutils.NewReadCloserWrapper(archive, func() error {
+			err := archive.Close()
+			layerStore.ReleaseRWLayer(rwlayer)
+			return err
+		}),
+		nil
+}
+
 // CommitBuildStep is used by the builder to create an image for each step in
 // the build.
 //
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
@@ -76,7 +77,7 @@ func (i *ImageService) ImageDelete(imageRef string, force, prune bool) ([]types.
 	repoRefs := i.referenceStore.References(imgID.Digest())
 
 	using := func(c *container.Container) bool {
-		return c.ImageID == imgID
+		return usesImage(c, imgID)
 	}
 
 	var removedRepositoryRef bool
@@ -369,7 +370,7 @@ func (i *ImageService) checkImageDeleteConflict(imgID image.ID, mask conflictTyp
 	if mask&conflictRunningContainer != 0 {
 		// Check if any running container is using the image.
 		running := func(c *container.Container) bool {
-			return c.IsRunning() && c.ImageID == imgID
+			return c.IsRunning() && usesImage(c, imgID)
 		}
 		if container := i.containers.First(running); container != nil {
 			return &imageDeleteConflict{
@@ -392,7 +393,7 @@ func (i *ImageService) checkImageDeleteConflict(imgID image.ID, mask conflictTyp
 	if mask&conflictStoppedContainer != 0 {
 		// Check if any stopped containers reference this image.
 		stopped := func(c *container.Container) bool {
-			return !c.IsRunning() && c.ImageID == imgID
+			return !c.IsRunning() && usesImage(c, imgID)
 		}
 		if container := i.containers.First(stopped); container != nil {
 			return &imageDeleteConflict{
@@ -412,3 +413,17 @@ func (i *ImageService) checkImageDeleteConflict(imgID image.ID, mask conflictTyp
 func (i *ImageService) imageIsDangling(imgID image.ID) bool {
 	return !(len(i.referenceStore.References(imgID.Digest())) > 0 || len(i.imageStore.Children(imgID)) > 0)
 }
+
+// usesImage returns whether the container was created from the image, or
+// mounts the image.
+func usesImage(c *container.Container, imgID image.ID) bool {
+	if c.ImageID == imgID {
+		return true
+	}
+	for _, m := range c.MountPoints {
+		if m.Type == mount.TypeImage && m.Name == imgID.String() {
+			return true
+		}
+	}
+	return false
+}
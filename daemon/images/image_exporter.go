@@ -2,8 +2,10 @@ package images // import "github.com/docker/docker/daemon/images"
 
 import (
 	"io"
+	"sort"
 
 	"github.com/docker/docker/image/tarexport"
+	"github.com/docker/docker/layer"
 )
 
 // ExportImage exports a list of images to the given output stream. The
@@ -16,6 +18,29 @@ func (i *ImageService) ExportImage(names []string, outStream io.Writer) error {
 	return imageExporter.Save(names, outStream)
 }
 
+// ExportImageExcludingLayers exports a list of images like ExportImage,
+// but leaves out the content of the layers in excludeLayers. This is used to
+// transfer images to another daemon, which reported the layers it has using
+// LayerChainIDs, without sending layers it already has.
+func (i *ImageService) ExportImageExcludingLayers(names []string, excludeLayers []layer.ChainID, outStream io.Writer) error {
+	imageExporter := tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
+	return imageExporter.SaveExcludingLayers(names, excludeLayers, outStream)
+}
+
+// LayerChainIDs returns the ChainIDs of all layers in the layer stores.
+func (i *ImageService) LayerChainIDs() []layer.ChainID {
+	var chainIDs []layer.ChainID
+	for _, ls := range i.layerStores {
+		for chainID := range ls.Map() {
+			chainIDs = append(chainIDs, chainID)
+		}
+	}
+	sort.Slice(chainIDs, func(a, b int) bool {
+		return chainIDs[a] < chainIDs[b]
+	})
+	return chainIDs
+}
+
 // LoadImage uploads a set of images into the repository. This is the
 // complement of ImageExport.  The input stream is an uncompressed tar
 // ball containing images and metadata.
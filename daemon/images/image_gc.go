@@ -0,0 +1,215 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/sirupsen/logrus"
+)
+
+// imageGCInterval is the interval at which the disk usage of the data-root
+// is checked.
+const imageGCInterval = time.Minute
+
+// ImageGCConfig configures the garbage collection of unused images.
+type ImageGCConfig struct {
+	// Root is the directory whose filesystem usage is monitored.
+	Root string
+	// HighThreshold is the disk usage, in percent, above which images
+	// are garbage collected. Garbage collection is disabled if it is 0.
+	HighThreshold int
+	// LowThreshold is the disk usage, in percent, that garbage collection
+	// frees space down to.
+	LowThreshold int
+}
+
+// StartImageGC starts garbage collecting unused images in the background,
+// least recently used first, whenever the disk usage of the filesystem of
+// config.Root exceeds the high threshold. It stops when Cleanup is called.
+func (i *ImageService) StartImageGC(config ImageGCConfig) {
+	if config.HighThreshold <= 0 {
+		return
+	}
+	i.gcRunning.Add(1)
+	go func() {
+		defer i.gcRunning.Done()
+		ticker := time.NewTicker(imageGCInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-i.gcStop:
+				return
+			case <-ticker.C:
+			}
+			if err := i.imageGC(config); err != nil {
+				logrus.WithError(err).Warn("image garbage collection failed, disabling it")
+				return
+			}
+		}
+	}()
+}
+
+// imageGC removes unused images until the disk usage of config.Root drops
+// below config.LowThreshold. Only errors which prevent garbage collection
+// from ever working are returned.
+func (i *ImageService) imageGC(config ImageGCConfig) error {
+	usage, err := diskUsage(config.Root)
+	if err != nil {
+		return err
+	}
+	if usage < config.HighThreshold {
+		return nil
+	}
+	if !atomic.CompareAndSwapInt32(&i.pruneRunning, 0, 1) {
+		// an image prune is running; try again on the next tick
+		return nil
+	}
+	defer atomic.StoreInt32(&i.pruneRunning, 0)
+
+	logrus.Infof("disk usage of %s is %d%%, garbage collecting images down to %d%%", config.Root, usage, config.LowThreshold)
+
+	for usage > config.LowThreshold {
+		candidates := i.imageGCCandidates()
+		if len(candidates) == 0 {
+			logrus.Warnf("disk usage of %s is %d%%, but there are no unused images left to remove", config.Root, usage)
+			return nil
+		}
+
+		allLayers := make(map[layer.ChainID]layer.Layer)
+		for _, ls := range i.layerStores {
+			for k, v := range ls.Map() {
+				allLayers[k] = v
+			}
+		}
+
+		deleted := false
+		for _, c := range candidates {
+			select {
+			case <-i.gcStop:
+				return nil
+			default:
+			}
+			if !i.imageGCDelete(c, allLayers) {
+				continue
+			}
+			deleted = true
+			if usage, err = diskUsage(config.Root); err != nil {
+				return err
+			}
+			if usage <= config.LowThreshold {
+				return nil
+			}
+		}
+		if !deleted {
+			return nil
+		}
+		// Removing images may have left parent images without children,
+		// which are candidates in the next round.
+	}
+	return nil
+}
+
+type imageGCCandidate struct {
+	id       image.ID
+	lastUsed time.Time
+}
+
+// imageGCCandidates returns the images that can be garbage collected, least
+// recently used first. Images which have children, or are used or mounted by
+// a container, are never garbage collected.
+func (i *ImageService) imageGCCandidates() []imageGCCandidate {
+	inUse := make(map[image.ID]struct{})
+	for _, c := range i.containers.List() {
+		inUse[c.ImageID] = struct{}{}
+		for _, m := range c.MountPoints {
+			if m.Type == mount.TypeImage {
+				inUse[image.ID(m.Name)] = struct{}{}
+			}
+		}
+	}
+
+	var candidates []imageGCCandidate
+	for id, img := range i.imageStore.Heads() {
+		if _, ok := inUse[id]; ok {
+			continue
+		}
+		candidates = append(candidates, imageGCCandidate{
+			id:       id,
+			lastUsed: i.imageLastUsed(id, img),
+		})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].lastUsed.Before(candidates[b].lastUsed)
+	})
+	return candidates
+}
+
+// imageLastUsed returns the time the image was last used to create a
+// container. Images which were never used are considered used when they were
+// last tagged, pulled or loaded, so that fresh images are not removed before
+// they had a chance to be used, or when they were created otherwise.
+func (i *ImageService) imageLastUsed(id image.ID, img *image.Image) time.Time {
+	if t, err := i.imageStore.GetLastUsed(id); err == nil && !t.IsZero() {
+		return t
+	}
+	if t, err := i.imageStore.GetLastUpdated(id); err == nil && !t.IsZero() {
+		return t
+	}
+	return img.Created
+}
+
+// imageGCDelete deletes the image and all its references, and logs a "gc"
+// event if it was removed. It returns whether the image was removed.
+func (i *ImageService) imageGCDelete(c imageGCCandidate, allLayers map[layer.ChainID]layer.Layer) bool {
+	var deletedImages []types.ImageDeleteResponseItem
+	if refs := i.referenceStore.References(c.id.Digest()); len(refs) > 0 {
+		for _, ref := range refs {
+			imgDel, err := i.ImageDelete(ref.String(), false, true)
+			if imageDeleteFailed(ref.String(), err) {
+				continue
+			}
+			deletedImages = append(deletedImages, imgDel...)
+		}
+	} else {
+		hex := c.id.Digest().Hex()
+		imgDel, err := i.ImageDelete(hex, false, true)
+		if imageDeleteFailed(hex, err) {
+			return false
+		}
+		deletedImages = append(deletedImages, imgDel...)
+	}
+
+	removed := false
+	var reclaimed int64
+	for _, d := range deletedImages {
+		if d.Deleted == "" {
+			continue
+		}
+		if d.Deleted == c.id.String() {
+			removed = true
+		}
+		if l, ok := allLayers[layer.ChainID(d.Deleted)]; ok {
+			if diffSize, err := l.DiffSize(); err == nil {
+				reclaimed += diffSize
+			}
+		}
+	}
+	if !removed {
+		return false
+	}
+
+	attributes := map[string]string{
+		"reclaimed": strconv.FormatInt(reclaimed, 10),
+	}
+	if !c.lastUsed.IsZero() {
+		attributes["lastUsed"] = c.lastUsed.UTC().Format(time.RFC3339Nano)
+	}
+	i.LogImageEventWithAttributes(c.id.String(), "", "gc", attributes)
+	return true
+}
@@ -0,0 +1,20 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// diskUsage returns the usage, in percent, of the filesystem of path, as
+// seen by unprivileged users.
+func diskUsage(path string) (int, error) {
+	var buf unix.Statfs_t
+	if err := unix.Statfs(path, &buf); err != nil {
+		return 0, err
+	}
+	used := buf.Blocks - buf.Bfree
+	total := used + buf.Bavail
+	if total == 0 {
+		return 0, nil
+	}
+	return int(used * 100 / total), nil
+}
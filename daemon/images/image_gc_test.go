@@ -0,0 +1,133 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	refstore "github.com/docker/docker/reference"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type mockContainerStore struct {
+	containers []*container.Container
+}
+
+func (s *mockContainerStore) First(container.StoreFilter) *container.Container {
+	return nil
+}
+
+func (s *mockContainerStore) List() []*container.Container {
+	return s.containers
+}
+
+func (s *mockContainerStore) Get(string) *container.Container {
+	return nil
+}
+
+type mockLayerGetReleaser struct{}
+
+func (ls *mockLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
+	return nil, nil
+}
+
+func (ls *mockLayerGetReleaser) Release(layer.Layer) ([]layer.Metadata, error) {
+	return nil, nil
+}
+
+func TestImageGCCandidates(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images-gc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	fs, err := image.NewFSStoreBackend(tmpdir)
+	assert.NilError(t, err)
+	imageStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{
+		runtime.GOOS: &mockLayerGetReleaser{},
+	})
+	assert.NilError(t, err)
+
+	create := func(comment string) image.ID {
+		id, err := imageStore.Create([]byte(`{"comment": "` + comment + `", "created": "2020-01-01T00:00:00Z", "rootfs": {"type": "layers"}}`))
+		assert.NilError(t, err)
+		return id
+	}
+
+	// never used nor tagged, so the creation time applies
+	unused := create("unused")
+	// tagged after creation, but never used
+	tagged := create("tagged")
+	assert.NilError(t, imageStore.SetLastUpdated(tagged))
+	// used after tagged was tagged
+	time.Sleep(10 * time.Millisecond)
+	used := create("used")
+	assert.NilError(t, imageStore.SetLastUsed(used))
+	// used by a container
+	inUse := create("in use")
+	// parent of another image
+	parent := create("parent")
+	child := create("child")
+	assert.NilError(t, imageStore.SetParent(child, parent))
+	time.Sleep(10 * time.Millisecond)
+	assert.NilError(t, imageStore.SetLastUsed(child))
+
+	i := &ImageService{
+		imageStore: imageStore,
+		containers: &mockContainerStore{
+			containers: []*container.Container{{ImageID: inUse}},
+		},
+	}
+
+	var ids []image.ID
+	for _, c := range i.imageGCCandidates() {
+		ids = append(ids, c.id)
+	}
+	assert.Check(t, is.DeepEqual([]image.ID{unused, tagged, used, child}, ids))
+}
+
+func TestImageGCCandidatesPulled(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images-gc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	fs, err := image.NewFSStoreBackend(tmpdir)
+	assert.NilError(t, err)
+	imageStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{
+		runtime.GOOS: &mockLayerGetReleaser{},
+	})
+	assert.NilError(t, err)
+	referenceStore, err := refstore.NewReferenceStore(filepath.Join(tmpdir, "repositories.json"))
+	assert.NilError(t, err)
+
+	// an image created years before a recently created, unused one
+	pulled, err := imageStore.Create([]byte(`{"comment": "pulled", "created": "2015-01-01T00:00:00Z", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+	unused, err := imageStore.Create([]byte(`{"comment": "unused", "created": "` + time.Now().Add(-time.Hour).UTC().Format(time.RFC3339) + `", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+
+	ref, err := reference.ParseNormalizedNamed("example.com/pulled:latest")
+	assert.NilError(t, err)
+	assert.NilError(t, referenceStore.AddTag(ref, pulled.Digest(), true))
+
+	i := &ImageService{
+		imageStore:     imageStore,
+		referenceStore: referenceStore,
+		containers:     &mockContainerStore{},
+	}
+	i.setPulledLastUpdated(ref)
+
+	// the freshly pulled image is removed last
+	var ids []image.ID
+	for _, c := range i.imageGCCandidates() {
+		ids = append(ids, c.id)
+	}
+	assert.Check(t, is.DeepEqual([]image.ID{unused, pulled}, ids))
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"errors"
+)
+
+func diskUsage(path string) (int, error) {
+	return 0, errors.New("image garbage collection is not supported on this platform")
+}
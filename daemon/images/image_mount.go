@@ -0,0 +1,45 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/pkg/errors"
+)
+
+// MountImage mounts the root filesystem of the image read-only, and returns
+// its path. The mount is shared by all users of the image, and must be
+// released with UnmountImage.
+func (i *ImageService) MountImage(img *image.Image) (string, error) {
+	ls, chainID, err := i.readOnlyMountStore(img)
+	if err != nil {
+		return "", err
+	}
+	fs, err := ls.MountReadOnly(chainID)
+	if err != nil {
+		return "", errors.Wrapf(err, "error mounting image %s", img.ID())
+	}
+	return fs.Path(), nil
+}
+
+// UnmountImage releases a mount of the root filesystem of the image made with
+// MountImage.
+func (i *ImageService) UnmountImage(img *image.Image) error {
+	ls, chainID, err := i.readOnlyMountStore(img)
+	if err != nil {
+		return err
+	}
+	return ls.UnmountReadOnly(chainID)
+}
+
+func (i *ImageService) readOnlyMountStore(img *image.Image) (layer.ReadOnlyMountStore, layer.ChainID, error) {
+	chainID := img.RootFS.ChainID()
+	if chainID == "" {
+		return nil, "", errdefs.InvalidParameter(errors.Errorf("image %s has no layers and can not be mounted", img.ID()))
+	}
+	ls, ok := i.layerStores[img.OperatingSystem()].(layer.ReadOnlyMountStore)
+	if !ok {
+		return nil, "", errdefs.NotImplemented(errors.Errorf("mounting images is not supported for %s images", img.OperatingSystem()))
+	}
+	return ls, chainID, nil
+}
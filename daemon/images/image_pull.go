@@ -10,12 +10,13 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution"
-	progressutils "github.com/docker/docker/distribution/utils"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/registry"
 	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
 )
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
@@ -52,39 +53,50 @@ func (i *ImageService) PullImage(ctx context.Context, image, tag string, platfor
 }
 
 func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference.Named, platform *specs.Platform, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
-	// Include a buffer so that slow client connections don't affect
-	// transfer performance.
-	progressChan := make(chan progress.Progress, 100)
-
-	writesDone := make(chan struct{})
+	job := i.transfers.Start("pull", reference.FamiliarString(ref), func(ctx context.Context, progressOutput progress.Output) error {
+		imagePullConfig := &distribution.ImagePullConfig{
+			Config: distribution.Config{
+				MetaHeaders:      metaHeaders,
+				AuthConfig:       authConfig,
+				ProgressOutput:   progressOutput,
+				RegistryService:  i.registryService,
+				ImageEventLogger: i.LogImageEvent,
+				MetadataStore:    i.distributionMetadataStore,
+				ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
+				ReferenceStore:   i.referenceStore,
+			},
+			DownloadManager: i.downloadManager,
+			Schema2Types:    distribution.ImageTypes,
+			Platform:        platform,
+		}
 
-	ctx, cancelFunc := context.WithCancel(ctx)
+		if err := distribution.Pull(ctx, ref, imagePullConfig); err != nil {
+			return err
+		}
+		i.setPulledLastUpdated(ref)
+		return nil
+	})
 
-	go func() {
-		progressutils.WriteDistributionProgress(cancelFunc, outStream, progressChan)
-		close(writesDone)
-	}()
+	return i.watchTransfer(ctx, job, outStream)
+}
 
-	imagePullConfig := &distribution.ImagePullConfig{
-		Config: distribution.Config{
-			MetaHeaders:      metaHeaders,
-			AuthConfig:       authConfig,
-			ProgressOutput:   progress.ChanOutput(progressChan),
-			RegistryService:  i.registryService,
-			ImageEventLogger: i.LogImageEvent,
-			MetadataStore:    i.distributionMetadataStore,
-			ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
-			ReferenceStore:   i.referenceStore,
-		},
-		DownloadManager: i.downloadManager,
-		Schema2Types:    distribution.ImageTypes,
-		Platform:        platform,
+// setPulledLastUpdated records the images pulled for ref as updated now, so
+// that the image garbage collection does not consider them unused since they
+// were created.
+func (i *ImageService) setPulledLastUpdated(ref reference.Named) {
+	var ids []digest.Digest
+	if reference.IsNameOnly(ref) {
+		for _, a := range i.referenceStore.ReferencesByName(ref) {
+			ids = append(ids, a.ID)
+		}
+	} else if id, err := i.referenceStore.Get(ref); err == nil {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		if err := i.imageStore.SetLastUpdated(image.IDFromDigest(id)); err != nil {
+			logrus.WithError(err).WithField("image", id).Warn("Failed to record the last update time of the pulled image")
+		}
 	}
-
-	err := distribution.Pull(ctx, ref, imagePullConfig)
-	close(progressChan)
-	<-writesDone
-	return err
 }
 
 // GetRepository returns a repository from the registry.
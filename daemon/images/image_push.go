@@ -9,7 +9,6 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution"
-	progressutils "github.com/docker/docker/distribution/utils"
 	"github.com/docker/docker/pkg/progress"
 )
 
@@ -28,39 +27,30 @@ func (i *ImageService) PushImage(ctx context.Context, image, tag string, metaHea
 		}
 	}
 
-	// Include a buffer so that slow client connections don't affect
-	// transfer performance.
-	progressChan := make(chan progress.Progress, 100)
-
-	writesDone := make(chan struct{})
-
-	ctx, cancelFunc := context.WithCancel(ctx)
-
-	go func() {
-		progressutils.WriteDistributionProgress(cancelFunc, outStream, progressChan)
-		close(writesDone)
-	}()
+	job := i.transfers.Start("push", reference.FamiliarString(ref), func(ctx context.Context, progressOutput progress.Output) error {
+		imagePushConfig := &distribution.ImagePushConfig{
+			Config: distribution.Config{
+				MetaHeaders:      metaHeaders,
+				AuthConfig:       authConfig,
+				ProgressOutput:   progressOutput,
+				RegistryService:  i.registryService,
+				ImageEventLogger: i.LogImageEvent,
+				MetadataStore:    i.distributionMetadataStore,
+				ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
+				ReferenceStore:   i.referenceStore,
+			},
+			ConfigMediaType: schema2.MediaTypeImageConfig,
+			LayerStores:     distribution.NewLayerProvidersFromStores(i.layerStores),
+			TrustKey:        i.trustKey,
+			UploadManager:   i.uploadManager,
+
+			LayerCompression: i.pushCompression,
+		}
 
-	imagePushConfig := &distribution.ImagePushConfig{
-		Config: distribution.Config{
-			MetaHeaders:      metaHeaders,
-			AuthConfig:       authConfig,
-			ProgressOutput:   progress.ChanOutput(progressChan),
-			RegistryService:  i.registryService,
-			ImageEventLogger: i.LogImageEvent,
-			MetadataStore:    i.distributionMetadataStore,
-			ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
-			ReferenceStore:   i.referenceStore,
-		},
-		ConfigMediaType: schema2.MediaTypeImageConfig,
-		LayerStores:     distribution.NewLayerProvidersFromStores(i.layerStores),
-		TrustKey:        i.trustKey,
-		UploadManager:   i.uploadManager,
-	}
+		return distribution.Push(ctx, ref, imagePushConfig)
+	})
 
-	err = distribution.Push(ctx, ref, imagePushConfig)
-	close(progressChan)
-	<-writesDone
+	err = i.watchTransfer(ctx, job, outStream)
 	imageActions.WithValues("push").UpdateSince(start)
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"runtime"
+	"sync"
 
 	"github.com/docker/docker/container"
 	daemonevents "github.com/docker/docker/daemon/events"
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	dockerreference "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/libtrust"
@@ -38,6 +40,7 @@ type ImageServiceConfig struct {
 	LayerStores               map[string]layer.Store
 	MaxConcurrentDownloads    int
 	MaxConcurrentUploads      int
+	PushCompression           archive.Compression
 	ReferenceStore            dockerreference.Store
 	RegistryService           registry.Service
 	TrustKey                  libtrust.PrivateKey
@@ -52,10 +55,13 @@ func NewImageService(config ImageServiceConfig) *ImageService {
 		distributionMetadataStore: config.DistributionMetadataStore,
 		downloadManager:           xfer.NewLayerDownloadManager(config.LayerStores, config.MaxConcurrentDownloads),
 		eventsService:             config.EventsService,
+		gcStop:                    make(chan struct{}),
 		imageStore:                config.ImageStore,
 		layerStores:               config.LayerStores,
+		pushCompression:           config.PushCompression,
 		referenceStore:            config.ReferenceStore,
 		registryService:           config.RegistryService,
+		transfers:                 xfer.NewJobManager(),
 		trustKey:                  config.TrustKey,
 		uploadManager:             xfer.NewLayerUploadManager(config.MaxConcurrentUploads),
 	}
@@ -67,11 +73,15 @@ type ImageService struct {
 	distributionMetadataStore metadata.Store
 	downloadManager           *xfer.LayerDownloadManager
 	eventsService             *daemonevents.Events
+	gcStop                    chan struct{}
+	gcRunning                 sync.WaitGroup
 	imageStore                image.Store
 	layerStores               map[string]layer.Store // By operating system
 	pruneRunning              int32
+	pushCompression           archive.Compression
 	referenceStore            dockerreference.Store
 	registryService           registry.Service
+	transfers                 *xfer.JobManager
 	trustKey                  libtrust.PrivateKey
 	uploadManager             *xfer.LayerUploadManager
 }
@@ -130,7 +140,18 @@ func (i *ImageService) CreateLayer(container *container.Container, initFunc laye
 
 	// Indexing by OS is safe here as validation of OS has already been performed in create() (the only
 	// caller), and guaranteed non-nil
-	return i.layerStores[container.OS].CreateRWLayer(container.ID, layerID, rwLayerOpts)
+	rwLayer, err := i.layerStores[container.OS].CreateRWLayer(container.ID, layerID, rwLayerOpts)
+	if err != nil {
+		return nil, err
+	}
+	if container.ImageID != "" {
+		// the last used time is used to decide which images to remove
+		// first when garbage collecting images.
+		if err := i.imageStore.SetLastUsed(container.ImageID); err != nil {
+			logrus.Warnf("failed to record last used time of image %s: %v", container.ImageID, err)
+		}
+	}
+	return rwLayer, nil
 }
 
 // GetLayerByID returns a layer by ID and operating system
@@ -160,6 +181,8 @@ func (i *ImageService) GetLayerMountID(cid string, os string) (string, error) {
 // Cleanup resources before the process is shutdown.
 // called from daemon.go Daemon.Shutdown()
 func (i *ImageService) Cleanup() {
+	close(i.gcStop)
+	i.gcRunning.Wait()
 	for os, ls := range i.layerStores {
 		if ls != nil {
 			if err := ls.Cleanup(); err != nil {
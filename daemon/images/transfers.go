@@ -0,0 +1,113 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	progressutils "github.com/docker/docker/distribution/utils"
+	"github.com/docker/docker/distribution/xfer"
+	"github.com/docker/docker/pkg/progress"
+)
+
+// Transfers returns the image pulls and pushes which are running, or which
+// finished recently.
+func (i *ImageService) Transfers() []*types.Transfer {
+	jobs := i.transfers.List()
+	transfers := make([]*types.Transfer, 0, len(jobs))
+	for _, job := range jobs {
+		t := &types.Transfer{
+			ID:      job.ID,
+			Action:  job.Action,
+			Ref:     job.Ref,
+			Created: job.Created,
+		}
+		status, finished := job.Status()
+		t.Status = status
+		if status != xfer.JobRunning {
+			t.Finished = &finished
+			if err := job.Err(); err != nil {
+				t.Error = err.Error()
+			}
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers
+}
+
+// TransferAttach writes the progress of the transfer with the given ID to
+// outStream until the transfer finishes or ctx is cancelled. Cancelling ctx
+// detaches from the transfer, but does not cancel it.
+func (i *ImageService) TransferAttach(ctx context.Context, id string, outStream io.Writer) error {
+	job, err := i.transfers.Get(id)
+	if err != nil {
+		return err
+	}
+	return i.watchTransfer(ctx, job, outStream)
+}
+
+// TransferCancel cancels the transfer with the given ID.
+func (i *ImageService) TransferCancel(id string) error {
+	job, err := i.transfers.Get(id)
+	if err != nil {
+		return err
+	}
+	job.Cancel()
+	return nil
+}
+
+// transferOutput prefixes the progress of a transfer with a message holding
+// the ID of the transfer. The ID is not sent before the transfer reported
+// progress, so that errors which occur early, such as an image that does not
+// exist, are still returned before the response is flushed.
+type transferOutput struct {
+	out  progress.Output
+	id   string
+	sent bool
+}
+
+func (o *transferOutput) WriteProgress(p progress.Progress) error {
+	if !o.sent {
+		o.sent = true
+		progress.Aux(o.out, types.TransferStarted{TransferID: o.id})
+	}
+	return o.out.WriteProgress(p)
+}
+
+// watchTransfer streams the progress of job to outStream as JSON messages,
+// starting with the ID of the transfer, and returns the result of the job once
+// it finished. If ctx is cancelled, or the client goes away, it returns
+// without waiting for the job; the job keeps running and can be reattached to.
+func (i *ImageService) watchTransfer(ctx context.Context, job *xfer.Job, outStream io.Writer) error {
+	// Include a buffer so that slow client connections don't affect
+	// transfer performance.
+	progressChan := make(chan progress.Progress, 100)
+
+	writesDone := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	go func() {
+		progressutils.WriteDistributionProgress(cancelFunc, outStream, progressChan)
+		close(writesDone)
+	}()
+
+	watcher := job.Watch(&transferOutput{
+		out: progress.ChanOutput(progressChan),
+		id:  job.ID,
+	})
+
+	var err error
+	select {
+	case <-job.Done():
+		err = job.Err()
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	job.Release(watcher)
+	close(progressChan)
+	<-writesDone
+	return err
+}
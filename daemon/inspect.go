@@ -177,6 +177,7 @@ func (daemon *Daemon) getInspectData(container *container.Container) (*types.Con
 		ProcessLabel: container.ProcessLabel,
 		ExecIDs:      container.GetExecIDs(),
 		HostConfig:   &hostConfig,
+		DebugTarget:  container.DebugTarget,
 	}
 
 	// Now set any platform-specific fields
@@ -232,6 +233,7 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 		ContainerID:   e.ContainerID,
 		DetachKeys:    e.DetachKeys,
 		Pid:           e.Pid,
+		Detachable:    e.Detachable,
 	}, nil
 }
 
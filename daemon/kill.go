@@ -69,16 +69,7 @@ func (daemon *Daemon) killWithSignal(container *containerpkg.Container, sig int)
 	}
 
 	var unpause bool
-	if container.Config.StopSignal != "" && syscall.Signal(sig) != syscall.SIGKILL {
-		containerStopSignal, err := signal.ParseSignal(container.Config.StopSignal)
-		if err != nil {
-			return err
-		}
-		if containerStopSignal == syscall.Signal(sig) {
-			container.ExitOnNext()
-			unpause = container.Paused
-		}
-	} else {
+	if container.IsStopSignal(syscall.Signal(sig)) {
 		container.ExitOnNext()
 		unpause = container.Paused
 	}
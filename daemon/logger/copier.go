@@ -26,6 +26,7 @@ const (
 type Copier struct {
 	// srcs is map of name -> reader pairs, for example "stdout", "stderr"
 	srcs      map[string]io.Reader
+	dstMu     sync.RWMutex
 	dst       Logger
 	copyJobs  sync.WaitGroup
 	closeOnce sync.Once
@@ -53,9 +54,11 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	defer c.copyJobs.Done()
 
 	bufSize := defaultBufSize
+	c.dstMu.RLock()
 	if sizedLogger, ok := c.dst.(SizedLogger); ok {
 		bufSize = sizedLogger.BufSize()
 	}
+	c.dstMu.RUnlock()
 	buf := make([]byte, bufSize)
 
 	n := 0
@@ -120,10 +123,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 						msg.Timestamp = partialTS
 					}
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					c.log(msg)
 				}
 				p += q + 1
 			}
@@ -153,10 +153,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 					ordinal++
 					hasMorePartial = true
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					c.log(msg)
 					p = 0
 					n = 0
 				}
@@ -173,6 +170,26 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 }
 
+func (c *Copier) log(msg *Message) {
+	c.dstMu.RLock()
+	defer c.dstMu.RUnlock()
+	if logErr := c.dst.Log(msg); logErr != nil {
+		logWritesFailedCount.Inc(1)
+		logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
+	}
+}
+
+// SetLogger replaces the logger the logs are copied to, and returns the
+// previous one. The previous logger is not used anymore once SetLogger
+// returns, and can be closed by the caller.
+func (c *Copier) SetLogger(dst Logger) Logger {
+	c.dstMu.Lock()
+	defer c.dstMu.Unlock()
+	prev := c.dst
+	c.dst = dst
+	return prev
+}
+
 // Wait waits until all copying is done
 func (c *Copier) Wait() {
 	c.copyJobs.Wait()
@@ -223,6 +223,58 @@ func TestCopierSlow(t *testing.T) {
 	}
 }
 
+type chanLogger chan *Message
+
+func (l chanLogger) Log(m *Message) error {
+	l <- m
+	return nil
+}
+
+func (chanLogger) Close() error { return nil }
+
+func (chanLogger) Name() string { return "chan" }
+
+func TestCopierSetLogger(t *testing.T) {
+	r, w := io.Pipe()
+	first, second := make(chanLogger, 1), make(chanLogger, 1)
+	c := NewCopier(map[string]io.Reader{"stdout": r}, first)
+	c.Run()
+	defer c.Close()
+
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-first:
+		if string(msg.Line) != "first" {
+			t.Fatalf("Wrong Line: %q, expected %q", msg.Line, "first")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("first logger did not receive the message in time")
+	}
+
+	if prev := c.SetLogger(second); prev != first {
+		t.Fatalf("SetLogger returned %v, expected the first logger", prev)
+	}
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-second:
+		if string(msg.Line) != "second" {
+			t.Fatalf("Wrong Line: %q, expected %q", msg.Line, "second")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second logger did not receive the message in time")
+	}
+	w.Close()
+	c.Wait()
+
+	if len(first) != 0 {
+		t.Fatalf("first logger received a message after it was replaced: %q", (<-first).Line)
+	}
+}
+
 func TestCopierWithSized(t *testing.T) {
 	var jsonBuf bytes.Buffer
 	expectedMsgs := 2
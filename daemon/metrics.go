@@ -3,6 +3,7 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"sync"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/plugingetter"
 	"github.com/docker/docker/pkg/plugins"
@@ -24,7 +25,8 @@ var (
 	healthChecksCounter       metrics.Counter
 	healthChecksFailedCounter metrics.Counter
 
-	stateCtr *stateCounter
+	stateCtr    *stateCounter
+	pressureCtr *pressureCollector
 )
 
 func init() {
@@ -61,6 +63,9 @@ func init() {
 	stateCtr = newStateCounter(ns.NewDesc("container_states", "The count of containers in various states", metrics.Unit("containers"), "state"))
 	ns.Add(stateCtr)
 
+	pressureCtr = newPressureCollector(ns)
+	ns.Add(pressureCtr)
+
 	metrics.Register(ns)
 }
 
@@ -115,6 +120,90 @@ func (ctr *stateCounter) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(ctr.desc, prometheus.GaugeValue, float64(stopped), "stopped")
 }
 
+// pressureCollector exports the pressure stall information and the OOM event
+// counts of the running containers, which are read from their cgroups when the
+// metrics are collected.
+type pressureCollector struct {
+	mu     sync.Mutex
+	daemon *Daemon
+
+	stalled       *prometheus.Desc
+	pressure      *prometheus.Desc
+	oomEvents     *prometheus.Desc
+	oomKillEvents *prometheus.Desc
+}
+
+func newPressureCollector(ns *metrics.Namespace) *pressureCollector {
+	return &pressureCollector{
+		stalled:       ns.NewDesc("container_pressure_stalled_seconds", "The total time tasks of the container were stalled on a resource", metrics.Total, "container_id", "resource", "kind"),
+		pressure:      ns.NewDesc("container_pressure", "The share of time tasks of the container were stalled on a resource, averaged over a window", metrics.Unit("ratio"), "container_id", "resource", "kind", "window"),
+		oomEvents:     ns.NewDesc("container_oom_events", "The number of times the memory usage of the container reached its limit and the OOM killer was invoked", metrics.Total, "container_id"),
+		oomKillEvents: ns.NewDesc("container_oom_kill_events", "The number of processes of the container killed by the OOM killer", metrics.Total, "container_id"),
+	}
+}
+
+// setDaemon sets the daemon whose containers are collected.
+func (ctr *pressureCollector) setDaemon(d *Daemon) {
+	ctr.mu.Lock()
+	ctr.daemon = d
+	ctr.mu.Unlock()
+}
+
+func (ctr *pressureCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ctr.stalled
+	ch <- ctr.pressure
+	ch <- ctr.oomEvents
+	ch <- ctr.oomKillEvents
+}
+
+func (ctr *pressureCollector) Collect(ch chan<- prometheus.Metric) {
+	ctr.mu.Lock()
+	d := ctr.daemon
+	ctr.mu.Unlock()
+	if d == nil {
+		return
+	}
+
+	for _, c := range d.List() {
+		if !c.IsRunning() {
+			continue
+		}
+		var s types.Stats
+		if err := readPressureStats(c.GetPID(), &s); err != nil {
+			logrus.WithError(err).WithField("container", c.ID).Debug("Error reading pressure stats of container")
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(ctr.oomEvents, prometheus.CounterValue, float64(s.MemoryStats.OOMEvents), c.ID)
+		ch <- prometheus.MustNewConstMetric(ctr.oomKillEvents, prometheus.CounterValue, float64(s.MemoryStats.OOMKillEvents), c.ID)
+		if s.PressureStats == nil {
+			continue
+		}
+		for resource, p := range map[string]*types.Pressure{
+			"cpu":    s.PressureStats.CPU,
+			"memory": s.PressureStats.Memory,
+			"io":     s.PressureStats.IO,
+		} {
+			if p == nil {
+				continue
+			}
+			ctr.collectPressure(ch, c.ID, resource, "some", p.Some)
+			ctr.collectPressure(ch, c.ID, resource, "full", p.Full)
+		}
+	}
+}
+
+func (ctr *pressureCollector) collectPressure(ch chan<- prometheus.Metric, id, resource, kind string, data *types.PressureData) {
+	if data == nil {
+		return
+	}
+	// The kernel reports the total in microseconds, and the averages as
+	// percentages.
+	ch <- prometheus.MustNewConstMetric(ctr.stalled, prometheus.CounterValue, float64(data.Total)/1e6, id, resource, kind)
+	ch <- prometheus.MustNewConstMetric(ctr.pressure, prometheus.GaugeValue, data.Avg10/100, id, resource, kind, "10s")
+	ch <- prometheus.MustNewConstMetric(ctr.pressure, prometheus.GaugeValue, data.Avg60/100, id, resource, kind, "60s")
+	ch <- prometheus.MustNewConstMetric(ctr.pressure, prometheus.GaugeValue, data.Avg300/100, id, resource, kind, "300s")
+}
+
 func (d *Daemon) cleanupMetricsPlugins() {
 	ls := d.PluginStore.GetAllManagedPluginsByCap(metricsPluginType)
 	var wg sync.WaitGroup
@@ -0,0 +1,523 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/libnetwork"
+	"github.com/docker/libnetwork/iptables"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// networkLimitChainPrefix is the prefix of the iptables chains holding
+	// the connection limits of the endpoints, followed by the truncated ID of
+	// the endpoint.
+	networkLimitChainPrefix = "DOCKER-LIMIT-"
+
+	// networkLimitLatency is the maximum time packets are queued on the rate
+	// limited interfaces before being dropped.
+	networkLimitLatency = 50 * time.Millisecond
+
+	// networkLimitMinBurst is the minimum burst of the rate limited
+	// interfaces, which must hold the largest segmentation offloaded packets.
+	networkLimitMinBurst = 64 * 1024
+
+	// networkLimitIfbPrefix is the prefix of the ifb interfaces limiting the
+	// egress rate of the endpoints, followed by the start of the ID of the
+	// endpoint.
+	networkLimitIfbPrefix = "dkifb"
+)
+
+// hasNetworkLimits returns whether the container has network limits.
+func hasNetworkLimits(c *container.Container) bool {
+	r := c.HostConfig.Resources
+	return r.NetworkIngressRate > 0 || r.NetworkEgressRate > 0 || r.NetworkConntrackMax > 0
+}
+
+// applyNetworkLimits applies the network limits of the container to its
+// endpoint ep, which joined the sandbox sb. Only the endpoints on bridge
+// networks are limited.
+func (daemon *Daemon) applyNetworkLimits(c *container.Container, n libnetwork.Network, ep libnetwork.Endpoint, sb libnetwork.Sandbox) error {
+	if n.Type() != "bridge" {
+		if hasNetworkLimits(c) {
+			logrus.WithField("container", c.ID).Debugf("Network limits are not applied on network %s of type %s", n.Name(), n.Type())
+		}
+		return nil
+	}
+	return daemon.setNetworkLimits(ep, sb, c.HostConfig.Resources)
+}
+
+// setNetworkLimits sets the network limits of the resources r on the endpoint
+// ep, which joined the sandbox sb. The rates are limited by tbf qdiscs on the
+// host side of the veth pair of the endpoint, out of reach of the container,
+// the connections by connlimit rules in the iptables chain of the endpoint.
+func (daemon *Daemon) setNetworkLimits(ep libnetwork.Endpoint, sb libnetwork.Sandbox, r containertypes.Resources) error {
+	nsh, err := netns.GetFromPath(sb.Key())
+	if err != nil {
+		return errors.Wrap(err, "failed to open the network namespace of the container")
+	}
+	defer nsh.Close()
+	inner, err := netlink.NewHandleAt(nsh)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the network namespace of the container")
+	}
+	defer inner.Delete()
+	host, err := netlink.NewHandle()
+	if err != nil {
+		return err
+	}
+	defer host.Delete()
+
+	_, hostLink, err := endpointVeth(inner, host, ep)
+	if err != nil {
+		return err
+	}
+	// The data sent by the container enters the host through the host side
+	// of the veth pair, and is redirected to an ifb interface limiting its
+	// rate. The data it receives leaves through the host side.
+	if err := setEgressRateLimit(host, hostLink, networkLimitIfb(ep), r.NetworkEgressRate); err != nil {
+		return errors.Wrap(err, "failed to limit the egress rate")
+	}
+	if err := setRateLimit(host, hostLink, r.NetworkIngressRate); err != nil {
+		return errors.Wrap(err, "failed to limit the ingress rate")
+	}
+
+	if !daemon.configStore.BridgeConfig.EnableIPTables {
+		return nil
+	}
+	return errors.Wrap(setConnectionLimit(ep, r.NetworkConntrackMax), "failed to limit the connections")
+}
+
+// updateNetworkLimits applies the network limits of the running container
+// to all its endpoints. If an endpoint cannot be updated, the previous limits
+// are set again on the endpoints, so that they are all left unchanged.
+func (daemon *Daemon) updateNetworkLimits(c *container.Container, previous containertypes.Resources) error {
+	sb := daemon.getNetworkSandbox(c)
+	if sb == nil {
+		return nil
+	}
+	for _, ep := range sb.Endpoints() {
+		n, err := daemon.FindNetwork(ep.Network())
+		if err == nil {
+			err = daemon.applyNetworkLimits(c, n, ep, sb)
+		}
+		if err != nil {
+			daemon.restoreNetworkLimits(c, sb, previous)
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreNetworkLimits sets the previous network limits of the container on
+// its endpoints on bridge networks, after a failed update.
+func (daemon *Daemon) restoreNetworkLimits(c *container.Container, sb libnetwork.Sandbox, previous containertypes.Resources) {
+	for _, ep := range sb.Endpoints() {
+		n, err := daemon.FindNetwork(ep.Network())
+		if err != nil || n.Type() != "bridge" {
+			continue
+		}
+		if err := daemon.setNetworkLimits(ep, sb, previous); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{"container": c.ID, "endpoint": ep.ID()}).Warn("Failed to restore the network limits of the endpoint")
+		}
+	}
+}
+
+// removeNetworkLimits removes the ifb interface and the iptables chain of the
+// endpoint, the qdiscs being removed with the veth pair.
+func (daemon *Daemon) removeNetworkLimits(ep libnetwork.Endpoint) {
+	if err := removeIfb(networkLimitIfb(ep)); err != nil {
+		logrus.WithError(err).WithField("endpoint", ep.ID()).Warn("Failed to remove the egress rate limit of the endpoint")
+	}
+	if !daemon.configStore.BridgeConfig.EnableIPTables {
+		return
+	}
+	if err := setConnectionLimit(ep, 0); err != nil {
+		logrus.WithError(err).WithField("endpoint", ep.ID()).Warn("Failed to remove the connection limits of the endpoint")
+	}
+}
+
+// setNetworkLimitStats sets the network limits of the container in the stats
+// of the interfaces of its endpoints on bridge networks. The limits reported
+// are the ones configured in the host config of the container, the qdiscs and
+// iptables rules of the endpoints are not read back.
+func (daemon *Daemon) setNetworkLimitStats(c *container.Container, sb libnetwork.Sandbox, stats map[string]types.NetworkStats) error {
+	if c.HostConfig.NetworkMode.IsContainer() || !hasNetworkLimits(c) {
+		return nil
+	}
+
+	nsh, err := netns.GetFromPath(sb.Key())
+	if err != nil {
+		return err
+	}
+	defer nsh.Close()
+	inner, err := netlink.NewHandleAt(nsh)
+	if err != nil {
+		return err
+	}
+	defer inner.Delete()
+
+	for _, ep := range sb.Endpoints() {
+		n, err := daemon.FindNetwork(ep.Network())
+		if err != nil || n.Type() != "bridge" {
+			continue
+		}
+		link, err := endpointLink(inner, ep)
+		if err != nil {
+			continue
+		}
+		s, ok := stats[link.Attrs().Name]
+		if !ok {
+			continue
+		}
+		s.RxRateLimit = uint64(c.HostConfig.NetworkIngressRate)
+		s.TxRateLimit = uint64(c.HostConfig.NetworkEgressRate)
+		s.ConntrackLimit = uint64(c.HostConfig.NetworkConntrackMax)
+		stats[link.Attrs().Name] = s
+	}
+	return nil
+}
+
+// endpointLink returns the interface of the endpoint in the network
+// namespace of the handle, identified by its MAC address.
+func endpointLink(h *netlink.Handle, ep libnetwork.Endpoint) (netlink.Link, error) {
+	var mac []byte
+	if info := ep.Info(); info != nil && info.Iface() != nil {
+		mac = info.Iface().MacAddress()
+	}
+	if len(mac) == 0 {
+		return nil, errors.Errorf("endpoint %s has no MAC address", ep.ID())
+	}
+	links, err := h.LinkList()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		if bytes.Equal(l.Attrs().HardwareAddr, mac) {
+			return l, nil
+		}
+	}
+	return nil, errors.Errorf("cannot find the interface of endpoint %s", ep.ID())
+}
+
+// endpointVeth returns both sides of the veth pair of the endpoint, the one
+// in the network namespace of the container, and its peer attached to the
+// bridge of the network.
+func endpointVeth(inner, host *netlink.Handle, ep libnetwork.Endpoint) (netlink.Link, netlink.Link, error) {
+	innerLink, err := endpointLink(inner, ep)
+	if err != nil {
+		return nil, nil, err
+	}
+	if innerLink.Type() != "veth" || innerLink.Attrs().ParentIndex == 0 {
+		return nil, nil, errors.Errorf("interface of endpoint %s is not a veth", ep.ID())
+	}
+	hostLink, err := host.LinkByIndex(innerLink.Attrs().ParentIndex)
+	if err != nil || hostLink.Type() != "veth" || hostLink.Attrs().MasterIndex == 0 {
+		return nil, nil, errors.Errorf("cannot find the peer of the interface of endpoint %s", ep.ID())
+	}
+	return innerLink, hostLink, nil
+}
+
+// endpointHostVeth returns the name of the host side of the veth pair of the
+// endpoint ep, which joined the sandbox sb.
+func endpointHostVeth(ep libnetwork.Endpoint, sb libnetwork.Sandbox) (string, error) {
+	nsh, err := netns.GetFromPath(sb.Key())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open the network namespace of the container")
+	}
+	defer nsh.Close()
+	inner, err := netlink.NewHandleAt(nsh)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open the network namespace of the container")
+	}
+	defer inner.Delete()
+	host, err := netlink.NewHandle()
+	if err != nil {
+		return "", err
+	}
+	defer host.Delete()
+
+	_, hostLink, err := endpointVeth(inner, host, ep)
+	if err != nil {
+		return "", err
+	}
+	return hostLink.Attrs().Name, nil
+}
+
+// setRateLimit limits the rate of the data sent on the link to rate bytes per
+// second, or removes the limit if rate is not positive.
+func setRateLimit(h *netlink.Handle, link netlink.Link, rate int64) error {
+	qdiscs, err := h.QdiscList(link)
+	if err != nil {
+		return err
+	}
+	if rate <= 0 {
+		for _, q := range qdiscs {
+			if q.Attrs().Parent == netlink.HANDLE_ROOT && q.Type() == "tbf" {
+				return h.QdiscDel(q)
+			}
+		}
+		return nil
+	}
+	return h.QdiscReplace(rateLimitQdisc(link.Attrs().Index, uint64(rate)))
+}
+
+// networkLimitIfb returns the name of the ifb interface limiting the egress
+// rate of the endpoint.
+func networkLimitIfb(ep libnetwork.Endpoint) string {
+	id := ep.ID()
+	if len(id) > 10 {
+		id = id[:10]
+	}
+	return networkLimitIfbPrefix + id
+}
+
+// setEgressRateLimit limits the rate of the data received on link, the host
+// side of the veth pair of an endpoint, to rate bytes per second, or removes
+// the limit if rate is not positive. The data is redirected to the ifb
+// interface ifbName, whose rate is limited by a tbf qdisc.
+func setEgressRateLimit(h *netlink.Handle, link netlink.Link, ifbName string, rate int64) error {
+	if rate <= 0 {
+		if err := removeIngressQdisc(h, link); err != nil {
+			return err
+		}
+		return removeIfb(ifbName)
+	}
+
+	ifb, err := h.LinkByName(ifbName)
+	if _, ok := err.(netlink.LinkNotFoundError); ok {
+		if err := h.LinkAdd(&netlink.Ifb{LinkAttrs: netlink.LinkAttrs{Name: ifbName, TxQLen: 1000}}); err != nil {
+			return err
+		}
+		ifb, err = h.LinkByName(ifbName)
+	}
+	if err != nil {
+		return err
+	}
+	if err := h.LinkSetUp(ifb); err != nil {
+		return err
+	}
+	if err := setRateLimit(h, ifb, rate); err != nil {
+		return err
+	}
+	return redirectIngress(h, link, ifb)
+}
+
+// redirectIngress redirects all the data received on link to the ifb
+// interface, with a filter of the ingress qdisc of link.
+func redirectIngress(h *netlink.Handle, link, ifb netlink.Link) error {
+	parent := netlink.MakeHandle(0xffff, 0)
+	filters, err := h.FilterList(link, parent)
+	if err == nil {
+		for _, f := range filters {
+			if u32, ok := f.(*netlink.U32); ok && u32.RedirIndex == ifb.Attrs().Index {
+				return nil
+			}
+		}
+	}
+
+	if err := removeIngressQdisc(h, link); err != nil {
+		return err
+	}
+	if err := h.QdiscAdd(&netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    parent,
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}); err != nil {
+		return err
+	}
+	// A u32 filter without selector matches all the packets.
+	return h.FilterAdd(&netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    parent,
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: []netlink.Action{netlink.NewMirredAction(ifb.Attrs().Index)},
+	})
+}
+
+// removeIngressQdisc removes the ingress qdisc of the link, and its filters.
+func removeIngressQdisc(h *netlink.Handle, link netlink.Link) error {
+	qdiscs, err := h.QdiscList(link)
+	if err != nil {
+		return err
+	}
+	for _, q := range qdiscs {
+		if q.Attrs().Parent == netlink.HANDLE_INGRESS {
+			return h.QdiscDel(q)
+		}
+	}
+	return nil
+}
+
+// removeIfb removes the ifb interface with the given name, if it exists.
+func removeIfb(name string) error {
+	ifb, err := netlink.LinkByName(name)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+	return netlink.LinkDel(ifb)
+}
+
+// rateLimitQdisc returns the tbf qdisc limiting the rate of the interface to
+// rate bytes per second. The burst is the data sent in 10ms at that rate.
+func rateLimitQdisc(index int, rate uint64) *netlink.Tbf {
+	burst := rate / 100
+	if burst < networkLimitMinBurst {
+		burst = networkLimitMinBurst
+	} else if burst > math.MaxUint32 {
+		burst = math.MaxUint32
+	}
+	limit := burst + rate*uint64(networkLimitLatency)/uint64(time.Second)
+	if limit > math.MaxUint32 {
+		limit = math.MaxUint32
+	}
+	return &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  uint32(limit),
+		Buffer: uint32(netlink.Xmittime(rate, uint32(burst))),
+	}
+}
+
+func networkLimitChain(ep libnetwork.Endpoint) string {
+	return networkLimitChainPrefix + stringid.TruncateID(ep.ID())
+}
+
+// setConnectionLimit limits the number of connections tracked from and to the
+// IPv4 address of the endpoint to max in each direction, or removes the limit
+// if max is not positive. New connections above the limit are rejected.
+func setConnectionLimit(ep libnetwork.Endpoint, max int64) error {
+	chain := networkLimitChain(ep)
+	var addr string
+	if info := ep.Info(); info != nil && info.Iface() != nil && info.Iface().Address() != nil {
+		addr = info.Iface().Address().IP.String()
+	}
+	if max <= 0 || addr == "" {
+		if !iptables.ExistChain(chain, iptables.Filter) {
+			return nil
+		}
+		if err := iptables.ProgramRule(iptables.Filter, "FORWARD", iptables.Delete, []string{"-j", chain}); err != nil {
+			return err
+		}
+		return iptables.RemoveExistingChain(chain, iptables.Filter)
+	}
+
+	above := strconv.FormatInt(max, 10)
+	rules := [][]string{
+		{"-s", addr, "-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit", "--connlimit-above", above, "--connlimit-mask", "32", "--connlimit-saddr", "-j", "REJECT"},
+		{"-d", addr, "-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit", "--connlimit-above", above, "--connlimit-mask", "32", "--connlimit-daddr", "-j", "REJECT"},
+	}
+	if _, err := iptables.NewChain(chain, iptables.Filter, false); err != nil {
+		return err
+	}
+	// Keep the rules if the limit did not change, as the connections
+	// counted by the rules would be forgotten.
+	if !iptables.Exists(iptables.Filter, chain, rules[0]...) || !iptables.Exists(iptables.Filter, chain, rules[1]...) {
+		if _, err := iptables.Raw("-t", string(iptables.Filter), "-F", chain); err != nil {
+			return err
+		}
+		for _, args := range rules {
+			if err := iptables.ProgramRule(iptables.Filter, chain, iptables.Append, args); err != nil {
+				return err
+			}
+		}
+	}
+	if iptables.Exists(iptables.Filter, "FORWARD", "-j", chain) {
+		return nil
+	}
+	return iptables.RawCombinedOutput("-I", "FORWARD", "-j", chain)
+}
+
+// cleanupEndpointChains removes the iptables chains of the network limits and
+// egress policies of the endpoints which are not in a sandbox restored by the
+// network controller.
+func (daemon *Daemon) cleanupEndpointChains() {
+	if !daemon.configStore.BridgeConfig.EnableIPTables {
+		return
+	}
+	out, err := iptables.Raw("-t", string(iptables.Filter), "-S")
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list the iptables chains")
+		return
+	}
+
+	active := make(map[string]bool)
+	for _, sb := range daemon.netController.Sandboxes() {
+		for _, ep := range sb.Endpoints() {
+			active[networkLimitChain(ep)] = true
+			active[egressPolicyChain(ep)] = true
+		}
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "-N" || active[fields[1]] {
+			continue
+		}
+		if !strings.HasPrefix(fields[1], networkLimitChainPrefix) && !strings.HasPrefix(fields[1], egressPolicyChainPrefix) {
+			continue
+		}
+		chain := fields[1]
+		if strings.HasPrefix(chain, egressPolicyChainPrefix) {
+			if err := removeEgressPolicyChain(chain); err != nil {
+				logrus.WithError(err).Warnf("Failed to remove iptables chain %s", chain)
+			}
+			continue
+		}
+		if err := iptables.ProgramRule(iptables.Filter, "FORWARD", iptables.Delete, []string{"-j", chain}); err != nil {
+			logrus.WithError(err).Warnf("Failed to remove iptables chain %s", chain)
+			continue
+		}
+		iptables.RemoveExistingChain(chain, iptables.Filter)
+	}
+}
+
+// cleanupNetworkLimitIfbs removes the ifb interfaces of the network limits of
+// the endpoints which are not in a sandbox restored by the network
+// controller.
+func (daemon *Daemon) cleanupNetworkLimitIfbs() {
+	links, err := netlink.LinkList()
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to list the network interfaces")
+		return
+	}
+
+	active := make(map[string]bool)
+	for _, sb := range daemon.netController.Sandboxes() {
+		for _, ep := range sb.Endpoints() {
+			active[networkLimitIfb(ep)] = true
+		}
+	}
+	for _, l := range links {
+		name := l.Attrs().Name
+		if l.Type() != "ifb" || !strings.HasPrefix(name, networkLimitIfbPrefix) || active[name] {
+			continue
+		}
+		if err := netlink.LinkDel(l); err != nil {
+			logrus.WithError(err).Warnf("Failed to remove interface %s", name)
+		}
+	}
+}
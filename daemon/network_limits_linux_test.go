@@ -0,0 +1,79 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestRateLimitQdisc(t *testing.T) {
+	for _, tc := range []struct {
+		rate  uint64
+		burst uint32
+		limit uint32
+	}{
+		{rate: 1024, burst: networkLimitMinBurst, limit: networkLimitMinBurst + 51},
+		{rate: 100 * 1024 * 1024, burst: 1048576, limit: 1048576 + 5242880},
+		{rate: 1 << 40, burst: math.MaxUint32, limit: math.MaxUint32},
+	} {
+		q := rateLimitQdisc(3, tc.rate)
+		assert.Check(t, is.Equal(q.LinkIndex, 3))
+		assert.Check(t, is.Equal(q.Parent, uint32(netlink.HANDLE_ROOT)))
+		assert.Check(t, is.Equal(q.Rate, tc.rate))
+		assert.Check(t, is.Equal(q.Limit, tc.limit))
+		assert.Check(t, is.Equal(q.Buffer, uint32(netlink.Xmittime(tc.rate, tc.burst))))
+	}
+}
+
+func TestSetEgressRateLimit(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires root")
+
+	h, err := netlink.NewHandle()
+	assert.NilError(t, err)
+	defer h.Delete()
+
+	veth := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "dktestveth0"}, PeerName: "dktestveth1"}
+	if err := h.LinkAdd(veth); err != nil {
+		t.Skipf("cannot create veth pair: %v", err)
+	}
+	defer h.LinkDel(veth)
+	link, err := h.LinkByName(veth.Name)
+	assert.NilError(t, err)
+
+	const ifbName = networkLimitIfbPrefix + "test"
+	defer removeIfb(ifbName)
+	if err := setEgressRateLimit(h, link, ifbName, 1024*1024); err != nil {
+		t.Skipf("cannot limit the egress rate: %v", err)
+	}
+	// Setting the same limit again is a no-op.
+	assert.NilError(t, setEgressRateLimit(h, link, ifbName, 1024*1024))
+
+	// The data received on the host side of the veth pair is redirected to
+	// the ifb interface, which limits its rate.
+	ifb, err := h.LinkByName(ifbName)
+	assert.NilError(t, err)
+	filters, err := h.FilterList(link, netlink.MakeHandle(0xffff, 0))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(filters, 1))
+	assert.Check(t, is.Equal(filters[0].(*netlink.U32).RedirIndex, ifb.Attrs().Index))
+	qdiscs, err := h.QdiscList(ifb)
+	assert.NilError(t, err)
+	var rate uint64
+	for _, q := range qdiscs {
+		if tbf, ok := q.(*netlink.Tbf); ok {
+			rate = tbf.Rate
+		}
+	}
+	assert.Check(t, is.Equal(rate, uint64(1024*1024)))
+
+	assert.NilError(t, setEgressRateLimit(h, link, ifbName, 0))
+	_, err = h.LinkByName(ifbName)
+	assert.Check(t, is.ErrorType(err, netlink.LinkNotFoundError{}))
+	filters, err = h.FilterList(link, netlink.MakeHandle(0xffff, 0))
+	assert.Check(t, err != nil || len(filters) == 0)
+}
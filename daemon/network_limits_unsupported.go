@@ -0,0 +1,28 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/libnetwork"
+)
+
+func (daemon *Daemon) applyNetworkLimits(c *container.Container, n libnetwork.Network, ep libnetwork.Endpoint, sb libnetwork.Sandbox) error {
+	return nil
+}
+
+func (daemon *Daemon) updateNetworkLimits(c *container.Container, previous containertypes.Resources) error {
+	return nil
+}
+
+func (daemon *Daemon) removeNetworkLimits(ep libnetwork.Endpoint) {}
+
+func (daemon *Daemon) cleanupEndpointChains() {}
+
+func (daemon *Daemon) cleanupNetworkLimitIfbs() {}
+
+func (daemon *Daemon) setNetworkLimitStats(c *container.Container, sb libnetwork.Sandbox, stats map[string]types.NetworkStats) error {
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/docker/docker/oci/caps"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/rootless/specconv"
 	volumemounts "github.com/docker/docker/volume/mounts"
 	"github.com/opencontainers/runc/libcontainer/apparmor"
@@ -305,6 +306,22 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 		if c.HostConfig.UTSMode.IsHost() {
 			oci.RemoveNamespace(s, specs.LinuxNamespaceType("uts"))
 			s.Hostname = ""
+		} else if c.HostConfig.UTSMode.IsContainer() {
+			ns := specs.LinuxNamespace{Type: "uts"}
+			uc, err := daemon.getUTSContainer(c.HostConfig.UTSMode.Container())
+			if err != nil {
+				return err
+			}
+			ns.Path = fmt.Sprintf("/proc/%d/ns/uts", uc.State.GetPID())
+			setNamespace(s, ns)
+			// The hostname is the one of the other container.
+			s.Hostname = ""
+			if userNS {
+				// to share a UTS namespace, they must also share a user namespace
+				nsUser := specs.LinuxNamespace{Type: "user"}
+				nsUser.Path = fmt.Sprintf("/proc/%d/ns/user", uc.State.GetPID())
+				setNamespace(s, nsUser)
+			}
 		}
 
 		// cgroup
@@ -324,6 +341,27 @@ func WithNamespaces(daemon *Daemon, c *container.Container) coci.SpecOpts {
 	}
 }
 
+// WithDebugTarget mounts the root filesystem of the container debugged by the
+// debug container c at debugTargetPath.
+func WithDebugTarget(daemon *Daemon, c *container.Container) coci.SpecOpts {
+	return func(ctx context.Context, _ coci.Client, _ *containers.Container, s *coci.Spec) error {
+		if c.DebugTarget == "" {
+			return nil
+		}
+		target, err := daemon.getDebugTarget(c.DebugTarget)
+		if err != nil {
+			return err
+		}
+		s.Mounts = append(s.Mounts, specs.Mount{
+			Destination: debugTargetPath,
+			Type:        "bind",
+			Source:      target.BaseFS.Path(),
+			Options:     []string{"rbind", "rprivate"},
+		})
+		return nil
+	}
+}
+
 func specMapping(s []idtools.IDMap) []specs.LinuxIDMapping {
 	var ids []specs.LinuxIDMapping
 	for _, item := range s {
@@ -771,6 +809,12 @@ func WithCgroups(daemon *Daemon, c *container.Container) coci.SpecOpts {
 			cgroupsPath = filepath.Join(parent, c.ID)
 		}
 		s.Linux.CgroupsPath = cgroupsPath
+
+		// cgroup v2 does not support real-time scheduling, so there are no
+		// real-time settings to initialize in the parent cgroups.
+		if sysinfo.IsCgroup2UnifiedMode() {
+			return nil
+		}
 		p := cgroupsPath
 		if useSystemd {
 			initPath, err := cgroups.GetInitCgroup("cpu")
@@ -891,6 +935,7 @@ func WithResources(c *container.Container) coci.SpecOpts {
 			},
 			Pids: getPidsLimit(r),
 		}
+		adaptResourcesForCgroup2(specResources)
 
 		if s.Linux.Resources != nil && len(s.Linux.Resources.Devices) > 0 {
 			specResources.Devices = s.Linux.Resources.Devices
@@ -944,6 +989,7 @@ func (daemon *Daemon) createSpec(c *container.Container) (retSpec *specs.Spec, e
 		WithCapabilities(c),
 		WithSeccomp(daemon, c),
 		WithMounts(daemon, c),
+		WithDebugTarget(daemon, c),
 		WithLibnetwork(daemon, c),
 		WithApparmor(c),
 		WithSelinux(c),
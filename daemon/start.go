@@ -92,6 +92,9 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 			return errdefs.InvalidParameter(err)
 		}
 	}
+	if err := daemon.checkDependencies(container); err != nil {
+		return err
+	}
 	return daemon.containerStart(container, checkpoint, checkpointDir, true)
 }
 
@@ -227,6 +230,7 @@ func (daemon *Daemon) containerStart(container *container.Container, checkpoint
 // Cleanup releases any network resources allocated to the container along with any rules
 // around how containers are linked together.  It also unmounts the container's root filesystem.
 func (daemon *Daemon) Cleanup(container *container.Container) {
+	daemon.removeDebugContainers(container)
 	daemon.releaseNetwork(container)
 
 	if err := container.UnmountIpcMount(); err != nil {
@@ -250,6 +254,17 @@ func (daemon *Daemon) Cleanup(container *container.Container) {
 	}
 
 	for _, eConfig := range container.ExecCommands.Commands() {
+		if eConfig.Detachable {
+			// Detachable execs are kept until they expire, so that their
+			// output and exit code can be retrieved.
+			container.ExecCommands.Delete(eConfig.ID, eConfig.Pid)
+			eConfig.Lock()
+			if eConfig.FinishedAt.IsZero() {
+				eConfig.FinishedAt = time.Now().UTC()
+			}
+			eConfig.Unlock()
+			continue
+		}
 		daemon.unregisterExecCommand(container, eConfig)
 	}
 
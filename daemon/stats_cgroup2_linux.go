@@ -0,0 +1,206 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/pkg/errors"
+)
+
+// statsV2 returns the stats of a container on a host using the cgroup v2
+// unified hierarchy. containerd only reports the metrics of the cgroup v1
+// controllers, so the stats are read from the interface files of the cgroup of
+// the container instead.
+//
+// cgroup v2 has no equivalent of some of the cgroup v1 stats: the per-cpu
+// usage, the maximum memory usage and memory failure count, and the blkio
+// stats other than the bytes and operations serviced are not set. The memory
+// stats hold the fields of memory.stat, which differ from the cgroup v1 ones.
+func (daemon *Daemon) statsV2(c *container.Container) (*types.StatsJSON, error) {
+	dir, err := cgroup2Dir(c.GetPID())
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, errNotRunning(c.ID)
+		}
+		return nil, err
+	}
+
+	s := &types.StatsJSON{}
+	s.Read = time.Now()
+
+	if err := readCgroup2CPUStats(dir, &s.CPUStats); err != nil {
+		return nil, err
+	}
+	if err := readCgroup2MemoryStats(dir, &s.MemoryStats); err != nil {
+		return nil, err
+	}
+	// if the container does not set memory limit, use the machineMemory
+	if (s.MemoryStats.Limit == 0 || s.MemoryStats.Limit > daemon.machineMemory) && daemon.machineMemory > 0 {
+		s.MemoryStats.Limit = daemon.machineMemory
+	}
+	if err := readCgroup2IOStats(dir, &s.BlkioStats); err != nil {
+		return nil, err
+	}
+	if err := readCgroup2PidsStats(dir, &s.PidsStats); err != nil {
+		return nil, err
+	}
+	if err := readCgroup2PressureStats(dir, &s.Stats); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// cgroup2Dir returns the directory of the cgroup v2 group of the process with
+// the given pid.
+func cgroup2Dir(pid int) (string, error) {
+	group, err := sysinfo.Cgroup2Group(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cgroup of container")
+	}
+	return filepath.Join(sysinfo.Cgroup2Root, group), nil
+}
+
+func readCgroup2CPUStats(dir string, stats *types.CPUStats) error {
+	values, err := readCgroupKeyValues(dir, "cpu.stat")
+	if err != nil || values == nil {
+		return err
+	}
+	// cgroup v2 reports times in microseconds, cgroup v1 in nanoseconds.
+	stats.CPUUsage = types.CPUUsage{
+		TotalUsage:        values["usage_usec"] * 1000,
+		UsageInKernelmode: values["system_usec"] * 1000,
+		UsageInUsermode:   values["user_usec"] * 1000,
+	}
+	stats.ThrottlingData = types.ThrottlingData{
+		Periods:          values["nr_periods"],
+		ThrottledPeriods: values["nr_throttled"],
+		ThrottledTime:    values["throttled_usec"] * 1000,
+	}
+	return nil
+}
+
+func readCgroup2MemoryStats(dir string, stats *types.MemoryStats) error {
+	values, err := readCgroupKeyValues(dir, "memory.stat")
+	if err != nil || values == nil {
+		return err
+	}
+	stats.Stats = values
+	if stats.Usage, err = readCgroup2Uint(dir, "memory.current"); err != nil {
+		return err
+	}
+	stats.Limit, err = readCgroup2Uint(dir, "memory.max")
+	return err
+}
+
+func readCgroup2IOStats(dir string, stats *types.BlkioStats) error {
+	content, err := ioutil.ReadFile(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to read io stats of container")
+	}
+
+	// Each line holds the stats of a device: "8:0 rbytes=1 wbytes=2 rios=3 ...".
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var major, minor uint64
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &major, &minor); err != nil {
+			return errors.Wrapf(err, "invalid io stats line: %q", line)
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return errors.Wrapf(err, "invalid io stats line: %q", line)
+			}
+			entry := types.BlkioStatEntry{Major: major, Minor: minor, Value: v}
+			switch kv[0] {
+			case "rbytes":
+				entry.Op = "Read"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "wbytes":
+				entry.Op = "Write"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "rios":
+				entry.Op = "Read"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			case "wios":
+				entry.Op = "Write"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			}
+		}
+	}
+	return nil
+}
+
+func readCgroup2PidsStats(dir string, stats *types.PidsStats) error {
+	current, err := readCgroup2Uint(dir, "pids.current")
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+	stats.Current = current
+	stats.Limit, err = readCgroup2Uint(dir, "pids.max")
+	return err
+}
+
+// readCgroup2Uint reads an interface file of a cgroup holding a single value.
+// The value "max", meaning there is no limit, is returned as 0.
+func readCgroup2Uint(dir, name string) (uint64, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read %s of container", name)
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "max" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value in %s of container", name)
+	}
+	return v, nil
+}
+
+// readCgroupKeyValues reads an interface file of a cgroup holding a value per
+// line, preceded by its key. It returns nil if the file does not exist, which
+// is the case if the controller of the file is not enabled.
+func readCgroupKeyValues(dir, name string) (map[string]uint64, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read %s of container", name)
+	}
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value in %s of container", name)
+		}
+		values[fields[0]] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestReadCgroup2Stats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-cgroup2-stats")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"cpu.stat":       "usage_usec 3000\nuser_usec 2000\nsystem_usec 1000\nnr_periods 10\nnr_throttled 2\nthrottled_usec 500\n",
+		"memory.stat":    "anon 4096\nfile 8192\n",
+		"memory.current": "12288\n",
+		"memory.max":     "max\n",
+		"io.stat":        "8:0 rbytes=100 wbytes=200 rios=1 wios=2 dbytes=0 dios=0\n",
+		"pids.current":   "3\n",
+		"pids.max":       "100\n",
+	}
+	for name, content := range files {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	var cpu types.CPUStats
+	assert.NilError(t, readCgroup2CPUStats(dir, &cpu))
+	assert.Check(t, is.DeepEqual(cpu, types.CPUStats{
+		CPUUsage:       types.CPUUsage{TotalUsage: 3000000, UsageInKernelmode: 1000000, UsageInUsermode: 2000000},
+		ThrottlingData: types.ThrottlingData{Periods: 10, ThrottledPeriods: 2, ThrottledTime: 500000},
+	}))
+
+	var mem types.MemoryStats
+	assert.NilError(t, readCgroup2MemoryStats(dir, &mem))
+	assert.Check(t, is.DeepEqual(mem, types.MemoryStats{
+		Usage: 12288,
+		Stats: map[string]uint64{"anon": 4096, "file": 8192},
+	}))
+
+	var io types.BlkioStats
+	assert.NilError(t, readCgroup2IOStats(dir, &io))
+	assert.Check(t, is.DeepEqual(io.IoServiceBytesRecursive, []types.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 100},
+		{Major: 8, Minor: 0, Op: "Write", Value: 200},
+	}))
+	assert.Check(t, is.DeepEqual(io.IoServicedRecursive, []types.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 1},
+		{Major: 8, Minor: 0, Op: "Write", Value: 2},
+	}))
+
+	var pids types.PidsStats
+	assert.NilError(t, readCgroup2PidsStats(dir, &pids))
+	assert.Check(t, is.DeepEqual(pids, types.PidsStats{Current: 3, Limit: 100}))
+
+	// Controllers which are not enabled have no interface files.
+	assert.NilError(t, os.Remove(filepath.Join(dir, "pids.current")))
+	pids = types.PidsStats{}
+	assert.NilError(t, readCgroup2PidsStats(dir, &pids))
+	assert.Check(t, is.DeepEqual(pids, types.PidsStats{}))
+}
@@ -0,0 +1,142 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/pkg/errors"
+)
+
+// readPressureStats sets the pressure stall information and the OOM event
+// counts of the cgroup of the process with the given pid in s.
+//
+// Pressure stall information is only available on hosts using the cgroup v2
+// unified hierarchy, with a kernel supporting it (CONFIG_PSI). On cgroup v1
+// hosts, only the number of processes killed by the OOM killer is set, if the
+// kernel reports it (Linux 4.13 and later).
+func readPressureStats(pid int, s *types.Stats) error {
+	if !sysinfo.IsCgroup2UnifiedMode() {
+		dir, err := cgroup1Dir(pid, "memory")
+		if err != nil {
+			return err
+		}
+		values, err := readCgroupKeyValues(dir, "memory.oom_control")
+		if err != nil {
+			return err
+		}
+		s.MemoryStats.OOMKillEvents = values["oom_kill"]
+		return nil
+	}
+
+	dir, err := cgroup2Dir(pid)
+	if err != nil {
+		return err
+	}
+	return readCgroup2PressureStats(dir, s)
+}
+
+func readCgroup2PressureStats(dir string, s *types.Stats) error {
+	events, err := readCgroupKeyValues(dir, "memory.events")
+	if err != nil {
+		return err
+	}
+	s.MemoryStats.OOMEvents = events["oom"]
+	s.MemoryStats.OOMKillEvents = events["oom_kill"]
+
+	var pressure types.PressureStats
+	for _, r := range []struct {
+		file string
+		p    **types.Pressure
+	}{
+		{"cpu.pressure", &pressure.CPU},
+		{"memory.pressure", &pressure.Memory},
+		{"io.pressure", &pressure.IO},
+	} {
+		if *r.p, err = readCgroup2Pressure(dir, r.file); err != nil {
+			return err
+		}
+	}
+	if pressure.CPU != nil || pressure.Memory != nil || pressure.IO != nil {
+		s.PressureStats = &pressure
+	}
+	return nil
+}
+
+// readCgroup2Pressure reads a pressure interface file of a cgroup, which looks
+// like:
+//
+//     some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//     full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// It returns nil if the file cannot be read, which is the case if the kernel
+// does not support pressure stall information, PSI is disabled with the psi=0
+// kernel parameter, or the controller of the file is not enabled.
+func readCgroup2Pressure(dir, name string) (*types.Pressure, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, nil
+	}
+
+	var p types.Pressure
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var data types.PressureData
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "avg10":
+				data.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				data.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				data.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				data.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value in %s of container", name)
+			}
+		}
+		switch fields[0] {
+		case "some":
+			p.Some = &data
+		case "full":
+			p.Full = &data
+		}
+	}
+	return &p, nil
+}
+
+// cgroup1Dir returns the directory of the cgroup v1 group of the process with
+// the given pid, in the hierarchy of the given subsystem.
+func cgroup1Dir(pid int, subsystem string) (string, error) {
+	groups, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cgroup of container")
+	}
+	group, ok := groups[subsystem]
+	if !ok {
+		return "", errors.Errorf("no %s cgroup found for process %d", subsystem, pid)
+	}
+	mnt, root, err := cgroups.FindCgroupMountpointAndRoot("", subsystem)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, group)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(mnt, rel), nil
+}
@@ -0,0 +1,51 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestReadCgroup2PressureStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-cgroup2-pressure")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"memory.events":   "low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\n",
+		"cpu.pressure":    "some avg10=1.50 avg60=0.75 avg300=0.10 total=123456\n",
+		"memory.pressure": "some avg10=0.00 avg60=0.00 avg300=0.00 total=10\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=5\n",
+	}
+	for name, content := range files {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	var s types.Stats
+	assert.NilError(t, readCgroup2PressureStats(dir, &s))
+	assert.Check(t, is.Equal(s.MemoryStats.OOMEvents, uint64(3)))
+	assert.Check(t, is.Equal(s.MemoryStats.OOMKillEvents, uint64(2)))
+	assert.Check(t, is.DeepEqual(s.PressureStats, &types.PressureStats{
+		CPU: &types.Pressure{
+			Some: &types.PressureData{Avg10: 1.5, Avg60: 0.75, Avg300: 0.1, Total: 123456},
+		},
+		Memory: &types.Pressure{
+			Some: &types.PressureData{Total: 10},
+			Full: &types.PressureData{Total: 5},
+		},
+	}))
+
+	// Pressure stall information is not reported if the kernel does not
+	// support it.
+	for _, name := range []string{"cpu.pressure", "memory.pressure"} {
+		assert.NilError(t, os.Remove(filepath.Join(dir, name)))
+	}
+	s = types.Stats{}
+	assert.NilError(t, readCgroup2PressureStats(dir, &s))
+	assert.Check(t, is.Nil(s.PressureStats))
+	assert.Check(t, is.Equal(s.MemoryStats.OOMKillEvents, uint64(2)))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/container"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // Resolve Network SandboxID in case the container reuse another container's network stack
@@ -52,6 +53,9 @@ func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.
 			TxDropped: ifStats.TxDropped,
 		}
 	}
+	if err := daemon.setNetworkLimitStats(c, sb, stats); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Debug("Failed to get the network limits")
+	}
 
 	return stats, nil
 }
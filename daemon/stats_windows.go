@@ -9,3 +9,8 @@ import (
 func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.NetworkStats, error) {
 	return make(map[string]types.NetworkStats), nil
 }
+
+// Pressure stall information is not available on Windows, hence this is a no-op.
+func readPressureStats(pid int, s *types.Stats) error {
+	return nil
+}
@@ -2,10 +2,15 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types/strslice"
 	containerpkg "github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/signal"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -36,45 +41,117 @@ func (daemon *Daemon) ContainerStop(name string, timeout *int) error {
 	return nil
 }
 
-// containerStop sends a stop signal, waits, sends a kill signal.
+// stopStep is a step of the stop sequence of a container: the signal sent,
+// and the timeout (in seconds) to wait for the container to exit. A negative
+// timeout means no timeout.
+type stopStep struct {
+	signal  int
+	timeout int
+}
+
+// stopSequence returns the steps to stop the container within the given
+// timeout. It is the stop signal of the container with the timeout if the
+// container has no stop sequence. The last step of the sequence waits until
+// the timeout expires, if it is longer than the sequence.
+func stopSequence(container *containerpkg.Container, seconds int) []stopStep {
+	if len(container.Config.StopSequence) == 0 {
+		return []stopStep{{signal: container.StopSignal(), timeout: seconds}}
+	}
+	steps := make([]stopStep, 0, len(container.Config.StopSequence))
+	elapsed := 0
+	for _, s := range container.Config.StopSequence {
+		// The signals are validated when the container is created.
+		sig, _ := signal.ParseSignal(s.Signal)
+		steps = append(steps, stopStep{signal: int(sig), timeout: s.Timeout})
+		elapsed += s.Timeout
+	}
+	last := &steps[len(steps)-1]
+	if seconds < 0 {
+		last.timeout = -1
+	} else if remaining := seconds - (elapsed - last.timeout); remaining > last.timeout {
+		last.timeout = remaining
+	}
+	return steps
+}
+
+// containerStop runs the pre-stop hook of the container, then sends the
+// signals of its stop sequence, waiting for the container to exit after each
+// of them, and kills it if it is still running once the sequence is over or
+// the timeout expired.
 func (daemon *Daemon) containerStop(container *containerpkg.Container, seconds int) error {
 	if !container.IsRunning() {
 		return nil
 	}
 
-	stopSignal := container.StopSignal()
-	// 1. Send a stop signal
-	if err := daemon.killPossiblyDeadProcess(container, stopSignal); err != nil {
-		// While normally we might "return err" here we're not going to
-		// because if we can't stop the container by this point then
-		// it's probably because it's already stopped. Meaning, between
-		// the time of the IsRunning() call above and now it stopped.
-		// Also, since the err return will be environment specific we can't
-		// look for any particular (common) error that would indicate
-		// that the process is already dead vs something else going wrong.
-		// So, instead we'll give it up to 2 more seconds to complete and if
-		// by that time the container is still running, then the error
-		// we got is probably valid and so we force kill it.
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-
-		if status := <-container.Wait(ctx, containerpkg.WaitConditionNotRunning); status.Err() != nil {
-			logrus.Infof("Container failed to stop after sending signal %d to the process, force killing", stopSignal)
-			if err := daemon.killPossiblyDeadProcess(container, 9); err != nil {
-				return err
-			}
-		}
+	if container.Config.PreStop != nil {
+		daemon.runPreStopHook(container)
 	}
 
-	// 2. Wait for the process to exit on its own
-	ctx := context.Background()
+	var deadline time.Time
 	if seconds >= 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds)*time.Second)
-		defer cancel()
+		deadline = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	steps := stopSequence(container, seconds)
+	stopSignal := steps[0].signal
+	exited := false
+	for i, step := range steps {
+		timeout := time.Duration(step.timeout) * time.Second
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if i > 0 && remaining <= 0 {
+				break
+			}
+			if remaining < timeout {
+				timeout = remaining
+			}
+		}
+		stopSignal = step.signal
+		if len(container.Config.StopSequence) > 0 {
+			daemon.LogContainerEventWithAttributes(container, "stop_signal", map[string]string{
+				"signal": container.Config.StopSequence[i].Signal,
+				"step":   strconv.Itoa(i + 1),
+			})
+		}
+
+		// 1. Send a stop signal
+		if err := daemon.killPossiblyDeadProcess(container, step.signal); err != nil {
+			// While normally we might "return err" here we're not going to
+			// because if we can't stop the container by this point then
+			// it's probably because it's already stopped. Meaning, between
+			// the time of the IsRunning() call above and now it stopped.
+			// Also, since the err return will be environment specific we can't
+			// look for any particular (common) error that would indicate
+			// that the process is already dead vs something else going wrong.
+			// So, instead we'll give it up to 2 more seconds to complete and if
+			// by that time the container is still running, then the error
+			// we got is probably valid and so we force kill it.
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			status := <-container.Wait(ctx, containerpkg.WaitConditionNotRunning)
+			cancel()
+			if status.Err() != nil {
+				logrus.Infof("Container failed to stop after sending signal %d to the process, force killing", step.signal)
+				if err := daemon.killPossiblyDeadProcess(container, 9); err != nil {
+					return err
+				}
+			}
+		}
+
+		// 2. Wait for the process to exit on its own
+		ctx := context.Background()
+		cancel := func() {}
+		if step.timeout >= 0 || !deadline.IsZero() {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		status := <-container.Wait(ctx, containerpkg.WaitConditionNotRunning)
+		cancel()
+		if status.Err() == nil {
+			exited = true
+			break
+		}
 	}
 
-	if status := <-container.Wait(ctx, containerpkg.WaitConditionNotRunning); status.Err() != nil {
+	if !exited {
 		logrus.Infof("Container %v failed to exit within %d seconds of signal %d - using the force", container.ID, seconds, stopSignal)
 		// 3. If it doesn't, then send SIGKILL
 		if err := daemon.Kill(container); err != nil {
@@ -87,3 +164,61 @@ func (daemon *Daemon) containerStop(container *containerpkg.Container, seconds i
 	daemon.LogContainerEvent(container, "stop")
 	return nil
 }
+
+// runPreStopHook runs the pre-stop hook of the container, killing it if it
+// does not exit within its timeout. The container is stopped whatever the
+// outcome of the hook, which is reported in a "pre_stop" event.
+func (daemon *Daemon) runPreStopHook(c *containerpkg.Container) {
+	hook := c.Config.PreStop
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.PreStopTimeout())*time.Second)
+	defer cancel()
+
+	execConfig := exec.NewConfig()
+	execConfig.OpenStdout = true
+	execConfig.OpenStderr = true
+	execConfig.ContainerID = c.ID
+	execConfig.DetachKeys = []byte{}
+	execConfig.Entrypoint, execConfig.Args = daemon.getEntrypointAndArgs(strslice.StrSlice{}, hook.Cmd)
+	execConfig.User = hook.User
+	if execConfig.User == "" {
+		execConfig.User = c.Config.User
+	}
+	execConfig.WorkingDir = c.Config.WorkingDir
+
+	attributes := map[string]string{}
+	err := func() error {
+		linkedEnv, err := daemon.setupLinkedContainers(c)
+		if err != nil {
+			return err
+		}
+		execConfig.Env = containerpkg.ReplaceOrAppendEnvValues(c.CreateDaemonEnvironment(execConfig.Tty, linkedEnv), execConfig.Env)
+
+		daemon.registerExecCommand(c, execConfig)
+		attributes["execID"] = execConfig.ID
+		daemon.LogContainerEventWithAttributes(c, "exec_create: "+execConfig.Entrypoint+" "+strings.Join(execConfig.Args, " "), map[string]string{"execID": execConfig.ID})
+
+		output := &limitedBuffer{}
+		if err := daemon.ContainerExecStart(ctx, execConfig.ID, nil, output, output); err != nil {
+			return err
+		}
+		execConfig.Lock()
+		exitCode := execConfig.ExitCode
+		execConfig.Unlock()
+		if exitCode == nil {
+			return errors.New("pre-stop hook has no exit code")
+		}
+		attributes["exitCode"] = strconv.Itoa(*exitCode)
+		if *exitCode != 0 {
+			logrus.WithField("container", c.ID).WithField("output", output.String()).Infof("Pre-stop hook exited with code %d", *exitCode)
+		}
+		return nil
+	}()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = errors.Errorf("pre-stop hook did not exit within %d seconds", c.PreStopTimeout())
+		}
+		logrus.WithError(err).WithField("container", c.ID).Warn("Error running pre-stop hook")
+		attributes["error"] = err.Error()
+	}
+	daemon.LogContainerEventWithAttributes(c, "pre_stop", attributes)
+}
@@ -0,0 +1,40 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"syscall"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+var cmpStopStep = cmp.AllowUnexported(stopStep{})
+
+func TestStopSequence(t *testing.T) {
+	c := &container.Container{Config: &containertypes.Config{StopSignal: "SIGINT"}}
+	assert.Check(t, is.DeepEqual(stopSequence(c, 7), []stopStep{{signal: int(syscall.SIGINT), timeout: 7}}, cmpStopStep))
+
+	c.Config.StopSequence = []containertypes.StopSignalStep{
+		{Signal: "SIGTERM", Timeout: 5},
+		{Signal: "SIGKILL", Timeout: 3},
+	}
+	assert.Check(t, is.DeepEqual(stopSequence(c, 7), []stopStep{
+		{signal: int(syscall.SIGTERM), timeout: 5},
+		{signal: int(syscall.SIGKILL), timeout: 3},
+	}, cmpStopStep))
+
+	// The last signal is given the rest of a longer timeout.
+	assert.Check(t, is.DeepEqual(stopSequence(c, 20), []stopStep{
+		{signal: int(syscall.SIGTERM), timeout: 5},
+		{signal: int(syscall.SIGKILL), timeout: 15},
+	}, cmpStopStep))
+
+	// Without timeout, the container is not killed after the last signal.
+	assert.Check(t, is.DeepEqual(stopSequence(c, -1), []stopStep{
+		{signal: int(syscall.SIGTERM), timeout: 5},
+		{signal: int(syscall.SIGKILL), timeout: -1},
+	}, cmpStopStep))
+}
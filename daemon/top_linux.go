@@ -0,0 +1,480 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/runc/libcontainer/system"
+	"github.com/opencontainers/runc/libcontainer/user"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// ContainerProcesses lists the processes running inside of the given
+// container, reading their information from /proc. An error is returned if
+// the container is not found, or is not running.
+func (daemon *Daemon) ContainerProcesses(name string, options types.ContainerProcessesOptions) ([]containertypes.Process, error) {
+	fields := make(map[string]bool)
+	for _, f := range options.Fields {
+		if !isProcessField(f) {
+			return nil, errdefs.InvalidParameter(errors.Errorf("invalid process field: %q", f))
+		}
+		fields[f] = true
+	}
+	if len(fields) == 0 {
+		for _, f := range containertypes.ProcessFields {
+			fields[f] = true
+		}
+	}
+
+	ctr, procs, err := daemon.containerProcs(name)
+	if err != nil {
+		return nil, err
+	}
+	sys, err := readProcSystem("/proc")
+	if err != nil {
+		return nil, errdefs.System(err)
+	}
+	var passwd []user.User
+	if fields[containertypes.ProcessFieldUser] {
+		passwd = containerPasswd(ctr)
+	}
+
+	processes := make([]containertypes.Process, 0, len(procs))
+	for _, p := range procs {
+		processes = append(processes, p.toProcess(fields, sys, passwd))
+	}
+	if options.Tree {
+		processes = processTree(processes, procs)
+	}
+	daemon.LogContainerEvent(ctr, "top")
+	return processes, nil
+}
+
+func isProcessField(f string) bool {
+	for _, field := range containertypes.ProcessFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// containerProcs returns the container with the given name, and its processes
+// read from /proc, sorted by PID. Processes which exited since they were
+// listed are left out.
+func (daemon *Daemon) containerProcs(name string) (*container.Container, []*procProcess, error) {
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ctr.IsRunning() {
+		return nil, nil, errNotRunning(ctr.ID)
+	}
+	if ctr.IsRestarting() {
+		return nil, nil, errContainerIsRestarting(ctr.ID)
+	}
+
+	pids, err := daemon.containerd.ListPids(context.Background(), ctr.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	procs := make([]*procProcess, 0, len(pids))
+	for _, pid := range pids {
+		p, err := readProcProcess("/proc", int(pid))
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				continue
+			}
+			return nil, nil, errdefs.System(err)
+		}
+		procs = append(procs, p)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].pid < procs[j].pid })
+	return ctr, procs, nil
+}
+
+// containerPasswd returns the users of the /etc/passwd file of the container,
+// or nil if it cannot be read.
+func containerPasswd(ctr *container.Container) []user.User {
+	path, err := ctr.GetResourcePath("/etc/passwd")
+	if err != nil {
+		return nil
+	}
+	users, err := user.ParsePasswdFile(path)
+	if err != nil {
+		return nil
+	}
+	return users
+}
+
+// processTree nests the processes under their parent. The processes whose
+// parent is not a process of the container are at the root of the tree.
+func processTree(processes []containertypes.Process, procs []*procProcess) []containertypes.Process {
+	index := make(map[int]int, len(procs))
+	for i, p := range procs {
+		index[p.pid] = i
+	}
+	children := make(map[int][]int)
+	var roots []int
+	for i, p := range procs {
+		if parent, ok := index[p.ppid]; ok && parent != i {
+			children[parent] = append(children[parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i int) containertypes.Process
+	build = func(i int) containertypes.Process {
+		process := processes[i]
+		for _, c := range children[i] {
+			process.Children = append(process.Children, build(c))
+		}
+		return process
+	}
+	tree := make([]containertypes.Process, 0, len(roots))
+	for _, i := range roots {
+		tree = append(tree, build(i))
+	}
+	return tree
+}
+
+// procSystem holds the information about the system needed to interpret the
+// information about processes in /proc.
+type procSystem struct {
+	bootTime   time.Time
+	now        time.Time
+	clockTicks uint64
+	pageSize   uint64
+}
+
+func readProcSystem(procRoot string) (*procSystem, error) {
+	f, err := os.Open(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sys := &procSystem{
+		now:        time.Now(),
+		clockTicks: uint64(system.GetClockTicks()),
+		pageSize:   uint64(os.Getpagesize()),
+	}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			btime, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid boot time in /proc/stat")
+			}
+			sys.bootTime = time.Unix(btime, 0)
+			return sys, nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no boot time in /proc/stat")
+}
+
+// procProcess is a process, as read from /proc.
+type procProcess struct {
+	pid     int
+	ppid    int
+	comm    string
+	state   string
+	ttyNr   uint64
+	threads int
+	// utime and stime are the CPU time the process spent in user and kernel
+	// mode, startTime the time the process started after boot, in clock
+	// ticks.
+	utime     uint64
+	stime     uint64
+	startTime uint64
+	rssPages  uint64
+	// uid is the effective UID of the process in the user namespace of the
+	// host, and uidMap the UID mappings of its user namespace.
+	uid     uint32
+	uidMap  []uidMapping
+	cmdline []string
+}
+
+type uidMapping struct {
+	inside, outside, count uint32
+}
+
+func readProcProcess(procRoot string, pid int) (*procProcess, error) {
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	p := &procProcess{pid: pid}
+
+	stat, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseStat(string(stat)); err != nil {
+		return nil, errors.Wrapf(err, "invalid stat of process %d", pid)
+	}
+
+	status, err := ioutil.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		// Uid: real, effective, saved set, and filesystem UIDs
+		if fields := strings.Fields(line); len(fields) == 5 && fields[0] == "Uid:" {
+			uid, err := strconv.ParseUint(fields[2], 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid status of process %d", pid)
+			}
+			p.uid = uint32(uid)
+		}
+	}
+
+	uidMap, err := ioutil.ReadFile(filepath.Join(dir, "uid_map"))
+	if err != nil {
+		return nil, err
+	}
+	if p.uidMap, err = parseUIDMap(string(uidMap)); err != nil {
+		return nil, errors.Wrapf(err, "invalid uid_map of process %d", pid)
+	}
+
+	cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0}) {
+		if len(arg) > 0 {
+			p.cmdline = append(p.cmdline, string(arg))
+		}
+	}
+	return p, nil
+}
+
+// parseStat parses the content of /proc/<pid>/stat. See proc(5) for the list
+// of its fields.
+func (p *procProcess) parseStat(stat string) error {
+	// The command name is enclosed in parentheses, and may itself contain
+	// spaces and parentheses.
+	start, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return errors.New("no command name")
+	}
+	p.comm = stat[start+1 : end]
+
+	// fields[0] is the state, the 3rd field of the file.
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return errors.Errorf("expected at least 24 fields, got %d", len(fields)+2)
+	}
+	p.state = fields[0]
+	var err error
+	for _, f := range []struct {
+		index int
+		v     *uint64
+	}{
+		{4, &p.ttyNr},
+		{11, &p.utime},
+		{12, &p.stime},
+		{19, &p.startTime},
+		{21, &p.rssPages},
+	} {
+		if *f.v, err = strconv.ParseUint(fields[f.index], 10, 64); err != nil {
+			return err
+		}
+	}
+	if p.ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return err
+	}
+	p.threads, err = strconv.Atoi(fields[17])
+	return err
+}
+
+func parseUIDMap(content string) ([]uidMapping, error) {
+	var mappings []uidMapping
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid mapping: %q", line)
+		}
+		var values [3]uint32
+		for i, f := range fields {
+			v, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = uint32(v)
+		}
+		mappings = append(mappings, uidMapping{inside: values[0], outside: values[1], count: values[2]})
+	}
+	return mappings, nil
+}
+
+// containerUID returns the UID of the process in its user namespace, or false
+// if the UID is not mapped in the namespace.
+func (p *procProcess) containerUID() (uint32, bool) {
+	for _, m := range p.uidMap {
+		if p.uid >= m.outside && uint64(p.uid) < uint64(m.outside)+uint64(m.count) {
+			return p.uid - m.outside + m.inside, true
+		}
+	}
+	return 0, false
+}
+
+// cpuTime returns the CPU time the process used since it started.
+func (p *procProcess) cpuTime(sys *procSystem) time.Duration {
+	return time.Duration((p.utime + p.stime) * uint64(time.Second) / sys.clockTicks)
+}
+
+func (p *procProcess) started(sys *procSystem) time.Time {
+	return sys.bootTime.Add(time.Duration(p.startTime * uint64(time.Second) / sys.clockTicks))
+}
+
+// cpuPercent returns the share of the CPU time the process used since it
+// started, like ps(1) does.
+func (p *procProcess) cpuPercent(sys *procSystem) float64 {
+	elapsed := sys.now.Sub(p.started(sys))
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.cpuTime(sys)) * 100 / float64(elapsed)
+}
+
+func (p *procProcess) toProcess(fields map[string]bool, sys *procSystem, passwd []user.User) containertypes.Process {
+	process := containertypes.Process{PID: p.pid}
+	if fields[containertypes.ProcessFieldPPID] {
+		process.PPID = p.ppid
+	}
+	uid, mapped := p.containerUID()
+	if fields[containertypes.ProcessFieldUser] && mapped {
+		process.User = strconv.FormatUint(uint64(uid), 10)
+		for _, u := range passwd {
+			if u.Uid == int(uid) {
+				process.User = u.Name
+				break
+			}
+		}
+	}
+	if fields[containertypes.ProcessFieldUID] && mapped {
+		process.UID = &uid
+	}
+	if fields[containertypes.ProcessFieldHostUID] {
+		hostUID := p.uid
+		process.HostUID = &hostUID
+	}
+	if fields[containertypes.ProcessFieldState] {
+		process.State = p.state
+	}
+	if fields[containertypes.ProcessFieldCPUPercent] {
+		cpu := p.cpuPercent(sys)
+		process.CPUPercent = &cpu
+	}
+	if fields[containertypes.ProcessFieldRSS] {
+		rss := p.rssPages * sys.pageSize
+		process.RSS = &rss
+	}
+	if fields[containertypes.ProcessFieldStartTime] {
+		started := p.started(sys)
+		process.StartTime = &started
+	}
+	if fields[containertypes.ProcessFieldCmdline] {
+		process.Cmdline = p.cmdline
+	}
+	if fields[containertypes.ProcessFieldThreads] {
+		process.Threads = p.threads
+	}
+	return process
+}
+
+// psTitles are the titles of the columns of the output of "ps -ef".
+var psTitles = []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+
+// topNative lists the processes of the container like "ps -ef" does, without
+// running ps.
+func (daemon *Daemon) topNative(name string) (*containertypes.ContainerTopOKBody, error) {
+	ctr, procs, err := daemon.containerProcs(name)
+	if err != nil {
+		return nil, err
+	}
+	sys, err := readProcSystem("/proc")
+	if err != nil {
+		return nil, errdefs.System(err)
+	}
+
+	procList := &containertypes.ContainerTopOKBody{Titles: psTitles}
+	for _, p := range procs {
+		procList.Processes = append(procList.Processes, p.psFields(sys, hostUserName))
+	}
+	daemon.LogContainerEvent(ctr, "top")
+	return procList, nil
+}
+
+// hostUserName returns the name of the user with the given UID on the host, or
+// the UID if there is none.
+func hostUserName(uid uint32) string {
+	if u, err := user.LookupUid(int(uid)); err == nil {
+		return u.Name
+	}
+	return strconv.FormatUint(uint64(uid), 10)
+}
+
+// psFields returns the columns of the process in the output of "ps -ef".
+func (p *procProcess) psFields(sys *procSystem, userName func(uint32) string) []string {
+	started := p.started(sys).Local()
+	stime := started.Format("Jan02")
+	if y, m, d := sys.now.Local().Date(); started.After(time.Date(y, m, d, 0, 0, 0, 0, time.Local)) {
+		stime = started.Format("15:04")
+	}
+
+	tty := "?"
+	if p.ttyNr != 0 {
+		major, minor := unix.Major(p.ttyNr), unix.Minor(p.ttyNr)
+		switch {
+		case major >= 136 && major <= 143:
+			tty = fmt.Sprintf("pts/%d", (major-136)<<8|minor)
+		case major == 4 && minor < 64:
+			tty = fmt.Sprintf("tty%d", minor)
+		case major == 4:
+			tty = fmt.Sprintf("ttyS%d", minor-64)
+		}
+	}
+
+	cpuTime := int64(p.cpuTime(sys) / time.Second)
+	cumulative := fmt.Sprintf("%02d:%02d:%02d", cpuTime/3600%24, cpuTime/60%60, cpuTime%60)
+	if days := cpuTime / 86400; days > 0 {
+		cumulative = fmt.Sprintf("%d-%s", days, cumulative)
+	}
+
+	cmd := "[" + p.comm + "]"
+	if len(p.cmdline) > 0 {
+		cmd = strings.Join(p.cmdline, " ")
+	}
+
+	return []string{
+		userName(p.uid),
+		strconv.Itoa(p.pid),
+		strconv.Itoa(p.ppid),
+		strconv.Itoa(int(p.cpuPercent(sys))),
+		stime,
+		tty,
+		cumulative,
+		cmd,
+	}
+}
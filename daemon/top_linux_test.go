@@ -0,0 +1,106 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/opencontainers/runc/libcontainer/user"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func writeProcProcess(t *testing.T, procRoot string, pid int, files map[string]string) {
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	assert.NilError(t, os.MkdirAll(dir, 0755))
+	for name, content := range files {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestReadProcProcess(t *testing.T) {
+	procRoot, err := ioutil.TempDir("", "test-proc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(procRoot)
+
+	writeProcProcess(t, procRoot, 1234, map[string]string{
+		// The command name may contain spaces and parentheses.
+		"stat":    "1234 (my (cmd) x) S 1200 1234 1234 34816 1234 4194560 100 0 0 0 150 50 0 0 20 0 3 0 500 10000000 256 18446744073709551615\n",
+		"status":  "Name:\tcmd\nUid:\t100000\t100033\t100033\t100033\nGid:\t100000\t100000\t100000\t100000\n",
+		"uid_map": "         0     100000      65536\n",
+		"cmdline": "/bin/sh\x00-c\x00sleep 10\x00",
+	})
+
+	p, err := readProcProcess(procRoot, 1234)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p.comm, "my (cmd) x"))
+	assert.Check(t, is.Equal(p.state, "S"))
+	assert.Check(t, is.Equal(p.ppid, 1200))
+	assert.Check(t, is.Equal(p.threads, 3))
+	assert.Check(t, is.Equal(p.utime, uint64(150)))
+	assert.Check(t, is.Equal(p.stime, uint64(50)))
+	assert.Check(t, is.Equal(p.startTime, uint64(500)))
+	assert.Check(t, is.Equal(p.rssPages, uint64(256)))
+	assert.Check(t, is.Equal(p.uid, uint32(100033)))
+	assert.Check(t, is.DeepEqual(p.cmdline, []string{"/bin/sh", "-c", "sleep 10"}))
+
+	uid, mapped := p.containerUID()
+	assert.Check(t, mapped)
+	assert.Check(t, is.Equal(uid, uint32(33)))
+
+	sys := &procSystem{
+		bootTime:   time.Unix(1000, 0),
+		now:        time.Unix(1025, 0),
+		clockTicks: 100,
+		pageSize:   4096,
+	}
+	fields := make(map[string]bool)
+	for _, f := range containertypes.ProcessFields {
+		fields[f] = true
+	}
+	process := p.toProcess(fields, sys, []user.User{{Name: "www-data", Uid: 33}})
+	assert.Check(t, is.Equal(process.PID, 1234))
+	assert.Check(t, is.Equal(process.User, "www-data"))
+	assert.Check(t, is.Equal(*process.UID, uint32(33)))
+	assert.Check(t, is.Equal(*process.HostUID, uint32(100033)))
+	assert.Check(t, is.Equal(*process.RSS, uint64(256*4096)))
+	assert.Check(t, is.Equal(*process.StartTime, time.Unix(1005, 0)))
+	// 2 seconds of CPU time in the 20 seconds since the process started.
+	assert.Check(t, is.Equal(*process.CPUPercent, float64(10)))
+
+	process = p.toProcess(map[string]bool{containertypes.ProcessFieldState: true}, sys, nil)
+	assert.Check(t, is.DeepEqual(process, containertypes.Process{PID: 1234, State: "S"}))
+
+	ps := p.psFields(sys, func(uid uint32) string { return strconv.Itoa(int(uid)) })
+	assert.Check(t, is.DeepEqual(ps[:4], []string{"100033", "1234", "1200", "10"}))
+	assert.Check(t, is.Equal(ps[5], "pts/0"))
+	assert.Check(t, is.Equal(ps[6], "00:00:02"))
+	assert.Check(t, is.Equal(ps[7], "/bin/sh -c sleep 10"))
+}
+
+func TestProcessTree(t *testing.T) {
+	procs := []*procProcess{
+		{pid: 10, ppid: 5},
+		{pid: 11, ppid: 10},
+		{pid: 12, ppid: 11},
+		{pid: 13, ppid: 10},
+		{pid: 20, ppid: 5},
+	}
+	var processes []containertypes.Process
+	for _, p := range procs {
+		processes = append(processes, containertypes.Process{PID: p.pid})
+	}
+
+	tree := processTree(processes, procs)
+	assert.Check(t, is.DeepEqual(tree, []containertypes.Process{
+		{PID: 10, Children: []containertypes.Process{
+			{PID: 11, Children: []containertypes.Process{{PID: 12}}},
+			{PID: 13},
+		}},
+		{PID: 20},
+	}))
+}
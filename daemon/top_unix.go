@@ -131,13 +131,14 @@ func psPidsArg(pids []uint32) string {
 }
 
 // ContainerTop lists the processes running inside of the given
-// container by calling ps with the given args, or with the flags
-// "-ef" if no args are given.  An error is returned if the container
-// is not found, or is not running, or if there are any problems
-// running ps, or parsing the output.
+// container by calling ps with the given args. If no args are given,
+// the processes are read from /proc, and listed like "ps -ef" does.
+// An error is returned if the container is not found, or is not
+// running, or if there are any problems running ps, or parsing the
+// output.
 func (daemon *Daemon) ContainerTop(name string, psArgs string) (*container.ContainerTopOKBody, error) {
 	if psArgs == "" {
-		psArgs = "-ef"
+		return daemon.topNative(name)
 	}
 
 	if err := validatePSArgs(psArgs); err != nil {
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-units"
 )
 
@@ -61,3 +63,8 @@ func (daemon *Daemon) ContainerTop(name string, psArgs string) (*containertypes.
 
 	return procList, nil
 }
+
+// ContainerProcesses is not supported on Windows.
+func (daemon *Daemon) ContainerProcesses(name string, options types.ContainerProcessesOptions) ([]containertypes.Process, error) {
+	return nil, errdefs.NotImplemented(errors.New("listing the processes of a container is not supported on Windows"))
+}
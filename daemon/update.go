@@ -3,48 +3,113 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
-	"github.com/docker/docker/api/types/container"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-// ContainerUpdate updates configuration of the container
-func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostConfig) (container.ContainerUpdateOKBody, error) {
+// swarmLabelPrefix is the prefix of the labels set by swarm on the containers
+// of its tasks. Those labels cannot be updated.
+const swarmLabelPrefix = "com.docker.swarm."
+
+// ContainerUpdate updates configuration of the container. Of config, only the
+// labels and the healthcheck are updated. Settings which are not set in config
+// and hostConfig are left unchanged.
+func (daemon *Daemon) ContainerUpdate(name string, config *containertypes.Config, hostConfig *containertypes.HostConfig) (containertypes.ContainerUpdateOKBody, error) {
 	var warnings []string
 
 	c, err := daemon.GetContainer(name)
 	if err != nil {
-		return container.ContainerUpdateOKBody{Warnings: warnings}, err
+		return containertypes.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
-	warnings, err = daemon.verifyContainerSettings(c.OS, hostConfig, nil, true)
+	warnings, err = daemon.verifyContainerSettings(c.OS, hostConfig, config, true)
 	if err != nil {
-		return container.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
+		return containertypes.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
+	}
+	if err := daemon.verifyUpdateSettings(c, config, hostConfig); err != nil {
+		return containertypes.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
 	}
 
-	if err := daemon.update(name, hostConfig); err != nil {
-		return container.ContainerUpdateOKBody{Warnings: warnings}, err
+	updateWarnings, err := daemon.update(name, config, hostConfig)
+	warnings = append(warnings, updateWarnings...)
+	if err != nil {
+		return containertypes.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
-	return container.ContainerUpdateOKBody{Warnings: warnings}, nil
+	return containertypes.ContainerUpdateOKBody{Warnings: warnings}, nil
 }
 
-func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) error {
+// verifyUpdateSettings verifies the settings of an update which are not
+// verified when creating a container, and merges the log configuration with
+// the log driver of the container and the default log options.
+func (daemon *Daemon) verifyUpdateSettings(c *container.Container, config *containertypes.Config, hostConfig *containertypes.HostConfig) error {
 	if hostConfig == nil {
 		return nil
 	}
+	if config != nil && config.Labels != nil {
+		if err := validateUpdateLabels(c.Config.Labels, config.Labels); err != nil {
+			return err
+		}
+	}
+	if err := validateUpdateDevices(hostConfig.Devices); err != nil {
+		return err
+	}
+
+	logConfig := &hostConfig.LogConfig
+	if logConfig.Type == "" && logConfig.Config == nil {
+		return nil
+	}
+	if logConfig.Type == "" {
+		logConfig.Type = c.HostConfig.LogConfig.Type
+	}
+	return daemon.mergeAndVerifyLogConfig(logConfig)
+}
+
+// validateUpdateLabels returns an error if updating the labels of a container
+// from current to labels adds, changes or removes a label set by swarm.
+func validateUpdateLabels(current, labels map[string]string) error {
+	for _, l := range []map[string]string{current, labels} {
+		for k := range l {
+			if !strings.HasPrefix(k, swarmLabelPrefix) {
+				continue
+			}
+			if v, ok := labels[k]; !ok || v != current[k] {
+				return errors.Errorf("label %s is managed by swarm and cannot be updated", k)
+			}
+		}
+	}
+	return nil
+}
+
+func (daemon *Daemon) update(name string, config *containertypes.Config, hostConfig *containertypes.HostConfig) ([]string, error) {
+	if hostConfig == nil {
+		return nil, nil
+	}
 
 	container, err := daemon.GetContainer(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	restoreConfig := false
+	backupConfig := *container.Config
 	backupHostConfig := *container.HostConfig
+	var newLogger logger.Logger
 	defer func() {
 		if restoreConfig {
+			if newLogger != nil {
+				newLogger.Close()
+			}
 			container.Lock()
+			container.Config = &backupConfig
 			container.HostConfig = &backupHostConfig
 			container.CheckpointTo(daemon.containersReplica)
 			container.Unlock()
@@ -52,19 +117,33 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 	}()
 
 	if container.RemovalInProgress || container.Dead {
-		return errCannotUpdate(container.ID, fmt.Errorf("container is marked for removal and cannot be \"update\""))
+		return nil, errCannotUpdate(container.ID, fmt.Errorf("container is marked for removal and cannot be \"update\""))
 	}
 
 	container.Lock()
 	if err := container.UpdateContainer(hostConfig); err != nil {
 		restoreConfig = true
 		container.Unlock()
-		return errCannotUpdate(container.ID, err)
+		return nil, errCannotUpdate(container.ID, err)
+	}
+	updateContainerSettings(container, config, hostConfig)
+	changed := changedSettings(&backupConfig, &backupHostConfig, container)
+
+	running := container.Running && !container.Restarting
+	warnings := deferredSettings(&backupHostConfig, container, changed, running)
+	if running && hasSetting(changed, "LogConfig") && canSwapLogger(&backupHostConfig, container) {
+		// Start the new log driver now, so that the update fails if it cannot
+		// be started; it replaces the current one once the update succeeded.
+		if newLogger, err = container.StartLogger(); err != nil {
+			restoreConfig = true
+			container.Unlock()
+			return nil, errCannotUpdate(container.ID, errors.Wrap(err, "failed to initialize logging driver"))
+		}
 	}
 	if err := container.CheckpointTo(daemon.containersReplica); err != nil {
 		restoreConfig = true
 		container.Unlock()
-		return errCannotUpdate(container.ID, err)
+		return nil, errCannotUpdate(container.ID, err)
 	}
 	container.Unlock()
 
@@ -77,17 +156,165 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 	// resources will be updated when the container is started again.
 	// If container is running (including paused), we need to update configs
 	// to the real world.
-	if container.IsRunning() && !container.IsRestarting() {
+	if running {
 		if err := daemon.containerd.UpdateResources(context.Background(), container.ID, toContainerdResources(hostConfig.Resources)); err != nil {
 			restoreConfig = true
 			// TODO: it would be nice if containerd responded with better errors here so we can classify this better.
-			return errCannotUpdate(container.ID, errdefs.System(err))
+			return nil, errCannotUpdate(container.ID, errdefs.System(err))
+		}
+		if hasSetting(changed, "NetworkIngressRate") || hasSetting(changed, "NetworkEgressRate") || hasSetting(changed, "NetworkConntrackMax") {
+			if err := daemon.updateNetworkLimits(container, backupHostConfig.Resources); err != nil {
+				restoreConfig = true
+				return nil, errCannotUpdate(container.ID, errdefs.System(err))
+			}
+		}
+	}
+
+	container.Lock()
+	if newLogger != nil {
+		if container.LogCopier != nil {
+			swapLogger(container, newLogger)
+		} else {
+			// The container exited in the meantime.
+			newLogger.Close()
 		}
 	}
+	if hasSetting(changed, "Healthcheck") {
+		daemon.updateHealthcheck(container)
+	}
+	container.Unlock()
 
-	daemon.LogContainerEvent(container, "update")
+	attributes := map[string]string{}
+	if len(changed) > 0 {
+		attributes["changed"] = strings.Join(changed, ",")
+	}
+	daemon.LogContainerEventWithAttributes(container, "update", attributes)
 
-	return nil
+	return warnings, nil
+}
+
+// updateContainerSettings updates the labels, healthcheck, port bindings, and
+// log configuration of c with the ones set in config and hostConfig. The ports
+// of the port bindings are exposed, if they were not yet. Called with c locked.
+func updateContainerSettings(c *container.Container, config *containertypes.Config, hostConfig *containertypes.HostConfig) {
+	if config != nil {
+		if config.Labels != nil {
+			c.Config.Labels = config.Labels
+		}
+		if config.Healthcheck != nil {
+			c.Config.Healthcheck = config.Healthcheck
+		}
+	}
+	if hostConfig.PortBindings != nil {
+		c.HostConfig.PortBindings = hostConfig.PortBindings
+		exposedPorts := make(nat.PortSet, len(c.Config.ExposedPorts))
+		for p := range c.Config.ExposedPorts {
+			exposedPorts[p] = struct{}{}
+		}
+		for p := range hostConfig.PortBindings {
+			exposedPorts[p] = struct{}{}
+		}
+		c.Config.ExposedPorts = exposedPorts
+	}
+	if hostConfig.LogConfig.Type != "" {
+		c.HostConfig.LogConfig = hostConfig.LogConfig
+	}
+}
+
+// changedSettings returns the names of the settings of c which differ from the
+// given previous configuration. The resources are listed by field name.
+func changedSettings(prevConfig *containertypes.Config, prevHostConfig *containertypes.HostConfig, c *container.Container) []string {
+	var changed []string
+
+	prev, cur := reflect.ValueOf(prevHostConfig.Resources), reflect.ValueOf(c.HostConfig.Resources)
+	for i := 0; i < prev.NumField(); i++ {
+		if reflect.DeepEqual(prev.Field(i).Interface(), cur.Field(i).Interface()) {
+			continue
+		}
+		field := prev.Type().Field(i)
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+			name = tag
+		}
+		changed = append(changed, name)
+	}
+
+	for _, s := range []struct {
+		name      string
+		prev, cur interface{}
+	}{
+		{"RestartPolicy", prevHostConfig.RestartPolicy, c.HostConfig.RestartPolicy},
+		{"Labels", prevConfig.Labels, c.Config.Labels},
+		{"Healthcheck", prevConfig.Healthcheck, c.Config.Healthcheck},
+		{"LogConfig", prevHostConfig.LogConfig, c.HostConfig.LogConfig},
+		{"PortBindings", prevHostConfig.PortBindings, c.HostConfig.PortBindings},
+	} {
+		if !reflect.DeepEqual(s.prev, s.cur) {
+			changed = append(changed, s.name)
+		}
+	}
+	return changed
+}
+
+func hasSetting(settings []string, name string) bool {
+	for _, s := range settings {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deferredSettings returns warnings for the changed settings of c which cannot
+// be applied to the running container, and are applied the next time it is
+// started.
+func deferredSettings(prevHostConfig *containertypes.HostConfig, c *container.Container, changed []string, running bool) []string {
+	if !running {
+		return nil
+	}
+	var warnings []string
+	if hasSetting(changed, "Devices") {
+		warnings = append(warnings, "Devices are updated when the container is restarted")
+	}
+	if hasSetting(changed, "PortBindings") {
+		warnings = append(warnings, "Published ports are updated when the container is restarted")
+	}
+	if hasSetting(changed, "LogConfig") && !canSwapLogger(prevHostConfig, c) {
+		warnings = append(warnings, "Log configuration is updated when the container is restarted")
+	}
+	return warnings
+}
+
+// canSwapLogger returns whether the log driver of the running container c can
+// be replaced. Containers using the "none" log driver do not copy their
+// output, so that it cannot be started or stopped while they are running.
+// Called with c locked.
+func canSwapLogger(prevHostConfig *containertypes.HostConfig, c *container.Container) bool {
+	return c.LogCopier != nil && prevHostConfig.LogConfig.Type != "none" && c.HostConfig.LogConfig.Type != "none"
+}
+
+// swapLogger replaces the log driver of the running container c with l, and
+// closes the previous one. Called with c locked.
+func swapLogger(c *container.Container, l logger.Logger) {
+	prev := c.LogCopier.SetLogger(l)
+	c.LogDriver = l
+	if err := prev.Close(); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("Error closing previous log driver")
+	}
+}
+
+// updateHealthcheck restarts the health monitor of c, if it is running, with
+// its current healthcheck. The health status is removed if c has no
+// healthcheck anymore. Called with c locked.
+func (daemon *Daemon) updateHealthcheck(c *container.Container) {
+	daemon.stopHealthchecks(c)
+	if getProbe(c) == nil {
+		c.State.Health = nil
+		return
+	}
+	if c.Running && !c.Restarting {
+		daemon.initHealthMonitor(c)
+	}
 }
 
 func errCannotUpdate(containerID string, err error) error {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/docker/docker/api/types/container"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
+	"github.com/docker/docker/oci"
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
@@ -51,5 +52,16 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	}
 
 	r.Pids = getPidsLimit(resources)
+	adaptResourcesForCgroup2((*specs.LinuxResources)(&r))
 	return &r
 }
+
+// validateUpdateDevices validates the devices a container is updated with.
+func validateUpdateDevices(devices []container.DeviceMapping) error {
+	for _, d := range devices {
+		if _, _, err := oci.DevicesFromPath(d.PathOnHost, d.PathInContainer, d.CgroupPermissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestUpdateContainerSettings(t *testing.T) {
+	c := &container.Container{
+		Config: &containertypes.Config{
+			Labels:       map[string]string{"foo": "bar"},
+			ExposedPorts: nat.PortSet{"80/tcp": {}},
+		},
+		HostConfig: &containertypes.HostConfig{
+			LogConfig: containertypes.LogConfig{Type: "json-file"},
+		},
+	}
+	prevConfig, prevHostConfig := *c.Config, *c.HostConfig
+
+	updateContainerSettings(c, &containertypes.Config{
+		Healthcheck: &containertypes.HealthConfig{Test: []string{"CMD", "true"}},
+	}, &containertypes.HostConfig{
+		PortBindings: nat.PortMap{
+			"443/tcp": []nat.PortBinding{{HostPort: "8443"}},
+		},
+	})
+	// Resources are updated by UpdateContainer.
+	c.HostConfig.Memory = 1024
+
+	assert.Check(t, is.DeepEqual(c.Config.Labels, map[string]string{"foo": "bar"}))
+	assert.Check(t, is.DeepEqual(c.Config.ExposedPorts, nat.PortSet{"80/tcp": {}, "443/tcp": {}}))
+	assert.Check(t, is.Equal(c.HostConfig.LogConfig.Type, "json-file"))
+	assert.Check(t, is.DeepEqual(changedSettings(&prevConfig, &prevHostConfig, c), []string{"Memory", "Healthcheck", "PortBindings"}))
+	assert.Check(t, is.Len(prevConfig.ExposedPorts, 1))
+}
+
+func TestValidateUpdateLabels(t *testing.T) {
+	current := map[string]string{
+		"foo":                      "bar",
+		"com.docker.swarm.task.id": "abc",
+	}
+
+	assert.Check(t, validateUpdateLabels(current, map[string]string{
+		"com.docker.swarm.task.id": "abc",
+	}))
+	assert.Check(t, is.ErrorContains(validateUpdateLabels(current, map[string]string{
+		"foo": "bar",
+	}), "label com.docker.swarm.task.id is managed by swarm"))
+	assert.Check(t, is.ErrorContains(validateUpdateLabels(current, map[string]string{
+		"com.docker.swarm.task.id": "def",
+	}), "label com.docker.swarm.task.id is managed by swarm"))
+	assert.Check(t, is.ErrorContains(validateUpdateLabels(nil, map[string]string{
+		"com.docker.swarm.service.id": "abc",
+	}), "label com.docker.swarm.service.id is managed by swarm"))
+}
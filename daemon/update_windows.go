@@ -9,3 +9,8 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	// We don't support update, so do nothing
 	return nil
 }
+
+func validateUpdateDevices(devices []container.DeviceMapping) error {
+	// Devices cannot be updated on Windows; see UpdateContainer.
+	return nil
+}
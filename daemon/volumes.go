@@ -116,7 +116,13 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 				CopyData:    false,
 			}
 
-			if len(cp.Source) == 0 {
+			if cp.Type == mounttypes.TypeImage {
+				v, err := daemon.imageVolume(cp.Name)
+				if err != nil {
+					return err
+				}
+				cp.Volume = v
+			} else if len(cp.Source) == 0 {
 				v, err := daemon.volumes.Get(ctx, cp.Name, volumeopts.WithGetDriver(cp.Driver), volumeopts.WithGetReference(container.ID))
 				if err != nil {
 					return err
@@ -217,6 +223,12 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			}
 		}
 
+		if mp.Type == mounttypes.TypeImage {
+			if err := daemon.registerImageMount(container.OS, mp); err != nil {
+				return err
+			}
+		}
+
 		if mp.Type == mounttypes.TypeBind {
 			mp.SkipMountpointCreation = true
 		}
@@ -246,6 +258,14 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 // lazyInitializeVolume initializes a mountpoint's volume if needed.
 // This happens after a daemon restart.
 func (daemon *Daemon) lazyInitializeVolume(containerID string, m *volumemounts.MountPoint) error {
+	if m.Type == mounttypes.TypeImage && m.Volume == nil {
+		v, err := daemon.imageVolume(m.Name)
+		if err != nil {
+			return err
+		}
+		m.Volume = v
+		return nil
+	}
 	if len(m.Driver) > 0 && m.Volume == nil {
 		v, err := daemon.volumes.Get(context.TODO(), m.Name, volumeopts.WithGetDriver(m.Driver), volumeopts.WithGetReference(containerID))
 		if err != nil {
@@ -385,7 +405,7 @@ func (daemon *Daemon) VolumesService() *service.VolumesService {
 }
 
 type volumeMounter interface {
-	Mount(ctx context.Context, v *types.Volume, ref string) (string, error)
+	Mount(ctx context.Context, v *types.Volume, ref string, mountOpts ...volumeopts.MountOption) (string, error)
 	Unmount(ctx context.Context, v *types.Volume, ref string) error
 }
 
@@ -410,6 +430,14 @@ func (v *volumeWrapper) Mount(ref string) (string, error) {
 	return v.s.Mount(context.TODO(), v.v, ref)
 }
 
+func (v *volumeWrapper) MountWithOptions(ref string, mountOpts volume.MountOptions) (string, error) {
+	o := []volumeopts.MountOption{volumeopts.WithMountSubpath(mountOpts.Subpath)}
+	if mountOpts.ReadOnly {
+		o = append(o, volumeopts.WithMountReadOnly)
+	}
+	return v.s.Mount(context.TODO(), v.v, ref, o...)
+}
+
 func (v *volumeWrapper) Unmount(ref string) error {
 	return v.s.Unmount(context.TODO(), v.v, ref)
 }
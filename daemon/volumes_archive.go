@@ -0,0 +1,155 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/symlink"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VolumeArchivePath creates an archive of the resource at the specified path
+// in the volume with the given name. The path is relative to the root of the
+// volume; the whole volume is archived if it is empty or "/".
+// The volume is mounted, and can not be removed, until the returned archive
+// is closed.
+func (daemon *Daemon) VolumeArchivePath(ctx context.Context, name, path string) (content io.ReadCloser, err error) {
+	vol, release, err := daemon.mountVolumeForArchive(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	path = filepath.Clean(string(filepath.Separator) + filepath.FromSlash(path))
+	resolvedPath, err := symlink.FollowSymlinkInScope(filepath.Join(vol.Mountpoint, path), vol.Mountpoint)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(resolvedPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.NotFound(errors.Errorf("no such file or directory in volume %s: %s", name, path))
+		}
+		return nil, errdefs.System(err)
+	}
+
+	options := daemon.defaultTarCopyOptions(false)
+	sourceDir := resolvedPath
+	if resolvedPath != vol.Mountpoint {
+		// Archive the resource itself rather than its contents, named
+		// after the requested path, even if it is a symlink that was
+		// followed.
+		var sourceBase string
+		sourceDir, sourceBase = filepath.Split(resolvedPath)
+		rebaseOpts := archive.TarResourceRebaseOpts(sourceBase, filepath.Base(path))
+		options.IncludeFiles = rebaseOpts.IncludeFiles
+		options.RebaseNames = rebaseOpts.RebaseNames
+	}
+
+	data, err := chrootarchive.Tar(sourceDir, options, vol.Mountpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	content = ioutils.NewReadCloserWrapper(data, func() error {
+		err := data.Close()
+		release()
+		return err
+	})
+
+	daemon.LogVolumeEvent(vol.Name, "archive-path", map[string]string{"driver": vol.Driver})
+
+	return content, nil
+}
+
+// VolumeExtractToDir extracts the given archive to the directory at the
+// specified path in the volume with the given name. The volume is created
+// with the given driver if it does not exist. If noOverwriteDirNonDir is true
+// then it will be an error if unpacking the given content would cause an
+// existing directory to be replaced with a non-directory and vice versa.
+func (daemon *Daemon) VolumeExtractToDir(ctx context.Context, name, path, driverName string, noOverwriteDirNonDir bool, content io.Reader) error {
+	if _, err := daemon.volumes.Get(ctx, name); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := daemon.volumes.Create(ctx, name, driverName); err != nil {
+			return err
+		}
+	}
+
+	vol, release, err := daemon.mountVolumeForArchive(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	path = filepath.Clean(string(filepath.Separator) + filepath.FromSlash(path))
+	resolvedPath, err := symlink.FollowSymlinkInScope(filepath.Join(vol.Mountpoint, path), vol.Mountpoint)
+	if err != nil {
+		return err
+	}
+	stat, err := os.Lstat(resolvedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errdefs.NotFound(errors.Errorf("no such directory in volume %s: %s", name, path))
+		}
+		return errdefs.System(err)
+	}
+	if !stat.IsDir() {
+		return errdefs.InvalidParameter(ErrExtractPointNotDirectory)
+	}
+
+	options := daemon.defaultTarCopyOptions(noOverwriteDirNonDir)
+	if err := chrootarchive.UntarWithRoot(content, resolvedPath, options, vol.Mountpoint); err != nil {
+		return err
+	}
+
+	daemon.LogVolumeEvent(vol.Name, "extract-to-dir", map[string]string{"driver": vol.Driver})
+
+	return nil
+}
+
+// mountVolumeForArchive mounts the volume with the given name, and holds a
+// reference to it so that it can not be removed while it is mounted. The
+// returned function unmounts the volume and releases the reference.
+func (daemon *Daemon) mountVolumeForArchive(ctx context.Context, name string) (*types.Volume, func(), error) {
+	ref := "archive-" + stringid.GenerateRandomID()
+	vol, err := daemon.volumes.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		return nil, nil, err
+	}
+	releaseRef := func() {
+		// use a new context, as ctx may be cancelled once the archive
+		// was sent
+		if err := daemon.volumes.Release(context.Background(), vol.Name, ref); err != nil {
+			logrus.WithError(err).WithField("volume", vol.Name).Warn("Error releasing reference to volume")
+		}
+	}
+
+	mountpoint, err := daemon.volumes.Mount(ctx, vol, ref)
+	if err != nil {
+		releaseRef()
+		return nil, nil, err
+	}
+	vol.Mountpoint = filepath.Clean(mountpoint)
+
+	return vol, func() {
+		if err := daemon.volumes.Unmount(context.Background(), vol, ref); err != nil {
+			logrus.WithError(err).WithField("volume", vol.Name).Warn("Error unmounting volume")
+		}
+		releaseRef()
+	}, nil
+}
@@ -62,7 +62,7 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 			if m.Spec.Type == mounttypes.TypeBind && m.Spec.BindOptions != nil {
 				mnt.NonRecursive = m.Spec.BindOptions.NonRecursive
 			}
-			if m.Volume != nil {
+			if m.Volume != nil && m.Type != mounttypes.TypeImage {
 				attributes := map[string]string{
 					"driver":      m.Volume.DriverName(),
 					"container":   c.ID,
@@ -14,6 +14,7 @@ import (
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/system"
 	refstore "github.com/docker/docker/reference"
@@ -78,6 +79,10 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// LayerCompression is the compression applied to layers that are
+	// stored uncompressed before they are pushed. Only archive.Gzip and
+	// archive.Zstd are supported; any other value results in gzip.
+	LayerCompression archive.Compression
 }
 
 // ImageConfigStore handles storing and getting image configurations
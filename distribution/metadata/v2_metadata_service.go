@@ -33,6 +33,10 @@ var _ V2MetadataService = &v2MetadataService{}
 type V2Metadata struct {
 	Digest           digest.Digest
 	SourceRepository string
+	// MediaType is the media type of the blob referenced by Digest. It is
+	// empty for entries recorded before compressions other than gzip were
+	// supported, in which case the blob is a gzip compressed layer.
+	MediaType string `json:",omitempty"`
 	// HMAC hashes above attributes with recent authconfig digest used as a key in order to determine matching
 	// metadata entries accompanied by the same credentials without actually exposing them.
 	HMAC string
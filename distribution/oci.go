@@ -1,6 +1,9 @@
 package distribution
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/docker/distribution"
@@ -13,7 +16,7 @@ func init() {
 	// TODO: Remove this registration if distribution is included with OCI support
 
 	ocischemaFunc := func(b []byte) (distribution.Manifest, distribution.Descriptor, error) {
-		m := new(schema2.DeserializedManifest)
+		m := new(ociManifest)
 		err := m.UnmarshalJSON(b)
 		if err != nil {
 			return nil, distribution.Descriptor{}, err
@@ -27,3 +30,100 @@ func init() {
 		panic(fmt.Sprintf("Unable to register manifest: %s", err))
 	}
 }
+
+// ociManifest is an OCI image manifest. It has the same format as a schema2
+// manifest, with the media types of the OCI image specification.
+type ociManifest struct {
+	schema2.Manifest
+
+	// canonical is the canonical byte representation of the manifest.
+	canonical []byte
+}
+
+// ociManifestFromStruct returns the OCI image manifest of m and its JSON
+// representation.
+func ociManifestFromStruct(m schema2.Manifest) (*ociManifest, error) {
+	m.MediaType = ocispec.MediaTypeImageManifest
+	canonical, err := json.MarshalIndent(&m, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	return &ociManifest{Manifest: m, canonical: canonical}, nil
+}
+
+// UnmarshalJSON populates the manifest from JSON data. The media type of OCI
+// image manifests is optional.
+func (m *ociManifest) UnmarshalJSON(b []byte) error {
+	var manifest schema2.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return err
+	}
+	if manifest.MediaType != "" && manifest.MediaType != ocispec.MediaTypeImageManifest {
+		return fmt.Errorf("mediaType in manifest should be '%s' not '%s'", ocispec.MediaTypeImageManifest, manifest.MediaType)
+	}
+
+	m.Manifest = manifest
+	m.canonical = make([]byte, len(b))
+	copy(m.canonical, b)
+	return nil
+}
+
+// MarshalJSON returns the canonical representation of the manifest.
+func (m *ociManifest) MarshalJSON() ([]byte, error) {
+	if len(m.canonical) == 0 {
+		return nil, errors.New("JSON representation not initialized in ociManifest")
+	}
+	return m.canonical, nil
+}
+
+// Payload returns the raw content of the manifest.
+func (m *ociManifest) Payload() (string, []byte, error) {
+	return ocispec.MediaTypeImageManifest, m.canonical, nil
+}
+
+// ociManifestBuilder builds OCI image manifests. The configuration blob is
+// published by a schema2 builder, and the media types of the manifest, of
+// the image configuration and of the layers are then replaced by their OCI
+// equivalents.
+type ociManifestBuilder struct {
+	distribution.ManifestBuilder
+}
+
+// newOCIManifestBuilder returns a builder of OCI image manifests.
+func newOCIManifestBuilder(bs distribution.BlobService, configMediaType string, configJSON []byte) distribution.ManifestBuilder {
+	if configMediaType == schema2.MediaTypeImageConfig {
+		configMediaType = ocispec.MediaTypeImageConfig
+	}
+	return &ociManifestBuilder{schema2.NewManifestBuilder(bs, configMediaType, configJSON)}
+}
+
+// Build produces the OCI image manifest from the given references.
+func (mb *ociManifestBuilder) Build(ctx context.Context) (distribution.Manifest, error) {
+	built, err := mb.ManifestBuilder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := built.(*schema2.DeserializedManifest).Manifest
+	layers := make([]distribution.Descriptor, len(m.Layers))
+	for i, l := range m.Layers {
+		l.MediaType = ociLayerMediaType(l.MediaType)
+		layers[i] = l
+	}
+	m.Layers = layers
+	return ociManifestFromStruct(m)
+}
+
+// ociLayerMediaType returns the OCI media type of a layer with the given
+// schema2 media type.
+func ociLayerMediaType(mediaType string) string {
+	switch mediaType {
+	case schema2.MediaTypeLayer:
+		return ocispec.MediaTypeImageLayerGzip
+	case schema2.MediaTypeForeignLayer:
+		return ocispec.MediaTypeImageLayerNonDistributableGzip
+	case schema2.MediaTypeUncompressedLayer:
+		return ocispec.MediaTypeImageLayer
+	default:
+		return mediaType
+	}
+}
@@ -325,7 +325,7 @@ func (ld *v2LayerDescriptor) truncateDownloadFile() error {
 
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
-	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name()})
+	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name(), MediaType: metadataMediaType(ld.src.MediaType)})
 }
 
 func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform *specs.Platform) (tagUpdated bool, err error) {
@@ -358,20 +358,20 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform
 		return false, fmt.Errorf("image manifest does not exist for tag or digest %q", tagOrDigest)
 	}
 
-	if m, ok := manifest.(*schema2.DeserializedManifest); ok {
+	if m, ok := imageManifestOf(manifest); ok {
 		var allowedMediatype bool
 		for _, t := range p.config.Schema2Types {
-			if m.Manifest.Config.MediaType == t {
+			if m.Config.MediaType == t {
 				allowedMediatype = true
 				break
 			}
 		}
 		if !allowedMediatype {
-			configClass := mediaTypeClasses[m.Manifest.Config.MediaType]
+			configClass := mediaTypeClasses[m.Config.MediaType]
 			if configClass == "" {
 				configClass = "unknown"
 			}
-			return false, invalidManifestClassError{m.Manifest.Config.MediaType, configClass}
+			return false, invalidManifestClassError{m.Config.MediaType, configClass}
 		}
 	}
 
@@ -400,7 +400,7 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform
 		if err != nil {
 			return false, err
 		}
-	case *schema2.DeserializedManifest:
+	case *schema2.DeserializedManifest, *ociManifest:
 		id, manifestDigest, err = p.pullSchema2(ctx, ref, v, platform)
 		if err != nil {
 			return false, err
@@ -552,13 +552,31 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Reference, unv
 	return imageID, manifestDigest, nil
 }
 
-func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *schema2.DeserializedManifest, platform *specs.Platform) (id digest.Digest, manifestDigest digest.Digest, err error) {
+// imageManifestOf returns the content of a schema2 or OCI image manifest,
+// which have the same format.
+func imageManifestOf(m distribution.Manifest) (schema2.Manifest, bool) {
+	switch m := m.(type) {
+	case *schema2.DeserializedManifest:
+		return m.Manifest, true
+	case *ociManifest:
+		return m.Manifest, true
+	default:
+		return schema2.Manifest{}, false
+	}
+}
+
+// pullSchema2 pulls the image of a schema2 or OCI image manifest.
+func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst distribution.Manifest, platform *specs.Platform) (id digest.Digest, manifestDigest digest.Digest, err error) {
 	manifestDigest, err = schema2ManifestDigest(ref, mfst)
 	if err != nil {
 		return "", "", err
 	}
+	m, ok := imageManifestOf(mfst)
+	if !ok {
+		return "", "", invalidManifestFormatError{}
+	}
 
-	target := mfst.Target()
+	target := m.Target()
 	if _, err := p.config.ImageStore.Get(target.Digest); err == nil {
 		// If the image already exists locally, no need to pull
 		// anything.
@@ -569,7 +587,7 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 
 	// Note that the order of this loop is in the direction of bottom-most
 	// to top-most, so that the downloads slice gets ordered correctly.
-	for _, d := range mfst.Layers {
+	for _, d := range m.Layers {
 		layerDescriptor := &v2LayerDescriptor{
 			digest:            d.Digest,
 			repo:              p.repo,
@@ -800,7 +818,7 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		if err != nil {
 			return "", "", err
 		}
-	case *schema2.DeserializedManifest:
+	case *schema2.DeserializedManifest, *ociManifest:
 		platform := toOCIPlatform(manifestMatches[0].Platform)
 		id, _, err = p.pullSchema2(ctx, manifestRef, v, &platform)
 		if err != nil {
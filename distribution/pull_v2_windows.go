@@ -24,8 +24,18 @@ import (
 var _ distribution.Describable = &v2LayerDescriptor{}
 
 func (ld *v2LayerDescriptor) Descriptor() distribution.Descriptor {
-	if ld.src.MediaType == schema2.MediaTypeForeignLayer && len(ld.src.URLs) > 0 {
+	if len(ld.src.URLs) == 0 {
+		return distribution.Descriptor{}
+	}
+	switch ld.src.MediaType {
+	case schema2.MediaTypeForeignLayer:
 		return ld.src
+	case specs.MediaTypeImageLayerNonDistributableGzip:
+		// Foreign layers of OCI image manifests are recorded like the ones
+		// of schema2 manifests.
+		d := ld.src
+		d.MediaType = schema2.MediaTypeForeignLayer
+		return d
 	}
 	return distribution.Descriptor{}
 }
@@ -9,8 +9,10 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/registry"
+	"github.com/klauspost/compress/zstd"
 	"github.com/sirupsen/logrus"
 )
 
@@ -139,8 +141,9 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 	return lastErr
 }
 
-// compress returns an io.ReadCloser which will supply a compressed version of
-// the provided Reader. The caller must close the ReadCloser after reading the
+// compress returns an io.ReadCloser which will supply a version of the
+// provided Reader compressed with the given compression, which must be either
+// archive.Gzip or archive.Zstd. The caller must close the ReadCloser after reading the
 // compressed data.
 //
 // Note that this function returns a reader instead of taking a writer as an
@@ -152,13 +155,25 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 // is finished. This allows the caller to make sure the goroutine finishes
 // before it releases any resources connected with the reader that was
 // passed in.
-func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
+func compress(in io.Reader, compression archive.Compression) (io.ReadCloser, chan struct{}) {
 	compressionDone := make(chan struct{})
 
 	pipeReader, pipeWriter := io.Pipe()
 	// Use a bufio.Writer to avoid excessive chunking in HTTP request.
 	bufWriter := bufio.NewWriterSize(pipeWriter, compressionBufSize)
-	compressor := gzip.NewWriter(bufWriter)
+	var compressor io.WriteCloser
+	switch compression {
+	case archive.Zstd:
+		zstdWriter, err := zstd.NewWriter(bufWriter)
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			close(compressionDone)
+			return pipeReader, compressionDone
+		}
+		compressor = zstdWriter
+	default:
+		compressor = gzip.NewWriter(bufWriter)
+	}
 
 	go func() {
 		_, err := io.Copy(compressor, in)
@@ -20,6 +20,7 @@ import (
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/stringid"
@@ -33,6 +34,37 @@ const (
 	middleLayerMaximumSize = 10 * (1 << 20)  // 10MB
 )
 
+// mediaTypeImageLayerZstd is the media type used for zstd compressed layers.
+// It is defined by the OCI image specification, but not by the vendored
+// version of image-spec.
+const mediaTypeImageLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+
+// layerMediaType returns the media type of a layer pushed with the given
+// compression.
+func layerMediaType(compression archive.Compression) string {
+	if compression == archive.Zstd {
+		return mediaTypeImageLayerZstd
+	}
+	return schema2.MediaTypeLayer
+}
+
+// compressionMatches reports whether the blob referenced by the given v2
+// metadata was compressed with the given compression. Entries without a media
+// type were recorded before zstd support and refer to gzip compressed layers.
+func compressionMatches(meta metadata.V2Metadata, compression archive.Compression) bool {
+	return (meta.MediaType == mediaTypeImageLayerZstd) == (compression == archive.Zstd)
+}
+
+// metadataMediaType returns the media type recorded in the v2 metadata for a
+// layer blob with the given media type. Gzip compressed blobs are recorded
+// without a media type so that they match the entries of older versions.
+func metadataMediaType(mediaType string) string {
+	if mediaType == mediaTypeImageLayerZstd {
+		return mediaTypeImageLayerZstd
+	}
+	return ""
+}
+
 type v2Pusher struct {
 	v2MetadataService metadata.V2MetadataService
 	ref               reference.Named
@@ -151,6 +183,7 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 		endpoint:          p.endpoint,
 		repo:              p.repo,
 		pushState:         &p.pushState,
+		compression:       p.config.LayerCompression,
 	}
 
 	// Loop bounds condition is to avoid pushing the base layer on Windows.
@@ -167,8 +200,15 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 		return err
 	}
 
-	// Try schema2 first
-	builder := schema2.NewManifestBuilder(p.repo.Blobs(ctx), p.config.ConfigMediaType, imgConfig)
+	// Try schema2 first, unless the image has zstd compressed layers, which
+	// only OCI image manifests support.
+	oci := hasZstdLayer(descriptors)
+	var builder distribution.ManifestBuilder
+	if oci {
+		builder = newOCIManifestBuilder(p.repo.Blobs(ctx), p.config.ConfigMediaType, imgConfig)
+	} else {
+		builder = schema2.NewManifestBuilder(p.repo.Blobs(ctx), p.config.ConfigMediaType, imgConfig)
+	}
 	manifest, err := manifestFromBuilder(ctx, builder, descriptors)
 	if err != nil {
 		return err
@@ -181,6 +221,10 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 
 	putOptions := []distribution.ManifestServiceOption{distribution.WithTag(ref.Tag())}
 	if _, err = manSvc.Put(ctx, manifest, putOptions...); err != nil {
+		if oci {
+			logrus.Warnf("failed to upload OCI image manifest: %v", err)
+			return err
+		}
 		if runtime.GOOS == "windows" || p.config.TrustKey == nil || p.config.RequireSchema2 {
 			logrus.Warnf("failed to upload schema2 manifest: %v", err)
 			return err
@@ -217,6 +261,11 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 		if err != nil {
 			return err
 		}
+	case *ociManifest:
+		_, canonicalManifest, err = v.Payload()
+		if err != nil {
+			return err
+		}
 	}
 
 	manifestDigest := digest.FromBytes(canonicalManifest)
@@ -233,6 +282,16 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 	return nil
 }
 
+// hasZstdLayer reports whether any of the uploaded layers is zstd compressed.
+func hasZstdLayer(descriptors []xfer.UploadDescriptor) bool {
+	for _, d := range descriptors {
+		if d.(*v2PushDescriptor).Descriptor().MediaType == mediaTypeImageLayerZstd {
+			return true
+		}
+	}
+	return false
+}
+
 func manifestFromBuilder(ctx context.Context, builder distribution.ManifestBuilder, descriptors []xfer.UploadDescriptor) (distribution.Manifest, error) {
 	// descriptors is in reverse order; iterate backwards to get references
 	// appended in the right order.
@@ -257,6 +316,10 @@ type v2PushDescriptor struct {
 	remoteDescriptor  distribution.Descriptor
 	// a set of digests whose presence has been checked in a target repository
 	checkedDigests map[digest.Digest]struct{}
+	// compression is applied to uncompressed layers before they are pushed,
+	// and restricts which known blobs can be reused for the layer. Anything
+	// other than archive.Zstd results in gzip.
+	compression archive.Compression
 }
 
 func (pd *v2PushDescriptor) Key() string {
@@ -313,7 +376,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	var layerUpload distribution.BlobWriter
 
 	// Attempt to find another repository in the same registry to mount the layer from to avoid an unnecessary upload
-	candidates := getRepositoryMountCandidates(pd.repoInfo, pd.hmacKey, maxMountAttempts, v2Metadata)
+	candidates := getRepositoryMountCandidates(pd.repoInfo, pd.hmacKey, maxMountAttempts, pd.compression, v2Metadata)
 	isUnauthorizedError := false
 	for _, mountCandidate := range candidates {
 		logrus.Debugf("attempting to mount layer %s (%s) from %s", diffID, mountCandidate.Digest, mountCandidate.SourceRepository)
@@ -352,7 +415,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
 
-			err.Descriptor.MediaType = schema2.MediaTypeLayer
+			err.Descriptor.MediaType = layerMediaType(pd.compression)
 
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
@@ -363,6 +426,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 				Digest:           err.Descriptor.Digest,
 				SourceRepository: pd.repoInfo.Name(),
+				MediaType:        mountCandidate.MediaType,
 			}); err != nil {
 				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 			}
@@ -449,15 +513,18 @@ func (pd *v2PushDescriptor) uploadUsingSession(
 
 	reader = progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, contentReader), progressOutput, size, pd.ID(), "Pushing")
 
+	mediaType := layerMediaType(pd.compression)
 	switch m := pd.layer.MediaType(); m {
 	case schema2.MediaTypeUncompressedLayer:
-		compressedReader, compressionDone := compress(reader)
+		compressedReader, compressionDone := compress(reader, pd.compression)
 		defer func(closer io.Closer) {
 			closer.Close()
 			<-compressionDone
 		}(reader)
 		reader = compressedReader
-	case schema2.MediaTypeLayer:
+	case schema2.MediaTypeLayer, mediaTypeImageLayerZstd:
+		// already compressed layers are pushed as-is
+		mediaType = m
 	default:
 		reader.Close()
 		return distribution.Descriptor{}, fmt.Errorf("unsupported layer media type %s", m)
@@ -484,13 +551,14 @@ func (pd *v2PushDescriptor) uploadUsingSession(
 	if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 		Digest:           pushDigest,
 		SourceRepository: pd.repoInfo.Name(),
+		MediaType:        metadataMediaType(mediaType),
 	}); err != nil {
 		return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 	}
 
 	desc := distribution.Descriptor{
 		Digest:    pushDigest,
-		MediaType: schema2.MediaTypeLayer,
+		MediaType: mediaType,
 		Size:      nn,
 	}
 
@@ -521,6 +589,9 @@ func (pd *v2PushDescriptor) layerAlreadyExists(
 		if len(meta.SourceRepository) > 0 && !checkOtherRepositories && meta.SourceRepository != pd.repoInfo.Name() {
 			continue
 		}
+		if !compressionMatches(meta, pd.compression) {
+			continue
+		}
 		candidates = append(candidates, meta)
 	}
 	// sort the candidates by similarity
@@ -559,11 +630,12 @@ attempts:
 				if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 					Digest:           desc.Digest,
 					SourceRepository: pd.repoInfo.Name(),
+					MediaType:        meta.MediaType,
 				}); err != nil {
 					return distribution.Descriptor{}, false, xfer.DoNotRetry{Err: err}
 				}
 			}
-			desc.MediaType = schema2.MediaTypeLayer
+			desc.MediaType = layerMediaType(pd.compression)
 			exists = true
 			break attempts
 		case distribution.ErrBlobUnknown:
@@ -616,11 +688,13 @@ func getMaxMountAndExistenceCheckAttempts(layer PushLayer) (maxMountAttempts, ma
 
 // getRepositoryMountCandidates returns an array of v2 metadata items belonging to the given registry. The
 // array is sorted from youngest to oldest. If requireRegistryMatch is true, the resulting array will contain
-// only metadata entries having registry part of SourceRepository matching the part of repoInfo.
+// only metadata entries having registry part of SourceRepository matching the part of repoInfo. Only entries
+// referring to blobs compressed with the given compression are returned.
 func getRepositoryMountCandidates(
 	repoInfo reference.Named,
 	hmacKey []byte,
 	max int,
+	compression archive.Compression,
 	v2Metadata []metadata.V2Metadata,
 ) []metadata.V2Metadata {
 	candidates := []metadata.V2Metadata{}
@@ -629,6 +703,9 @@ func getRepositoryMountCandidates(
 		if err != nil || reference.Domain(repoInfo) != reference.Domain(sourceRepo) {
 			continue
 		}
+		if !compressionMatches(meta, compression) {
+			continue
+		}
 		// target repository is not a viable candidate
 		if meta.SourceRepository == repoInfo.Name() {
 			continue
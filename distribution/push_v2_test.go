@@ -2,6 +2,8 @@ package distribution // import "github.com/docker/docker/distribution"
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -13,11 +15,17 @@ import (
 	"github.com/docker/distribution/registry/api/errcode"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/xfer"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/progress"
 	refstore "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestGetRepositoryMountCandidates(t *testing.T) {
@@ -26,6 +34,7 @@ func TestGetRepositoryMountCandidates(t *testing.T) {
 		hmacKey       string
 		targetRepo    string
 		maxCandidates int
+		compression   archive.Compression
 		metadata      []metadata.V2Metadata
 		candidates    []metadata.V2Metadata
 	}{
@@ -126,12 +135,38 @@ func TestGetRepositoryMountCandidates(t *testing.T) {
 				taggedMetadata("hash", "5", "docker.io/user/foo"),
 			},
 		},
+		{
+			name:          "filter gzip blobs for zstd push",
+			targetRepo:    "user/app",
+			maxCandidates: -1,
+			compression:   archive.Zstd,
+			metadata: []metadata.V2Metadata{
+				{Digest: digest.Digest("1"), SourceRepository: "docker.io/user/foo"},
+				{Digest: digest.Digest("2"), SourceRepository: "docker.io/user/bar", MediaType: mediaTypeImageLayerZstd},
+			},
+			candidates: []metadata.V2Metadata{
+				{Digest: digest.Digest("2"), SourceRepository: "docker.io/user/bar", MediaType: mediaTypeImageLayerZstd},
+			},
+		},
+		{
+			name:          "filter zstd blobs for gzip push",
+			targetRepo:    "user/app",
+			maxCandidates: -1,
+			compression:   archive.Gzip,
+			metadata: []metadata.V2Metadata{
+				{Digest: digest.Digest("1"), SourceRepository: "docker.io/user/foo"},
+				{Digest: digest.Digest("2"), SourceRepository: "docker.io/user/bar", MediaType: mediaTypeImageLayerZstd},
+			},
+			candidates: []metadata.V2Metadata{
+				{Digest: digest.Digest("1"), SourceRepository: "docker.io/user/foo"},
+			},
+		},
 	} {
 		repoInfo, err := reference.ParseNormalizedNamed(tc.targetRepo)
 		if err != nil {
 			t.Fatalf("[%s] failed to parse reference name: %v", tc.name, err)
 		}
-		candidates := getRepositoryMountCandidates(repoInfo, []byte(tc.hmacKey), tc.maxCandidates, tc.metadata)
+		candidates := getRepositoryMountCandidates(repoInfo, []byte(tc.hmacKey), tc.maxCandidates, tc.compression, tc.metadata)
 		if len(candidates) != len(tc.candidates) {
 			t.Errorf("[%s] got unexpected number of candidates: %d != %d", tc.name, len(candidates), len(tc.candidates))
 		}
@@ -738,3 +773,137 @@ func (s *progressSink) WriteProgress(p progress.Progress) error {
 	s.t.Logf("progress update: %#+v", p)
 	return nil
 }
+
+type mockImageConfigStore struct {
+	config []byte
+}
+
+func (s *mockImageConfigStore) Put([]byte) (digest.Digest, error) {
+	return "", errors.New("Put() not implemented")
+}
+func (s *mockImageConfigStore) Get(digest.Digest) ([]byte, error) {
+	return s.config, nil
+}
+func (s *mockImageConfigStore) RootFSFromConfig(c []byte) (*image.RootFS, error) {
+	img, err := image.NewFromJSON(c)
+	if err != nil {
+		return nil, err
+	}
+	return img.RootFS, nil
+}
+func (s *mockImageConfigStore) PlatformFromConfig([]byte) (*specs.Platform, error) {
+	return &specs.Platform{OS: "linux"}, nil
+}
+
+type mockPushLayerProvider struct{}
+
+func (mockPushLayerProvider) Get(layer.ChainID) (PushLayer, error) {
+	return &storeLayer{Layer: layer.EmptyLayer}, nil
+}
+
+type mockRepoWithManifests struct {
+	mockRepo
+	manifests mockManifestService
+}
+
+func (m *mockRepoWithManifests) Blobs(ctx context.Context) distribution.BlobStore {
+	return &mockBlobStoreWithPut{mockBlobStore{repo: &m.mockRepo}}
+}
+func (m *mockRepoWithManifests) Manifests(ctx context.Context, options ...distribution.ManifestServiceOption) (distribution.ManifestService, error) {
+	return &m.manifests, nil
+}
+
+type mockBlobStoreWithPut struct {
+	mockBlobStore
+}
+
+func (m *mockBlobStoreWithPut) Put(ctx context.Context, mediaType string, p []byte) (distribution.Descriptor, error) {
+	return distribution.Descriptor{Digest: digest.FromBytes(p), Size: int64(len(p)), MediaType: "application/octet-stream"}, nil
+}
+
+type mockManifestService struct {
+	distribution.ManifestService
+	put distribution.Manifest
+}
+
+func (m *mockManifestService) Put(ctx context.Context, manifest distribution.Manifest, options ...distribution.ManifestServiceOption) (digest.Digest, error) {
+	m.put = manifest
+	_, payload, err := manifest.Payload()
+	return digest.FromBytes(payload), err
+}
+
+func TestPushManifestMediaType(t *testing.T) {
+	config, err := json.Marshal(&image.Image{RootFS: &image.RootFS{Type: "layers", DiffIDs: []layer.DiffID{layer.EmptyLayer.DiffID()}}})
+	assert.NilError(t, err)
+	ref, err := reference.ParseNormalizedNamed("user/app:latest")
+	assert.NilError(t, err)
+	layerDigest := digest.Digest("sha256:ff3a5c916c92643ff77519ffa742d3ec61b7f591b6b7504599d95a4a41134e28")
+
+	for _, tc := range []struct {
+		name              string
+		layerMediaType    string
+		manifestMediaType string
+		configMediaType   string
+		expectedLayerType string
+	}{
+		{
+			name:              "gzip",
+			layerMediaType:    schema2.MediaTypeLayer,
+			manifestMediaType: schema2.MediaTypeManifest,
+			configMediaType:   schema2.MediaTypeImageConfig,
+			expectedLayerType: schema2.MediaTypeLayer,
+		},
+		{
+			name:              "zstd",
+			layerMediaType:    mediaTypeImageLayerZstd,
+			manifestMediaType: specs.MediaTypeImageManifest,
+			configMediaType:   specs.MediaTypeImageConfig,
+			expectedLayerType: mediaTypeImageLayerZstd,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockRepoWithManifests{mockRepo: mockRepo{t: t}}
+			p := &v2Pusher{
+				v2MetadataService: &mockV2MetadataService{},
+				ref:               ref,
+				repoInfo:          &registry.RepositoryInfo{Name: ref},
+				repo:              repo,
+				config: &ImagePushConfig{
+					Config: Config{
+						ImageStore:     &mockImageConfigStore{config: config},
+						ReferenceStore: &mockReferenceStore{},
+						ProgressOutput: &progressSink{t},
+					},
+					ConfigMediaType:  schema2.MediaTypeImageConfig,
+					LayerStores:      map[string]PushLayerProvider{"linux": mockPushLayerProvider{}},
+					UploadManager:    xfer.NewLayerUploadManager(1),
+					LayerCompression: archive.Zstd,
+				},
+				pushState: pushState{
+					remoteLayers: map[layer.DiffID]distribution.Descriptor{
+						layer.EmptyLayer.DiffID(): {Digest: layerDigest, Size: 32, MediaType: tc.layerMediaType},
+					},
+				},
+			}
+			err := p.pushV2Tag(context.Background(), ref.(reference.NamedTagged), digest.FromBytes(config))
+			assert.NilError(t, err)
+
+			assert.Assert(t, repo.manifests.put != nil)
+			mediaType, payload, err := repo.manifests.put.Payload()
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(mediaType, tc.manifestMediaType))
+
+			// The pushed manifest is pulled back as the same manifest.
+			pulled, _, err := distribution.UnmarshalManifest(mediaType, payload)
+			assert.NilError(t, err)
+			m, ok := imageManifestOf(pulled)
+			assert.Assert(t, ok)
+			assert.Check(t, is.Equal(m.MediaType, tc.manifestMediaType))
+			assert.Check(t, is.Equal(m.Config.MediaType, tc.configMediaType))
+			assert.Check(t, is.Equal(m.Config.Digest, digest.FromBytes(config)))
+			assert.Assert(t, is.Len(m.Layers, 1))
+			assert.Check(t, is.Equal(m.Layers[0].MediaType, tc.expectedLayerType))
+			assert.Check(t, is.Equal(m.Layers[0].Digest, layerDigest))
+		})
+	}
+}
@@ -312,13 +312,36 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				}
 			}
 
+			var src distribution.Descriptor
+			if fs, ok := descriptor.(distribution.Describable); ok {
+				src = fs.Descriptor()
+			}
+
+			// If the layer store supports it, decompress and stage the
+			// layer while the parent layer is still being downloaded
+			// or extracted, so only committing the layer on top of its
+			// parent has to wait.
+			var staged layer.StagedLayer
+			if ss, ok := d.layerStore.(layer.StagingStore); ok && ss.CanStage() && parentDownload != nil {
+				staged, err = ldm.stageLayer(d, ss, descriptor, downloadReader, size, src, progressOutput)
+				if err != nil {
+					d.err = err
+					return
+				}
+				progress.Update(progressOutput, descriptor.ID(), "Waiting for parent layer")
+			}
+
 			close(inactive)
 
 			if parentDownload != nil {
 				select {
 				case <-d.Transfer.Context().Done():
 					d.err = errors.New("layer registration cancelled")
-					downloadReader.Close()
+					if staged != nil {
+						staged.Discard()
+					} else {
+						downloadReader.Close()
+					}
 					return
 				case <-parentDownload.Done():
 				}
@@ -326,29 +349,34 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				l, err := parentDownload.result()
 				if err != nil {
 					d.err = err
-					downloadReader.Close()
+					if staged != nil {
+						staged.Discard()
+					} else {
+						downloadReader.Close()
+					}
 					return
 				}
 				parentLayer = l.ChainID()
 			}
 
-			reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
-			defer reader.Close()
+			if staged != nil {
+				d.layer, err = d.layerStore.(layer.StagingStore).Commit(staged, parentLayer)
+			} else {
+				reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
+				defer reader.Close()
 
-			inflatedLayerData, err := archive.DecompressStream(reader)
-			if err != nil {
-				d.err = fmt.Errorf("could not get decompression stream: %v", err)
-				return
-			}
+				var inflatedLayerData io.ReadCloser
+				inflatedLayerData, err = archive.DecompressStream(reader)
+				if err != nil {
+					d.err = fmt.Errorf("could not get decompression stream: %v", err)
+					return
+				}
 
-			var src distribution.Descriptor
-			if fs, ok := descriptor.(distribution.Describable); ok {
-				src = fs.Descriptor()
-			}
-			if ds, ok := d.layerStore.(layer.DescribableStore); ok {
-				d.layer, err = ds.RegisterWithDescriptor(inflatedLayerData, parentLayer, src)
-			} else {
-				d.layer, err = d.layerStore.Register(inflatedLayerData, parentLayer)
+				if ds, ok := d.layerStore.(layer.DescribableStore); ok {
+					d.layer, err = ds.RegisterWithDescriptor(inflatedLayerData, parentLayer, src)
+				} else {
+					d.layer, err = d.layerStore.Register(inflatedLayerData, parentLayer)
+				}
 			}
 			if err != nil {
 				select {
@@ -380,6 +408,30 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 	}
 }
 
+// stageLayer decompresses the downloaded layer data and stages it in the
+// layer store, without waiting for the parent layer to be registered.
+func (ldm *LayerDownloadManager) stageLayer(d *downloadTransfer, ss layer.StagingStore, descriptor DownloadDescriptor, downloadReader io.ReadCloser, size int64, src distribution.Descriptor, progressOutput progress.Output) (layer.StagedLayer, error) {
+	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(d.Transfer.Context(), downloadReader), progressOutput, size, descriptor.ID(), "Extracting")
+	defer reader.Close()
+
+	inflatedLayerData, err := archive.DecompressStream(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not get decompression stream: %v", err)
+	}
+	defer inflatedLayerData.Close()
+
+	staged, err := ss.Stage(inflatedLayerData, src)
+	if err != nil {
+		select {
+		case <-d.Transfer.Context().Done():
+			return nil, errors.New("layer registration cancelled")
+		default:
+			return nil, fmt.Errorf("failed to stage layer: %v", err)
+		}
+	}
+	return staged, nil
+}
+
 // makeDownloadFuncFromDownload returns a function that performs the layer
 // registration when the layer data is coming from an existing download. It
 // waits for sourceDownload and parentDownload to complete, and then
@@ -116,6 +116,44 @@ func (ls *mockLayerStore) RegisterWithDescriptor(reader io.Reader, parentID laye
 	return l, nil
 }
 
+// mockStagingLayerStore is a mockLayerStore which supports staging layers
+// before their parent is registered.
+type mockStagingLayerStore struct {
+	*mockLayerStore
+	noStaging bool
+	staged    int32
+}
+
+type mockStagedLayer struct {
+	data   []byte
+	diffID layer.DiffID
+}
+
+func (sl *mockStagedLayer) DiffID() layer.DiffID {
+	return sl.diffID
+}
+
+func (sl *mockStagedLayer) Discard() error {
+	return nil
+}
+
+func (ls *mockStagingLayerStore) CanStage() bool {
+	return !ls.noStaging
+}
+
+func (ls *mockStagingLayerStore) Stage(reader io.Reader, _ distribution.Descriptor) (layer.StagedLayer, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&ls.staged, 1)
+	return &mockStagedLayer{data: data, diffID: layer.DiffID(digest.FromBytes(data))}, nil
+}
+
+func (ls *mockStagingLayerStore) Commit(staged layer.StagedLayer, parentID layer.ChainID) (layer.Layer, error) {
+	return ls.Register(bytes.NewReader(staged.(*mockStagedLayer).data), parentID)
+}
+
 func (ls *mockLayerStore) Get(chainID layer.ChainID) (layer.Layer, error) {
 	l, ok := ls.layers[chainID]
 	if !ok {
@@ -267,7 +305,40 @@ func TestSuccessfulDownload(t *testing.T) {
 		t.Skip("Needs fixing on Windows")
 	}
 
-	layerStore := &mockLayerStore{make(map[layer.ChainID]*mockLayer)}
+	testSuccessfulDownload(t, &mockLayerStore{make(map[layer.ChainID]*mockLayer)})
+}
+
+func TestSuccessfulDownloadWithStaging(t *testing.T) {
+	// TODO Windows: Fix this unit text
+	if runtime.GOOS == "windows" {
+		t.Skip("Needs fixing on Windows")
+	}
+
+	layerStore := &mockStagingLayerStore{mockLayerStore: &mockLayerStore{make(map[layer.ChainID]*mockLayer)}}
+	testSuccessfulDownload(t, layerStore)
+
+	// All layers but the first one to be downloaded are staged. id2 is
+	// only downloaded once, and id1 already exists.
+	if staged := atomic.LoadInt32(&layerStore.staged); staged != 3 {
+		t.Fatalf("unexpected number of staged layers: %d", staged)
+	}
+}
+
+func TestSuccessfulDownloadStagingNotSupported(t *testing.T) {
+	// TODO Windows: Fix this unit text
+	if runtime.GOOS == "windows" {
+		t.Skip("Needs fixing on Windows")
+	}
+
+	layerStore := &mockStagingLayerStore{mockLayerStore: &mockLayerStore{make(map[layer.ChainID]*mockLayer)}, noStaging: true}
+	testSuccessfulDownload(t, layerStore)
+
+	if staged := atomic.LoadInt32(&layerStore.staged); staged != 0 {
+		t.Fatalf("unexpected number of staged layers: %d", staged)
+	}
+}
+
+func testSuccessfulDownload(t *testing.T, layerStore layer.Store) {
 	lsMap := make(map[string]layer.Store)
 	lsMap[runtime.GOOS] = layerStore
 	ldm := NewLayerDownloadManager(lsMap, maxDownloadConcurrency, func(m *LayerDownloadManager) { m.waitDuration = time.Millisecond })
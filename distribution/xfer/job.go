@@ -0,0 +1,297 @@
+package xfer // import "github.com/docker/docker/distribution/xfer"
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/pkg/errors"
+)
+
+// Job states reported by Job.Status.
+const (
+	JobRunning   = "running"
+	JobComplete  = "complete"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// jobRetention is the time a finished job is kept, so that clients which
+// were disconnected while it was running can retrieve its result.
+const jobRetention = 10 * time.Minute
+
+// JobFunc performs the work of a job, reporting progress to progressOutput.
+// It must return once ctx is cancelled.
+type JobFunc func(ctx context.Context, progressOutput progress.Output) error
+
+// Job is an image level transfer, such as the pull or push of an image. The
+// layer transfers it triggers are scheduled by the LayerDownloadManager and
+// LayerUploadManager. Unlike a Transfer, a job is not cancelled when all of
+// its watchers are released; it keeps running until it completes or is
+// cancelled explicitly, and can be watched again while it runs.
+type Job struct {
+	ID      string
+	Action  string
+	Ref     string
+	Created time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// states holds the last progress for each progress ID, in the order
+	// they were first reported, as well as all messages without an ID.
+	states    []progress.Progress
+	watchers  map[*JobWatcher]struct{}
+	finished  time.Time
+	err       error
+	cancelled bool
+	done      chan struct{}
+}
+
+// JobWatcher is returned by Job.Watch and can be passed to Job.Release to
+// stop watching.
+type JobWatcher struct {
+	pending []progress.Progress
+	signal  chan struct{}
+	release chan struct{}
+	running chan struct{}
+}
+
+// enqueue adds p to the list of progress messages. Progress for an ID which
+// is already in the list replaces the previous progress for that ID, so that
+// a slow watcher only gets the most recent state of each layer.
+func enqueue(list []progress.Progress, p progress.Progress) []progress.Progress {
+	if p.ID != "" {
+		for i := range list {
+			if list[i].ID == p.ID {
+				list[i] = p
+				return list
+			}
+		}
+	}
+	return append(list, p)
+}
+
+// WriteProgress implements progress.Output. It records the progress and
+// forwards it to all watchers of the job.
+func (j *Job) WriteProgress(p progress.Progress) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.states = enqueue(j.states, p)
+	for w := range j.watchers {
+		w.pending = enqueue(w.pending, p)
+		select {
+		case w.signal <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Watch adds a watcher to the job. The current state of the job is written to
+// progressOutput first, followed by progress updates until the job finishes
+// or the watcher is released.
+func (j *Job) Watch(progressOutput progress.Output) *JobWatcher {
+	j.mu.Lock()
+	w := &JobWatcher{
+		pending: append([]progress.Progress(nil), j.states...),
+		signal:  make(chan struct{}, 1),
+		release: make(chan struct{}),
+		running: make(chan struct{}),
+	}
+	j.watchers[w] = struct{}{}
+	j.mu.Unlock()
+
+	go func() {
+		defer close(w.running)
+		for {
+			j.mu.Lock()
+			pending := w.pending
+			w.pending = nil
+			j.mu.Unlock()
+
+			for _, p := range pending {
+				progressOutput.WriteProgress(p)
+			}
+
+			select {
+			case <-w.signal:
+				continue
+			case <-w.release:
+				select {
+				case <-j.done:
+				default:
+					return
+				}
+			case <-j.done:
+			}
+
+			// write anything reported before the job finished
+			j.mu.Lock()
+			pending = w.pending
+			w.pending = nil
+			j.mu.Unlock()
+			for _, p := range pending {
+				progressOutput.WriteProgress(p)
+			}
+			return
+		}
+	}()
+
+	return w
+}
+
+// Release is the inverse of Watch. It blocks until the watcher stopped
+// writing progress. Releasing the last watcher does not cancel the job.
+func (j *Job) Release(w *JobWatcher) {
+	j.mu.Lock()
+	delete(j.watchers, w)
+	j.mu.Unlock()
+
+	close(w.release)
+	<-w.running
+}
+
+// Done returns a channel which is closed once the job finished.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Err returns the error the job finished with, if any.
+func (j *Job) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// Cancel cancels the job. It does not wait for the job to finish.
+func (j *Job) Cancel() {
+	j.mu.Lock()
+	select {
+	case <-j.done:
+	default:
+		j.cancelled = true
+	}
+	j.mu.Unlock()
+	j.cancel()
+}
+
+// Status returns the state of the job, and the time it finished at if it is
+// no longer running.
+func (j *Job) Status() (string, time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	select {
+	case <-j.done:
+	default:
+		return JobRunning, time.Time{}
+	}
+	switch {
+	case j.err == nil:
+		return JobComplete, j.finished
+	case j.cancelled:
+		return JobCancelled, j.finished
+	default:
+		return JobFailed, j.finished
+	}
+}
+
+// JobManager keeps track of running and recently finished jobs.
+type JobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewJobManager returns a new JobManager.
+func NewJobManager() *JobManager {
+	return &JobManager{
+		jobs: make(map[string]*Job),
+	}
+}
+
+// Start starts a job running fn, and returns the job. The job is not tied to
+// the lifetime of any client request.
+func (jm *JobManager) Start(action, ref string, fn JobFunc) *Job {
+	j := &Job{
+		ID:       stringid.GenerateRandomID(),
+		Action:   action,
+		Ref:      ref,
+		Created:  time.Now().UTC(),
+		watchers: make(map[*JobWatcher]struct{}),
+		done:     make(chan struct{}),
+	}
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+
+	jm.mu.Lock()
+	jm.prune()
+	jm.jobs[j.ID] = j
+	jm.mu.Unlock()
+
+	go func() {
+		err := fn(j.ctx, j)
+		j.mu.Lock()
+		j.err = err
+		j.finished = time.Now().UTC()
+		close(j.done)
+		j.mu.Unlock()
+		j.cancel()
+	}()
+
+	return j
+}
+
+// Get returns the job with the given ID. Any unique prefix of the ID is
+// accepted.
+func (jm *JobManager) Get(id string) (*Job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.prune()
+
+	if j, ok := jm.jobs[id]; ok {
+		return j, nil
+	}
+	var found *Job
+	for jobID, j := range jm.jobs {
+		if len(id) > 0 && len(jobID) >= len(id) && jobID[:len(id)] == id {
+			if found != nil {
+				return nil, errdefs.InvalidParameter(errors.Errorf("multiple transfers found with provided prefix: %s", id))
+			}
+			found = j
+		}
+	}
+	if found == nil {
+		return nil, errdefs.NotFound(errors.Errorf("no such transfer: %s", id))
+	}
+	return found, nil
+}
+
+// List returns all running and recently finished jobs, oldest first.
+func (jm *JobManager) List() []*Job {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	jm.prune()
+
+	jobs := make([]*Job, 0, len(jm.jobs))
+	for _, j := range jm.jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Created.Before(jobs[k].Created)
+	})
+	return jobs
+}
+
+// prune removes jobs which finished longer than jobRetention ago. It must be
+// called with jm.mu held.
+func (jm *JobManager) prune() {
+	for id, j := range jm.jobs {
+		if status, finished := j.Status(); status != JobRunning && time.Since(finished) > jobRetention {
+			delete(jm.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package xfer // import "github.com/docker/docker/distribution/xfer"
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+)
+
+func TestJobReattach(t *testing.T) {
+	jm := NewJobManager()
+
+	step := make(chan struct{})
+	job := jm.Start("pull", "busybox", func(ctx context.Context, progressOutput progress.Output) error {
+		progress.Update(progressOutput, "layer1", "Downloading")
+		progress.Update(progressOutput, "layer2", "Downloading")
+		<-step
+		progress.Update(progressOutput, "layer1", "Pull complete")
+		<-step
+		progress.Update(progressOutput, "layer2", "Pull complete")
+		return nil
+	})
+
+	// Attach and detach without affecting the job.
+	first := make(chan progress.Progress, 10)
+	w := job.Watch(progress.ChanOutput(first))
+	step <- struct{}{}
+	job.Release(w)
+
+	if status, _ := job.Status(); status != JobRunning {
+		t.Fatalf("expected job to keep running after its watcher was released, got %s", status)
+	}
+
+	// A new watcher gets the current state of each layer.
+	progressChan := make(chan progress.Progress, 10)
+	w = job.Watch(progress.ChanOutput(progressChan))
+	step <- struct{}{}
+	<-job.Done()
+	job.Release(w)
+	close(progressChan)
+
+	received := map[string]string{}
+	for p := range progressChan {
+		received[p.ID] = p.Action
+	}
+	if received["layer1"] != "Pull complete" || received["layer2"] != "Pull complete" {
+		t.Fatalf("unexpected progress after reattaching: %v", received)
+	}
+	if status, _ := job.Status(); status != JobComplete {
+		t.Fatalf("expected job to be complete, got %s", status)
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	jm := NewJobManager()
+
+	job := jm.Start("push", "busybox", func(ctx context.Context, progressOutput progress.Output) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	found, err := jm.Get(job.ID[:12])
+	if err != nil {
+		t.Fatal(err)
+	}
+	found.Cancel()
+
+	select {
+	case <-job.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("job was not cancelled")
+	}
+	if status, _ := job.Status(); status != JobCancelled {
+		t.Fatalf("expected job to be cancelled, got %s", status)
+	}
+	if err := job.Err(); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobs := jm.List(); len(jobs) != 1 || jobs[0] != job {
+		t.Fatalf("expected finished job to be listed, got %v", jobs)
+	}
+}
+
+func TestJobFailed(t *testing.T) {
+	jm := NewJobManager()
+
+	job := jm.Start("pull", "busybox", func(ctx context.Context, progressOutput progress.Output) error {
+		return errors.New("manifest unknown")
+	})
+	<-job.Done()
+	if status, _ := job.Status(); status != JobFailed {
+		t.Fatalf("expected job to have failed, got %s", status)
+	}
+	if _, err := jm.Get("nonexistent"); err == nil {
+		t.Fatal("expected an error getting a nonexistent job")
+	}
+}
@@ -213,6 +213,9 @@ type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
 	Save([]string, io.Writer) error
+	// SaveExcludingLayers is like Save, but leaves out the content of the
+	// given layers, which the receiver of the archive already has.
+	SaveExcludingLayers([]string, []layer.ChainID, io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
@@ -126,6 +126,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 		if err != nil {
 			return err
 		}
+		if err := l.is.SetLastUpdated(imgID); err != nil {
+			return err
+		}
 		imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
 
 		imageRefCount = 0
@@ -183,6 +186,11 @@ func (l *tarexporter) loadLayer(filename string, rootFS image.RootFS, id string,
 	rawTar, err := system.OpenSequential(filename)
 	if err != nil {
 		logrus.Debugf("Error reading embedded tar: %v", err)
+		if isNotExist(err) {
+			// The archive was saved without the layer, assuming it
+			// already exists here.
+			return nil, fmt.Errorf("layer %s is not included in the archive, and does not exist", id)
+		}
 		return nil, err
 	}
 	defer rawTar.Close()
@@ -359,6 +367,9 @@ func (l *tarexporter) legacyLoadImage(oldID, sourceDir string, loadedMap map[str
 	if err != nil {
 		return err
 	}
+	if err := l.is.SetLastUpdated(imgID); err != nil {
+		return err
+	}
 
 	metadata, err := l.lss[img.OS].Release(newLayer)
 	layer.LogReleaseMetadata(metadata)
@@ -430,3 +441,9 @@ func checkCompatibleOS(imageOS string) error {
 
 	return system.ValidatePlatform(p)
 }
+
+// isNotExist is os.IsNotExist, for functions in which the os package is
+// shadowed by an operating system argument.
+func isNotExist(err error) bool {
+	return os.IsNotExist(err)
+}
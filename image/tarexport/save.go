@@ -35,6 +35,9 @@ type saveSession struct {
 	images      map[image.ID]*imageDescriptor
 	savedLayers map[string]struct{}
 	diffIDPaths map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+	// excludeLayers are layers whose content is left out of the archive,
+	// because the receiver of the archive already has them.
+	excludeLayers map[layer.ChainID]struct{}
 }
 
 func (l *tarexporter) Save(names []string, outStream io.Writer) error {
@@ -48,6 +51,25 @@ func (l *tarexporter) Save(names []string, outStream io.Writer) error {
 	return (&saveSession{tarexporter: l, images: images}).save(outStream)
 }
 
+// SaveExcludingLayers saves the images like Save, except for the content of
+// the layers in excludeLayers. The resulting archive can only be loaded by a
+// daemon which has all excluded layers that are part of the saved images.
+func (l *tarexporter) SaveExcludingLayers(names []string, excludeLayers []layer.ChainID, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	exclude := make(map[layer.ChainID]struct{}, len(excludeLayers))
+	for _, chainID := range excludeLayers {
+		exclude[chainID] = struct{}{}
+	}
+
+	// Release all the image top layer references
+	defer l.releaseLayerReferences(images)
+	return (&saveSession{tarexporter: l, images: images, excludeLayers: exclude}).save(outStream)
+}
+
 // parseNames will parse the image names to a map which contains image.ID to *imageDescriptor.
 // Each imageDescriptor holds an image top layer reference named 'layerRef'. It is taken here, should be released later.
 func (l *tarexporter) parseNames(names []string) (desc map[image.ID]*imageDescriptor, rErr error) {
@@ -372,6 +394,17 @@ func (s *saveSession) saveLayer(id layer.ChainID, legacyImg image.V1Image, creat
 		return distribution.Descriptor{}, err
 	}
 
+	if _, excluded := s.excludeLayers[id]; excluded {
+		// The layer.tar is left out; Load uses the receiver's copy of the layer.
+		for _, fname := range []string{"", legacyVersionFileName, legacyConfigFileName} {
+			if err := system.Chtimes(filepath.Join(outDir, fname), createdTime, createdTime); err != nil {
+				return distribution.Descriptor{}, err
+			}
+		}
+		s.savedLayers[legacyImg.ID] = struct{}{}
+		return distribution.Descriptor{}, nil
+	}
+
 	// serialize filesystem
 	layerPath := filepath.Join(outDir, legacyLayerFileName)
 	operatingSystem := legacyImg.OS
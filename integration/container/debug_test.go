@@ -0,0 +1,86 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestDebugContainer(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "debug containers require API v1.41")
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows", "debug containers are not supported on Windows")
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	apiClient := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, apiClient, container.WithCmd("sh", "-c", "echo target > /marker && exec sleep 300"))
+	target, err := apiClient.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+
+	resp, err := apiClient.ContainerDebug(ctx, cID, types.ContainerDebugConfig{
+		Image: "busybox",
+		Cmd:   []string{"sh", "-c", "hostname && pidof sleep && cat /target/marker"},
+	})
+	assert.NilError(t, err)
+
+	debug, err := apiClient.ContainerInspect(ctx, resp.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(debug.DebugTarget, cID))
+	assert.Check(t, debug.HostConfig.AutoRemove)
+
+	attach, err := apiClient.ContainerAttach(ctx, resp.ID, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	assert.NilError(t, err)
+	defer attach.Close()
+
+	assert.NilError(t, apiClient.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}))
+
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, attach.Reader)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stderr.String(), ""))
+	assert.Check(t, is.Equal(stdout.String(), target.Config.Hostname+"\n1\ntarget\n"))
+
+	// The debug container is removed once it exited.
+	poll.WaitOn(t, container.IsRemoved(ctx, apiClient, resp.ID), poll.WithDelay(100*time.Millisecond))
+}
+
+func TestDebugContainerRemovedWithTarget(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "debug containers require API v1.41")
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows", "debug containers are not supported on Windows")
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	apiClient := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, apiClient)
+
+	resp, err := apiClient.ContainerDebug(ctx, cID, types.ContainerDebugConfig{
+		Image: "busybox",
+		Cmd:   []string{"top"},
+	})
+	assert.NilError(t, err)
+	assert.NilError(t, apiClient.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}))
+
+	assert.NilError(t, apiClient.ContainerStop(ctx, cID, nil))
+	poll.WaitOn(t, container.IsRemoved(ctx, apiClient, resp.ID), poll.WithDelay(100*time.Millisecond))
+
+	_, err = apiClient.ContainerDebug(ctx, cID, types.ContainerDebugConfig{Image: "busybox"})
+	assert.Check(t, is.ErrorContains(err, "is not running"))
+	assert.Check(t, errdefs.IsConflict(err))
+}
@@ -0,0 +1,71 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestStartContainerWithDependencies(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "DependsOn was added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	db := container.Create(ctx, t, client, container.WithName("dependency-db"))
+	migrate := container.Create(ctx, t, client, container.WithName("dependency-migrate"), container.WithCmd("sh", "-c", "exit 1"))
+	app := container.Create(ctx, t, client,
+		container.WithDependsOn("dependency-db", containertypes.DependencyStarted),
+		container.WithDependsOn("dependency-migrate", containertypes.DependencyCompletedSuccessfully),
+	)
+
+	err := client.ContainerStart(ctx, app, types.ContainerStartOptions{})
+	assert.Check(t, errdefs.IsConflict(err), "expected a conflict, got %v", err)
+	assert.Check(t, is.ErrorContains(err, "dependency dependency-db is not running"))
+
+	err = client.ContainerStart(ctx, db, types.ContainerStartOptions{})
+	assert.NilError(t, err)
+
+	err = client.ContainerStart(ctx, migrate, types.ContainerStartOptions{})
+	assert.NilError(t, err)
+	poll.WaitOn(t, container.IsInState(ctx, client, migrate, "exited"), poll.WithDelay(100*time.Millisecond))
+
+	err = client.ContainerStart(ctx, app, types.ContainerStartOptions{})
+	assert.Check(t, errdefs.IsConflict(err), "expected a conflict, got %v", err)
+	assert.Check(t, is.ErrorContains(err, "dependency dependency-migrate did not complete successfully"))
+}
+
+func TestCreateContainerWithDependencyCycle(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "DependsOn was added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	container.CreateExpectingErr(ctx, t, client, "cannot get dependency dependency-missing",
+		container.WithDependsOn("dependency-missing", containertypes.DependencyStarted))
+
+	first := container.Create(ctx, t, client, container.WithName("dependency-first"))
+	container.Create(ctx, t, client, container.WithName("dependency-second"),
+		container.WithDependsOn("dependency-first", containertypes.DependencyStarted))
+
+	// Renaming the dependency lets a new container take its name, and
+	// depend on its dependent.
+	err := client.ContainerRename(ctx, first, "dependency-renamed")
+	assert.NilError(t, err)
+	container.CreateExpectingErr(ctx, t, client, "dependency cycle between containers: dependency-first -> dependency-second -> dependency-first",
+		container.WithName("dependency-first"),
+		container.WithDependsOn("dependency-second", containertypes.DependencyStarted))
+
+	_, err = client.ContainerInspect(ctx, "dependency-first")
+	assert.Check(t, errdefs.IsNotFound(err), "expected the container to be removed, got %v", err)
+}
@@ -0,0 +1,112 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/internal/test/request"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestEgressPolicy(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot inspect the events of a remote daemon")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "egress policies were added in API v1.41")
+	defer setupTest(t)()
+	apiClient := testEnv.APIClient()
+	ctx := context.Background()
+
+	serverID := container.Run(ctx, t, apiClient, container.WithCmd("sh", "-c",
+		"while true; do nc -l -p 8080 </dev/null; done & while true; do nc -l -p 8081 </dev/null; done"),
+		container.WithExposedPorts("8080/tcp"),
+		func(c *container.TestContainerConfig) {
+			c.HostConfig.PortBindings = nat.PortMap{"8080/tcp": []nat.PortBinding{{HostPort: ""}}}
+		})
+	poll.WaitOn(t, container.IsInState(ctx, apiClient, serverID, "running"), poll.WithDelay(100*time.Millisecond))
+	inspect, err := apiClient.ContainerInspect(ctx, serverID)
+	assert.NilError(t, err)
+	serverIP := inspect.NetworkSettings.IPAddress
+	gateway := inspect.NetworkSettings.Gateway
+	assert.Assert(t, is.Len(inspect.NetworkSettings.Ports["8080/tcp"], 1))
+	hostPort := inspect.NetworkSettings.Ports["8080/tcp"][0].HostPort
+
+	cID := container.Run(ctx, t, apiClient, func(c *container.TestContainerConfig) {
+		c.HostConfig.EgressPolicy = &containertypes.EgressPolicy{
+			Allow: []containertypes.EgressRule{{CIDR: serverIP, Ports: []string{"8080"}}},
+		}
+	})
+	poll.WaitOn(t, container.IsInState(ctx, apiClient, cID, "running"), poll.WithDelay(100*time.Millisecond))
+	since := request.DaemonUnixTime(ctx, t, apiClient, testEnv)
+
+	res, err := container.Exec(ctx, apiClient, cID, []string{"nc", "-w", "2", serverIP, "8080"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(res.ExitCode, 0), res.Stderr())
+
+	res, err = container.Exec(ctx, apiClient, cID, []string{"nc", "-w", "2", serverIP, "8081"})
+	assert.NilError(t, err)
+	assert.Check(t, res.ExitCode != 0)
+
+	// The allowed port is not reachable through the host.
+	res, err = container.Exec(ctx, apiClient, cID, []string{"nc", "-w", "2", gateway, hostPort})
+	assert.NilError(t, err)
+	assert.Check(t, res.ExitCode != 0)
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		until := request.DaemonUnixTime(ctx, t, apiClient, testEnv)
+		messages, errs := apiClient.Events(ctx, types.EventsOptions{
+			Since: since,
+			Until: until,
+			Filters: filters.NewArgs(
+				filters.Arg("container", cID),
+				filters.Arg("event", "egress_denied"),
+			),
+		})
+		for _, m := range collectEvents(t, messages, errs) {
+			if m.Actor.Attributes["destination"] == serverIP && m.Actor.Attributes["port"] == "8081" {
+				assert.Check(t, is.Equal(m.Actor.Attributes["protocol"], "tcp"))
+				return poll.Success()
+			}
+		}
+		return poll.Continue("no egress_denied event for %s:8081", serverIP)
+	}, poll.WithDelay(100*time.Millisecond), poll.WithTimeout(10*time.Second))
+}
+
+func collectEvents(t *testing.T, messages <-chan events.Message, errs <-chan error) []events.Message {
+	var msgs []events.Message
+	for {
+		select {
+		case err := <-errs:
+			assert.Check(t, err == nil || err == io.EOF)
+			return msgs
+		case m := <-messages:
+			msgs = append(msgs, m)
+		}
+	}
+}
+
+func TestEgressPolicyHostNetwork(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "egress policies were added in API v1.41")
+	defer setupTest(t)()
+	apiClient := testEnv.APIClient()
+	ctx := context.Background()
+
+	// The egress policy cannot be enforced in the host network namespace.
+	container.CreateExpectingErr(ctx, t, apiClient, "EgressPolicy is not supported in host network mode",
+		container.WithNetworkMode("host"),
+		func(c *container.TestContainerConfig) {
+			c.HostConfig.EgressPolicy = &containertypes.EgressPolicy{
+				Allow: []containertypes.EgressRule{{CIDR: "10.0.0.0/8"}},
+			}
+		})
+}
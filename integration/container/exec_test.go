@@ -1,7 +1,9 @@
 package container // import "github.com/docker/docker/integration/container"
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
 	"testing"
 	"time"
@@ -10,8 +12,10 @@ import (
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
 	"gotest.tools/skip"
 )
 
@@ -132,3 +136,116 @@ func TestExecUser(t *testing.T) {
 
 	assert.Assert(t, is.Contains(result.Stdout(), "uid=1(daemon) gid=1(daemon)"), "exec command not running as uid/gid 1")
 }
+
+func TestExecReattach(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "detachable execs require API v1.41")
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, client)
+
+	execResp, err := client.ContainerExecCreate(ctx, cID,
+		types.ExecConfig{
+			AttachStdin:  true,
+			AttachStdout: true,
+			Detachable:   true,
+			Cmd:          strslice.StrSlice([]string{"sh", "-c", "echo hello && read line && echo got $line"}),
+		},
+	)
+	assert.NilError(t, err)
+
+	// Start the exec without attaching to it; its output is buffered.
+	err = client.ContainerExecStart(ctx, execResp.ID, types.ExecStartCheck{Detach: true})
+	assert.NilError(t, err)
+
+	readOutput := func(options types.ContainerAttachOptions, stdin string) string {
+		resp, err := client.ContainerExecReattach(ctx, execResp.ID, options)
+		assert.NilError(t, err)
+		defer resp.Close()
+		if stdin != "" {
+			_, err = resp.Conn.Write([]byte(stdin))
+			assert.NilError(t, err)
+		}
+		var stdout bytes.Buffer
+		_, err = stdcopy.StdCopy(&stdout, ioutil.Discard, resp.Reader)
+		assert.NilError(t, err)
+		return stdout.String()
+	}
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		out := readOutput(types.ContainerAttachOptions{Stdout: true, Logs: true}, "")
+		if out != "hello\n" {
+			return poll.Continue("waiting for buffered output, got %q", out)
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+
+	out := readOutput(types.ContainerAttachOptions{Stdin: true, Stdout: true, Stream: true}, "world\n")
+	assert.Check(t, is.Equal(out, "got world\n"))
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		inspect, err := client.ContainerExecInspect(ctx, execResp.ID)
+		if err != nil {
+			return poll.Error(err)
+		}
+		if inspect.Running {
+			return poll.Continue("waiting for exec to exit")
+		}
+		if inspect.ExitCode != 0 {
+			return poll.Error(fmt.Errorf("unexpected exit code %d", inspect.ExitCode))
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+
+	// The output of the exited exec can still be read.
+	out = readOutput(types.ContainerAttachOptions{Stdout: true, Logs: true}, "")
+	assert.Check(t, is.Equal(out, "hello\ngot world\n"))
+}
+
+func TestExecReattachCloseStdin(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "detachable execs require API v1.41")
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	cID := container.Run(ctx, t, client)
+
+	execResp, err := client.ContainerExecCreate(ctx, cID,
+		types.ExecConfig{
+			AttachStdin:  true,
+			AttachStdout: true,
+			Detachable:   true,
+			Cmd:          strslice.StrSlice([]string{"cat"}),
+		},
+	)
+	assert.NilError(t, err)
+	err = client.ContainerExecStart(ctx, execResp.ID, types.ExecStartCheck{Detach: true})
+	assert.NilError(t, err)
+
+	// Closing the input of the client closes the stdin of the exec process,
+	// which has no TTY, so that it exits.
+	resp, err := client.ContainerExecReattach(ctx, execResp.ID, types.ContainerAttachOptions{Stdin: true, Stdout: true, Stream: true})
+	assert.NilError(t, err)
+	defer resp.Close()
+	_, err = resp.Conn.Write([]byte("hello\n"))
+	assert.NilError(t, err)
+	assert.NilError(t, resp.CloseWrite())
+	var stdout bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, ioutil.Discard, resp.Reader)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stdout.String(), "hello\n"))
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		inspect, err := client.ContainerExecInspect(ctx, execResp.ID)
+		if err != nil {
+			return poll.Error(err)
+		}
+		if inspect.Running {
+			return poll.Continue("waiting for exec to exit")
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond))
+}
@@ -12,6 +12,7 @@ import (
 	mounttypes "github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/versions"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/integration/internal/container"
 	"github.com/docker/docker/pkg/mount"
@@ -265,3 +266,104 @@ func TestContainerBindMountNonRecursive(t *testing.T) {
 		poll.WaitOn(t, container.IsSuccessful(ctx, client, c), poll.WithDelay(100*time.Millisecond))
 	}
 }
+
+func TestContainerVolumeSubpath(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "VolumeOptions.Subpath requires API v1.41")
+
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	vol, err := client.VolumeCreate(ctx, volumetypes.VolumeCreateBody{})
+	assert.NilError(t, err)
+
+	cID := container.Run(ctx, t, client,
+		container.WithMount(mounttypes.Mount{Type: mounttypes.TypeVolume, Source: vol.Name, Target: "/vol"}),
+		container.WithCmd("sh", "-c", "mkdir -p /vol/sub/dir && echo hello > /vol/sub/dir/file && ln -s ../../.. /vol/sub/escape"),
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	subpathMount := func(subpath string) mounttypes.Mount {
+		return mounttypes.Mount{
+			Type:          mounttypes.TypeVolume,
+			Source:        vol.Name,
+			Target:        "/foo",
+			VolumeOptions: &mounttypes.VolumeOptions{Subpath: subpath},
+		}
+	}
+
+	cID = container.Run(ctx, t, client,
+		container.WithMount(subpathMount("sub/dir")),
+		container.WithCmd("grep", "hello", "/foo/file"),
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	// symlinks are resolved inside the volume
+	cID = container.Run(ctx, t, client,
+		container.WithMount(subpathMount("sub/escape")),
+		container.WithCmd("test", "-f", "/foo/sub/dir/file"),
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	cID = container.Create(ctx, t, client, container.WithMount(subpathMount("missing")))
+	err = client.ContainerStart(ctx, cID, types.ContainerStartOptions{})
+	assert.Check(t, is.ErrorContains(err, "does not exist in volume"))
+
+	_, err = client.ContainerCreate(ctx,
+		&containertypes.Config{Image: "busybox"},
+		&containertypes.HostConfig{Mounts: []mounttypes.Mount{subpathMount("../outside")}},
+		nil, "")
+	assert.Check(t, is.ErrorContains(err, "subpath must not refer to a path outside of the volume"))
+}
+
+func TestContainerImageMount(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "image mounts require API v1.41")
+	skip.If(t, testEnv.IsRemoteDaemon)
+
+	defer setupTest(t)()
+
+	ctx := context.Background()
+	client := testEnv.APIClient()
+
+	img, _, err := client.ImageInspectWithRaw(ctx, "busybox")
+	assert.NilError(t, err)
+
+	imageMount := mounttypes.Mount{Type: mounttypes.TypeImage, Source: "busybox", Target: "/img"}
+	cID := container.Run(ctx, t, client,
+		container.WithMount(imageMount),
+		container.WithCmd("sh", "-c", "test -f /img/bin/busybox && ! touch /img/foo"),
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(inspect.Mounts, 1))
+	assert.Check(t, is.Equal(inspect.Mounts[0].Type, mounttypes.TypeImage))
+	assert.Check(t, is.Equal(inspect.Mounts[0].Name, img.ID))
+	assert.Check(t, !inspect.Mounts[0].RW)
+
+	// the image is mounted again when the container is restarted
+	err = client.ContainerStart(ctx, cID, types.ContainerStartOptions{})
+	assert.NilError(t, err)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	// the image mount is shared with --volumes-from
+	cID2 := container.Run(ctx, t, client,
+		container.WithVolumesFrom(cID),
+		container.WithCmd("test", "-f", "/img/bin/busybox"),
+	)
+	poll.WaitOn(t, container.IsSuccessful(ctx, client, cID2), poll.WithDelay(100*time.Millisecond))
+
+	inspect, err = client.ContainerInspect(ctx, cID2)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(inspect.Mounts, 1))
+	assert.Check(t, is.Equal(inspect.Mounts[0].Type, mounttypes.TypeImage))
+	assert.Check(t, is.Equal(inspect.Mounts[0].Name, img.ID))
+
+	_, err = client.ContainerCreate(ctx,
+		&containertypes.Config{Image: "busybox"},
+		&containertypes.HostConfig{Mounts: []mounttypes.Mount{{Type: mounttypes.TypeImage, Source: "no-such-image", Target: "/img"}}},
+		nil, "")
+	assert.Check(t, is.ErrorContains(err, "No such image"))
+}
@@ -0,0 +1,71 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestNetworkLimits(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot inspect the network stats of a remote daemon")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "network limits were added in API v1.41")
+	defer setupTest(t)()
+	apiClient := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, apiClient, func(c *container.TestContainerConfig) {
+		c.HostConfig.NetworkIngressRate = 1024 * 1024
+		c.HostConfig.NetworkEgressRate = 512 * 1024
+		c.HostConfig.NetworkConntrackMax = 100
+	})
+	poll.WaitOn(t, container.IsInState(ctx, apiClient, cID, "running"), poll.WithDelay(100*time.Millisecond))
+
+	stats := networkStats(ctx, t, apiClient, cID)
+	assert.Check(t, is.Equal(stats.RxRateLimit, uint64(1024*1024)))
+	assert.Check(t, is.Equal(stats.TxRateLimit, uint64(512*1024)))
+	assert.Check(t, is.Equal(stats.ConntrackLimit, uint64(100)))
+
+	_, err := apiClient.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		Resources: containertypes.Resources{
+			NetworkEgressRate:   2 * 1024 * 1024,
+			NetworkConntrackMax: -1,
+		},
+	})
+	assert.NilError(t, err)
+
+	inspect, err := apiClient.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.HostConfig.NetworkIngressRate, int64(1024*1024)))
+	assert.Check(t, is.Equal(inspect.HostConfig.NetworkEgressRate, int64(2*1024*1024)))
+	assert.Check(t, is.Equal(inspect.HostConfig.NetworkConntrackMax, int64(0)))
+
+	stats = networkStats(ctx, t, apiClient, cID)
+	assert.Check(t, is.Equal(stats.RxRateLimit, uint64(1024*1024)))
+	assert.Check(t, is.Equal(stats.TxRateLimit, uint64(2*1024*1024)))
+	assert.Check(t, is.Equal(stats.ConntrackLimit, uint64(0)))
+}
+
+func networkStats(ctx context.Context, t *testing.T, apiClient client.APIClient, cID string) types.NetworkStats {
+	t.Helper()
+	resp, err := apiClient.ContainerStats(ctx, cID, false)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	var v types.StatsJSON
+	err = json.NewDecoder(resp.Body).Decode(&v)
+	assert.NilError(t, err)
+	stats, ok := v.Networks["eth0"]
+	assert.Assert(t, ok, "no stats for eth0: %v", v.Networks)
+	return stats
+}
@@ -76,6 +76,17 @@ func TestCgroupNamespacesRunPrivileged(t *testing.T) {
 	assert.Assert(t, daemonCgroup == containerCgroup)
 }
 
+func TestCgroupNamespacesRunPrivilegedDefault(t *testing.T) {
+	skip.If(t, testEnv.DaemonInfo.OSType != "linux")
+	skip.If(t, testEnv.IsRemoteDaemon())
+	skip.If(t, !requirement.CgroupNamespacesEnabled())
+
+	// Privileged containers launched without a cgroup ns mode should follow
+	// the default of the daemon, like the other containers
+	containerCgroup, daemonCgroup := testRunWithCgroupNs(t, "private", container.WithPrivileged(true))
+	assert.Assert(t, daemonCgroup != containerCgroup)
+}
+
 func TestCgroupNamespacesRunDaemonHostMode(t *testing.T) {
 	skip.If(t, testEnv.DaemonInfo.OSType != "linux")
 	skip.If(t, testEnv.IsRemoteDaemon())
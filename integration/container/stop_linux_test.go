@@ -9,8 +9,13 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/internal/test/request"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/icmd"
 	"gotest.tools/poll"
 	"gotest.tools/skip"
@@ -98,3 +103,45 @@ func TestDeleteDevicemapper(t *testing.T) {
 	err = client.ContainerRemove(ctx, id, types.ContainerRemoveOptions{})
 	assert.NilError(t, err)
 }
+
+// TestStopContainerWithStopSequence checks that the pre-stop hook is run,
+// then the signals of the stop sequence are sent in order.
+func TestStopContainerWithStopSequence(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "stop sequences were added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	id := container.Run(ctx, t, client,
+		container.WithCmd("sh", "-c", `trap "" TERM; trap "[ -f /tmp/drained ] && exit 43; exit 44" INT; while true; do sleep 0.1; done`),
+		func(c *container.TestContainerConfig) {
+			c.Config.PreStop = &containertypes.PreStopHook{Cmd: []string{"touch", "/tmp/drained"}}
+			c.Config.StopSequence = []containertypes.StopSignalStep{
+				{Signal: "SIGTERM", Timeout: 1},
+				{Signal: "SIGINT", Timeout: 10},
+			}
+		})
+	since := request.DaemonUnixTime(ctx, t, client, testEnv)
+
+	err := client.ContainerStop(ctx, id, nil)
+	assert.NilError(t, err)
+
+	inspect, err := client.ContainerInspect(ctx, id)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.State.ExitCode, 43))
+
+	until := request.DaemonUnixTime(ctx, t, client, testEnv)
+	messages, errs := client.Events(ctx, types.EventsOptions{
+		Since:   since,
+		Until:   until,
+		Filters: filters.NewArgs(filters.Arg("container", id)),
+	})
+	var phases []string
+	for _, action := range getEventActions(t, messages, errs) {
+		switch action {
+		case "pre_stop", "stop_signal", "die", "stop":
+			phases = append(phases, action)
+		}
+	}
+	assert.Check(t, is.DeepEqual(phases, []string{"pre_stop", "stop_signal", "stop_signal", "die", "stop"}))
+}
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"gotest.tools/poll"
+	"gotest.tools/skip"
 )
 
 func TestUpdateRestartPolicy(t *testing.T) {
@@ -59,3 +61,30 @@ func TestUpdateRestartWithAutoRemove(t *testing.T) {
 	})
 	assert.Check(t, is.ErrorContains(err, "Restart policy cannot be updated because AutoRemove is enabled for the container"))
 }
+
+func TestUpdateLabelsAndHealthcheck(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "updating labels and healthcheck requires API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, client, func(c *container.TestContainerConfig) {
+		c.Config.Labels = map[string]string{"foo": "bar"}
+	})
+
+	_, err := client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		Labels: map[string]string{"foo": "baz"},
+		Healthcheck: &containertypes.HealthConfig{
+			Test:     []string{"CMD", "true"},
+			Interval: 100 * time.Millisecond,
+		},
+	})
+	assert.NilError(t, err)
+
+	poll.WaitOn(t, pollForHealthStatus(ctx, client, cID, "healthy"), poll.WithDelay(100*time.Millisecond))
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(inspect.Config.Labels, map[string]string{"foo": "baz"}))
+	assert.Check(t, is.DeepEqual(inspect.Config.Healthcheck.Test, []string{"CMD", "true"}))
+}
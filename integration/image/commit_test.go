@@ -1,14 +1,19 @@
 package image // import "github.com/docker/docker/integration/image"
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
 	"gotest.tools/skip"
 )
 
@@ -46,3 +51,52 @@ func TestCommitInheritsEnv(t *testing.T) {
 	expectedEnv2 := []string{"PATH=/usr/bin:/bin"}
 	assert.Check(t, is.DeepEqual(expectedEnv2, image2.Config.Env))
 }
+
+func TestCommitSnapshotIncremental(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "snapshot commits require API v1.41")
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows", "snapshots are not supported on Windows")
+	skip.If(t, testEnv.DaemonInfo.Driver != "overlay2" && testEnv.DaemonInfo.Driver != "vfs", "storage driver does not support snapshots")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, client, container.WithCmd("sh", "-c", "echo first > /first && top"))
+
+	commitResp1, err := client.ContainerCommit(ctx, cID, types.ContainerCommitOptions{
+		Snapshot:    true,
+		Incremental: true,
+	})
+	assert.NilError(t, err)
+
+	res, err := container.Exec(ctx, client, cID, []string{"sh", "-c", "echo second > /second"})
+	assert.NilError(t, err)
+	assert.Equal(t, res.ExitCode, 0)
+
+	commitResp2, err := client.ContainerCommit(ctx, cID, types.ContainerCommitOptions{
+		Snapshot:    true,
+		Incremental: true,
+	})
+	assert.NilError(t, err)
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, inspect.State.Running)
+	assert.Check(t, !inspect.State.Paused)
+
+	image1, _, err := client.ImageInspectWithRaw(ctx, commitResp1.ID)
+	assert.NilError(t, err)
+	image2, _, err := client.ImageInspectWithRaw(ctx, commitResp2.ID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(image2.RootFS.Layers, len(image1.RootFS.Layers)+1))
+	assert.Check(t, is.DeepEqual(image2.RootFS.Layers[:len(image1.RootFS.Layers)], image1.RootFS.Layers))
+
+	cID2 := container.Run(ctx, t, client, container.WithImage(image2.ID), container.WithCmd("cat", "/first", "/second"))
+	poll.WaitOn(t, container.IsStopped(ctx, client, cID2), poll.WithDelay(100*time.Millisecond))
+	out, err := client.ContainerLogs(ctx, cID2, types.ContainerLogsOptions{ShowStdout: true})
+	assert.NilError(t, err)
+	defer out.Close()
+	var stdout bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, ioutil.Discard, out)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stdout.String(), "first\nsecond\n"))
+}
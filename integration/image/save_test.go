@@ -0,0 +1,60 @@
+package image // import "github.com/docker/docker/integration/image"
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestSaveExcludingLayersAndLoad(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "POST /images/get is not available before 1.41")
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	// the layers the "receiving" daemon has
+	layers, err := client.ImageLayers(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, len(layers) > 0)
+
+	cID := container.Create(ctx, t, client, container.WithCmd("touch", "/foo"))
+	commitResp, err := client.ContainerCommit(ctx, cID, types.ContainerCommitOptions{
+		Reference: "test-save-excluding-layers",
+	})
+	assert.NilError(t, err)
+
+	rdr, err := client.ImageSaveExcludingLayers(ctx, []string{"test-save-excluding-layers"}, layers)
+	assert.NilError(t, err)
+	tarball, err := ioutil.ReadAll(rdr)
+	rdr.Close()
+	assert.NilError(t, err)
+
+	err = client.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+	assert.NilError(t, err)
+	_, err = client.ImageRemove(ctx, "test-save-excluding-layers", types.ImageRemoveOptions{})
+	assert.NilError(t, err)
+
+	beforeLoad := time.Now()
+	resp, err := client.ImageLoad(ctx, bytes.NewReader(tarball), true)
+	assert.NilError(t, err)
+	_, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+
+	inspect, _, err := client.ImageInspectWithRaw(ctx, "test-save-excluding-layers")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.ID, commitResp.ID))
+	// The image is not considered unused by the image garbage collection
+	// since it was created.
+	assert.Check(t, !inspect.Metadata.LastTagTime.Before(beforeLoad.Add(-time.Second)), "LastTagTime %s", inspect.Metadata.LastTagTime)
+}
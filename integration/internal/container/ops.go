@@ -86,6 +86,13 @@ func WithVolume(name string) func(*TestContainerConfig) {
 	}
 }
 
+// WithVolumesFrom mounts the volumes of the given container
+func WithVolumesFrom(containerID string) func(*TestContainerConfig) {
+	return func(c *TestContainerConfig) {
+		c.HostConfig.VolumesFrom = append(c.HostConfig.VolumesFrom, containerID)
+	}
+}
+
 // WithBind sets the bind mount of the container
 func WithBind(src, target string) func(*TestContainerConfig) {
 	return func(c *TestContainerConfig) {
@@ -180,3 +187,13 @@ func WithCgroupnsMode(mode string) func(*TestContainerConfig) {
 		c.HostConfig.CgroupnsMode = containertypes.CgroupnsMode(mode)
 	}
 }
+
+// WithDependsOn adds a dependency of the container on another container
+func WithDependsOn(name string, condition containertypes.DependencyCondition) func(*TestContainerConfig) {
+	return func(c *TestContainerConfig) {
+		if c.HostConfig == nil {
+			c.HostConfig = &containertypes.HostConfig{}
+		}
+		c.HostConfig.DependsOn = append(c.HostConfig.DependsOn, containertypes.Dependency{Container: name, Condition: condition})
+	}
+}
@@ -0,0 +1,90 @@
+package system // import "github.com/docker/docker/integration/system"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/audit"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/integration/internal/container"
+	"github.com/docker/docker/internal/test/daemon"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestAuditLogExec(t *testing.T) {
+	skip.If(t, testEnv.IsRemoteDaemon, "cannot start daemon on remote test run")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "audit log was added in API v1.41")
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	d := daemon.New(t)
+	d.StartWithBusybox(t, "--iptables=false", "--audit-log", filepath.Join(dir, "audit.log"))
+	defer d.Stop(t)
+	c := d.NewClientT(t)
+
+	ctx := context.Background()
+	cID := container.Run(ctx, t, c)
+	defer c.ContainerRemove(ctx, cID, types.ContainerRemoveOptions{Force: true})
+
+	res, err := container.Exec(ctx, c, cID, []string{"sh", "-c", "exit 3"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(res.ExitCode, 3))
+
+	var entries []audit.Entry
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		entries, err = c.AuditLog(ctx, types.AuditLogOptions{
+			Filters: filters.NewArgs(filters.Arg("container", cID), filters.Arg("action", audit.ExecDieAction)),
+		})
+		if err != nil {
+			return poll.Error(err)
+		}
+		if len(entries) == 0 {
+			return poll.Continue("waiting for the exit of the exec process in the audit log")
+		}
+		return poll.Success()
+	}, poll.WithDelay(100*time.Millisecond), poll.WithTimeout(10*time.Second))
+
+	assert.Assert(t, is.Len(entries, 1))
+	e := entries[0]
+	assert.Check(t, is.DeepEqual(e.Command, []string{"sh", "-c", "exit 3"}))
+	assert.Assert(t, e.ExitCode != nil)
+	assert.Check(t, is.Equal(*e.ExitCode, 3))
+	assert.Assert(t, e.Caller.UID != nil)
+	assert.Check(t, is.Equal(*e.Caller.UID, os.Getuid()))
+
+	// The request starting the exec process is recorded too.
+	entries, err = c.AuditLog(ctx, types.AuditLogOptions{
+		Filters: filters.NewArgs(filters.Arg("exec", e.ExecID), filters.Arg("action", audit.RequestAction)),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Container, cID))
+
+	// The start of the exec session is recorded before the request is handled.
+	entries, err = c.AuditLog(ctx, types.AuditLogOptions{
+		Filters: filters.NewArgs(filters.Arg("exec", e.ExecID), filters.Arg("action", audit.StartedAction)),
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 1))
+
+	// The request creating the container is recorded with its ID.
+	entries, err = c.AuditLog(ctx, types.AuditLogOptions{
+		Filters: filters.NewArgs(filters.Arg("container", cID), filters.Arg("action", audit.RequestAction)),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, len(entries) > 0)
+	assert.Check(t, strings.HasSuffix(entries[0].Endpoint, "/containers/create"))
+}
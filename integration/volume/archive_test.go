@@ -0,0 +1,73 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestVolumeArchive(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "volume archives were added in API v1.41")
+	skip.If(t, testEnv.OSType == "windows", "FIXME")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []struct{ name, content string }{
+		{"foo", "hello"},
+		{"dir/bar", "world"},
+	} {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{
+			Name: f.name,
+			Mode: 0644,
+			Size: int64(len(f.content)),
+		}))
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	// the volume does not exist yet, and is created
+	name := "test-volume-archive"
+	err := client.CopyToVolume(ctx, name, "/", &buf, types.CopyToVolumeOptions{})
+	assert.NilError(t, err)
+
+	vol, err := client.VolumeInspect(ctx, name)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(vol.Driver, "local"))
+
+	rdr, err := client.CopyFromVolume(ctx, name, "/dir")
+	assert.NilError(t, err)
+	defer rdr.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(rdr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = string(content)
+	}
+	assert.Check(t, is.DeepEqual(files, map[string]string{"dir/bar": "world"}))
+
+	_, err = client.CopyFromVolume(ctx, name, "/missing")
+	assert.Check(t, is.ErrorContains(err, "no such file or directory"))
+}
@@ -44,6 +44,12 @@ func newFSMetadataStore(root string) (*fileMetadataStore, error) {
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, err
 	}
+	// Nothing in the temporary directory survives a restart: remove the
+	// leftovers of transactions and staged layers which were interrupted
+	// when the daemon stopped.
+	if err := os.RemoveAll(filepath.Join(root, "tmp")); err != nil {
+		logrus.WithError(err).Warn("failed to clean up temporary layer metadata")
+	}
 	return &fileMetadataStore{
 		root: root,
 	}, nil
@@ -288,6 +294,24 @@ func (fms *fileMetadataStore) GetInitID(mount string) (string, error) {
 	return content, nil
 }
 
+func (fms *fileMetadataStore) SetMountSnapshots(mount string, snapshots []string) error {
+	if err := os.MkdirAll(fms.getMountDirectory(mount), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fms.getMountFilename(mount, "snapshots"), []byte(strings.Join(snapshots, "\n")), 0644)
+}
+
+func (fms *fileMetadataStore) GetMountSnapshots(mount string) ([]string, error) {
+	content, err := ioutil.ReadFile(fms.getMountFilename(mount, "snapshots"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Fields(string(content)), nil
+}
+
 func (fms *fileMetadataStore) GetMountParent(mount string) (ChainID, error) {
 	content, err := ioutil.ReadFile(fms.getMountFilename(mount, "parent"))
 	if err != nil {
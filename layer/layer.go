@@ -55,6 +55,14 @@ var (
 	// ErrNotSupported is used when the action is not supported
 	// on the current host operating system.
 	ErrNotSupported = errors.New("not support on this host operating system")
+
+	// ErrSnapshotNotSupported is used when a snapshot of a mount
+	// is attempted but the graph driver cannot take snapshots.
+	ErrSnapshotNotSupported = errors.New("graph driver does not support snapshots")
+
+	// ErrSnapshotDoesNotExist is used when an operation is
+	// attempted on a snapshot which does not exist.
+	ErrSnapshotDoesNotExist = errors.New("snapshot does not exist")
 )
 
 // ChainID is the content-addressable ID of a layer.
@@ -148,6 +156,20 @@ type RWLayer interface {
 
 	// ApplyDiff applies the diff to the RW layer
 	ApplyDiff(diff io.Reader) (int64, error)
+
+	// Snapshot takes a point-in-time snapshot of the RW layer,
+	// without freezing the processes using it, and returns its
+	// ID. The snapshot is kept until it is removed, or the RW
+	// layer is released.
+	Snapshot() (string, error)
+
+	// SnapshotTarStream returns a tar archive of the changes of
+	// the snapshot from the base layer or, if since is not empty,
+	// from the snapshot since.
+	SnapshotTarStream(id, since string) (io.ReadCloser, error)
+
+	// RemoveSnapshot removes the snapshot.
+	RemoveSnapshot(id string) error
 }
 
 // Metadata holds information about a
@@ -204,6 +226,43 @@ type DescribableStore interface {
 	RegisterWithDescriptor(io.Reader, ChainID, distribution.Descriptor) (Layer, error)
 }
 
+// StagingStore represents a layer store capable of preparing layers before
+// their parent is known. Layers can be staged concurrently, while committing
+// them on top of their parent must happen in order.
+type StagingStore interface {
+	// CanStage returns whether layers can be staged. If not, layers must
+	// be registered once their parent is.
+	CanStage() bool
+	// Stage prepares the layer described by the tar stream.
+	Stage(io.Reader, distribution.Descriptor) (StagedLayer, error)
+	// Commit registers a staged layer on top of the given parent. The staged
+	// layer can not be used anymore after Commit returns, even on error.
+	Commit(StagedLayer, ChainID) (Layer, error)
+}
+
+// StagedLayer represents a layer which was prepared by a StagingStore but
+// not yet committed.
+type StagedLayer interface {
+	// DiffID returns the content hash of the staged layer.
+	DiffID() DiffID
+	// Discard releases the resources held by a staged layer which will
+	// not be committed.
+	Discard() error
+}
+
+// ReadOnlyMountStore represents a layer store capable of mounting layer
+// chains read-only, without creating a writable layer for a container.
+type ReadOnlyMountStore interface {
+	// MountReadOnly mounts the layer chain and returns the path of its root
+	// filesystem, which must not be written to. A layer chain may be
+	// mounted multiple times; the mount is shared, and the layer chain is
+	// retained until UnmountReadOnly was called for every mount.
+	MountReadOnly(ChainID) (containerfs.ContainerFS, error)
+	// UnmountReadOnly releases a mount of the layer chain made with
+	// MountReadOnly.
+	UnmountReadOnly(ChainID) error
+}
+
 // CreateChainID returns ID for a layerDigest slice
 func CreateChainID(dgsts []DiffID) ChainID {
 	return createChainIDFromParent("", dgsts...)
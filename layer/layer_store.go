@@ -40,6 +40,9 @@ type layerStore struct {
 	mounts map[string]*mountedLayer
 	mountL sync.Mutex
 
+	roMounts map[ChainID]*roMount
+	roMountL sync.Mutex
+
 	// protect *RWLayer() methods from operating on the same name/id
 	locker *locker.Locker
 
@@ -99,6 +102,7 @@ func newStoreFromGraphDriver(root string, driver graphdriver.Driver, os string)
 		driver:      driver,
 		layerMap:    map[ChainID]*roLayer{},
 		mounts:      map[string]*mountedLayer{},
+		roMounts:    map[ChainID]*roMount{},
 		locker:      locker.New(),
 		useTarSplit: !caps.ReproducesExactDiffs,
 		os:          os,
@@ -218,12 +222,18 @@ func (ls *layerStore) loadMount(mount string) error {
 		return err
 	}
 
+	snapshots, err := ls.store.GetMountSnapshots(mount)
+	if err != nil {
+		return err
+	}
+
 	ml := &mountedLayer{
 		name:       mount,
 		mountID:    mountID,
 		initID:     initID,
 		layerStore: ls,
 		references: map[RWLayer]*referencedRWLayer{},
+		snapshots:  snapshots,
 	}
 
 	if parent != "" {
@@ -430,6 +440,9 @@ func (ls *layerStore) deleteLayer(layer *roLayer, metadata *Metadata) error {
 		}
 		break
 	}
+	if err := ls.removeROMountLayer(layer); err != nil {
+		return err
+	}
 	err := ls.driver.Remove(layer.cacheID)
 	if err != nil {
 		return err
@@ -621,6 +634,12 @@ func (ls *layerStore) ReleaseRWLayer(l RWLayer) ([]Metadata, error) {
 		return []Metadata{}, nil
 	}
 
+	if err := m.removeSnapshots(); err != nil {
+		logrus.Errorf("Error removing snapshots of mounted layer %s: %s", m.name, err)
+		m.retakeReference(l)
+		return nil, err
+	}
+
 	if err := ls.driver.Remove(m.mountID); err != nil {
 		logrus.Errorf("Error removing mounted layer %s: %s", m.name, err)
 		m.retakeReference(l)
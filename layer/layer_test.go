@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/containerd/continuity/driver"
+	"github.com/docker/distribution"
 	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/daemon/graphdriver/vfs"
 	"github.com/docker/docker/pkg/archive"
@@ -695,6 +696,44 @@ func TestRegisterExistingLayer(t *testing.T) {
 	assertReferences(t, layer2a, layer2b)
 }
 
+func TestStageNotSupported(t *testing.T) {
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	// The vfs driver can not attach a layer to its parent afterwards.
+	ss := ls.(StagingStore)
+	if ss.CanStage() {
+		t.Fatal("Expected staging not to be supported")
+	}
+	tar1, err := tarFromFiles(newTestFile("/etc/profile", []byte("# Base configuration"), 0644))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.Stage(bytes.NewReader(tar1), distribution.Descriptor{}); err == nil {
+		t.Fatal("Expected error staging a layer")
+	}
+}
+
+func TestStoreRemovesTemporaryFiles(t *testing.T) {
+	ls, td, cleanup := newTestStore(t)
+	defer cleanup()
+
+	stale := filepath.Join(td, "tmp", "staged-123")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(stale, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newStoreFromGraphDriver(td, ls.(*layerStore).driver, runtime.GOOS); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("Expected stale temporary file to be removed: %v", err)
+	}
+}
+
 func TestTarStreamVerification(t *testing.T) {
 	// TODO Windows: Figure out why this is failing
 	if runtime.GOOS == "windows" {
@@ -264,6 +264,73 @@ func TestMountApply(t *testing.T) {
 	}
 }
 
+func TestMountReadOnly(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	ros, ok := ls.(ReadOnlyMountStore)
+	if !ok {
+		t.Skip("layer store does not support read-only mounts")
+	}
+
+	basefile := newTestFile("testfile.txt", []byte("base data!"), 0644)
+	layer, err := createLayer(ls, "", initWithFiles(basefile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cacheID := getCachedLayer(layer).cacheID
+
+	pathFS, err := ros.MountReadOnly(layer.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(pathFS.Join(pathFS.Path(), "testfile.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "base data!"; string(b) != expected {
+		t.Fatalf("Unexpected test file contents %q, expected %q", string(b), expected)
+	}
+
+	// mounts are shared
+	pathFS2, err := ros.MountReadOnly(layer.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pathFS2.Path() != pathFS.Path() {
+		t.Fatalf("Unexpected path %s, expected %s", pathFS2.Path(), pathFS.Path())
+	}
+
+	// the layer is retained while it is mounted
+	if _, err := ls.Release(layer); err != nil {
+		t.Fatal(err)
+	}
+	retained, err := ls.Get(layer.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.Release(retained); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ros.UnmountReadOnly(layer.ChainID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ls.Get(layer.ChainID()); err != ErrLayerDoesNotExist {
+		t.Fatalf("Unexpected error %v, expected %v", err, ErrLayerDoesNotExist)
+	}
+	if ls.(*layerStore).driver.Exists(cacheID + roMountSuffix) {
+		t.Fatal("read-only mount layer was not removed with the layer")
+	}
+}
+
 func assertChange(t *testing.T, actual, expected archive.Change) {
 	if actual.Path != expected.Path {
 		t.Fatalf("Unexpected change path %s, expected %s", actual.Path, expected.Path)
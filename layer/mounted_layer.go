@@ -18,6 +18,7 @@ type mountedLayer struct {
 
 	sync.Mutex
 	references map[RWLayer]*referencedRWLayer
+	snapshots  []string
 }
 
 func (ml *mountedLayer) cacheParent() string {
@@ -0,0 +1,91 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/pkg/containerfs"
+	"github.com/sirupsen/logrus"
+)
+
+// roMountSuffix is appended to the cache ID of the top layer of a layer chain
+// to name the graph driver layer used to mount the chain read-only.
+const roMountSuffix = "-ro"
+
+// roMount is a read-only mount of a layer chain, shared by all its users.
+type roMount struct {
+	layer      Layer
+	id         string
+	path       containerfs.ContainerFS
+	mountCount int
+}
+
+// MountReadOnly mounts the layer chain read-only. The graph driver layer used
+// for the mount is kept when the layer chain is unmounted, so that mounting it
+// again is cheap, and removed with the layer chain.
+func (ls *layerStore) MountReadOnly(chainID ChainID) (containerfs.ContainerFS, error) {
+	ls.roMountL.Lock()
+	defer ls.roMountL.Unlock()
+
+	if m, ok := ls.roMounts[chainID]; ok {
+		m.mountCount++
+		return m.path, nil
+	}
+
+	l, err := ls.Get(chainID)
+	if err != nil {
+		return nil, err
+	}
+	cacheID := l.(*referencedCacheLayer).cacheID
+	id := cacheID + roMountSuffix
+
+	if !ls.driver.Exists(id) {
+		if err := ls.driver.Create(id, cacheID, nil); err != nil {
+			ls.Release(l)
+			return nil, fmt.Errorf("error creating read-only mount of layer %s: %v", chainID, err)
+		}
+	}
+	path, err := ls.driver.Get(id, "")
+	if err != nil {
+		ls.Release(l)
+		return nil, fmt.Errorf("error mounting layer %s read-only: %v", chainID, err)
+	}
+
+	ls.roMounts[chainID] = &roMount{layer: l, id: id, path: path, mountCount: 1}
+	return path, nil
+}
+
+// UnmountReadOnly releases a read-only mount of the layer chain. The layer
+// chain is unmounted, and released, once it is not used anymore.
+func (ls *layerStore) UnmountReadOnly(chainID ChainID) error {
+	ls.roMountL.Lock()
+	defer ls.roMountL.Unlock()
+
+	m, ok := ls.roMounts[chainID]
+	if !ok {
+		// The mount may have been made before the daemon was restarted.
+		logrus.WithField("chainID", chainID).Debug("layer is not mounted read-only")
+		return nil
+	}
+	m.mountCount--
+	if m.mountCount > 0 {
+		return nil
+	}
+
+	delete(ls.roMounts, chainID)
+	if err := ls.driver.Put(m.id); err != nil {
+		logrus.WithError(err).WithField("chainID", chainID).Error("Error unmounting read-only mount of layer")
+	}
+	_, err := ls.Release(m.layer)
+	return err
+}
+
+// removeROMountLayer removes the graph driver layer used to mount the layer
+// read-only, if there is one. It must be called before the layer is removed
+// from the graph driver.
+func (ls *layerStore) removeROMountLayer(layer *roLayer) error {
+	id := layer.cacheID + roMountSuffix
+	if !ls.driver.Exists(id) {
+		return nil
+	}
+	return ls.driver.Remove(id)
+}
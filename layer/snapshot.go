@@ -0,0 +1,107 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/sirupsen/logrus"
+)
+
+// Snapshot takes a snapshot of the mounted layer with the graph driver. The
+// snapshot is a graph driver layer with the same parent as the mounted layer,
+// and its ID is recorded in the mount metadata, so that it is removed with the
+// mounted layer.
+func (ml *mountedLayer) Snapshot() (string, error) {
+	driver, ok := ml.layerStore.driver.(graphdriver.SnapshotDriver)
+	if !ok {
+		return "", ErrSnapshotNotSupported
+	}
+
+	id := fmt.Sprintf("%s-snapshot-%s", ml.mountID, stringid.TruncateID(stringid.GenerateRandomID()))
+	if err := driver.Snapshot(id, ml.mountID, ml.cacheParent()); err != nil {
+		if err == graphdriver.ErrNotSupported {
+			return "", ErrSnapshotNotSupported
+		}
+		return "", fmt.Errorf("error taking snapshot of mounted layer %s: %v", ml.name, err)
+	}
+
+	ml.Lock()
+	defer ml.Unlock()
+	snapshots := append(ml.snapshots[:len(ml.snapshots):len(ml.snapshots)], id)
+	if err := ml.layerStore.store.SetMountSnapshots(ml.name, snapshots); err != nil {
+		if err := ml.layerStore.driver.Remove(id); err != nil {
+			logrus.WithError(err).WithField("snapshot", id).Error("Error removing snapshot")
+		}
+		return "", err
+	}
+	ml.snapshots = snapshots
+	return id, nil
+}
+
+// SnapshotTarStream returns a tar archive of the changes of the snapshot from
+// the parent of the mounted layer, or from the snapshot since.
+func (ml *mountedLayer) SnapshotTarStream(id, since string) (io.ReadCloser, error) {
+	parent := ml.cacheParent()
+	if since != "" {
+		if !ml.hasSnapshot(since) {
+			return nil, ErrSnapshotDoesNotExist
+		}
+		parent = since
+	}
+	if !ml.hasSnapshot(id) {
+		return nil, ErrSnapshotDoesNotExist
+	}
+	return ml.layerStore.driver.Diff(id, parent)
+}
+
+// RemoveSnapshot removes a snapshot of the mounted layer.
+func (ml *mountedLayer) RemoveSnapshot(id string) error {
+	ml.Lock()
+	defer ml.Unlock()
+
+	var snapshots []string
+	for _, s := range ml.snapshots {
+		if s != id {
+			snapshots = append(snapshots, s)
+		}
+	}
+	if len(snapshots) == len(ml.snapshots) {
+		return ErrSnapshotDoesNotExist
+	}
+	if err := ml.layerStore.driver.Remove(id); err != nil {
+		return err
+	}
+	ml.snapshots = snapshots
+	return ml.layerStore.store.SetMountSnapshots(ml.name, snapshots)
+}
+
+func (ml *mountedLayer) hasSnapshot(id string) bool {
+	ml.Lock()
+	defer ml.Unlock()
+	for _, s := range ml.snapshots {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeSnapshots removes all the snapshots of the mounted layer. It must be
+// called before the mounted layer is removed from the graph driver, as the
+// snapshots may depend on it.
+func (ml *mountedLayer) removeSnapshots() error {
+	ml.Lock()
+	defer ml.Unlock()
+	for len(ml.snapshots) > 0 {
+		if err := ml.layerStore.driver.Remove(ml.snapshots[0]); err != nil {
+			if err := ml.layerStore.store.SetMountSnapshots(ml.name, ml.snapshots); err != nil {
+				logrus.WithError(err).WithField("mount", ml.name).Error("Error saving snapshots of mounted layer")
+			}
+			return err
+		}
+		ml.snapshots = ml.snapshots[1:]
+	}
+	return nil
+}
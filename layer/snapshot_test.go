@@ -0,0 +1,121 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/containerd/continuity/driver"
+)
+
+func snapshotFiles(t *testing.T, m RWLayer, id, since string) []string {
+	t.Helper()
+	ts, err := m.SnapshotTarStream(id, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	var files []string
+	tr := tar.NewReader(ts)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, hdr.Name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestMountSnapshot(t *testing.T) {
+	// TODO Windows: the windowsfilter driver does not support snapshots
+	if runtime.GOOS == "windows" {
+		t.Skip("Not supported on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	li := initWithFiles(newTestFile("base", []byte("base data!"), 0644))
+	layer, err := createLayer(ls, "", li)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ls.CreateRWLayer("snapshot-mount", layer.ChainID(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathFS, err := m.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := driver.WriteFile(pathFS, pathFS.Join(pathFS.Path(), "file1"), []byte("first"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s1, err := m.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := driver.WriteFile(pathFS, pathFS.Join(pathFS.Path(), "file2"), []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := pathFS.Remove(pathFS.Join(pathFS.Path(), "file1")); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := m.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if files, expected := snapshotFiles(t, m, s1, ""), []string{"file1"}; !equalStrings(files, expected) {
+		t.Fatalf("Unexpected files in snapshot %v, expected %v", files, expected)
+	}
+	if files, expected := snapshotFiles(t, m, s2, s1), []string{".wh.file1", "file2"}; !equalStrings(files, expected) {
+		t.Fatalf("Unexpected files in incremental snapshot %v, expected %v", files, expected)
+	}
+
+	if err := m.RemoveSnapshot(s1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.SnapshotTarStream(s2, s1); err != ErrSnapshotDoesNotExist {
+		t.Fatalf("Unexpected error for removed snapshot: %v", err)
+	}
+
+	// The remaining snapshot is removed with the mount.
+	snapshotPath, err := ls.(*layerStore).driver.Get(s2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ls.(*layerStore).driver.Put(s2)
+	if err := m.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.ReleaseRWLayer(m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(snapshotPath.Path()); !os.IsNotExist(err) {
+		t.Fatalf("Snapshot was not removed with the mount: %v", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
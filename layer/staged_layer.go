@@ -0,0 +1,160 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/docker/distribution"
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/sirupsen/logrus"
+)
+
+// stagedLayer is a layer which was prepared by the layer store, but is not
+// attached to its parent yet. Its diff is applied to a driver layer created
+// without a parent, which is attached to the parent when the layer is
+// committed.
+type stagedLayer struct {
+	layerStore *layerStore
+	layer      *roLayer
+	tx         *fileMetadataTransaction
+}
+
+func (sl *stagedLayer) DiffID() DiffID {
+	return sl.layer.diffID
+}
+
+func (sl *stagedLayer) Discard() error {
+	if err := sl.tx.Cancel(); err != nil {
+		logrus.Errorf("Error canceling metadata transaction %q: %s", sl.tx.String(), err)
+	}
+	return sl.layerStore.driver.Remove(sl.layer.cacheID)
+}
+
+// CanStage returns whether the graph driver can attach a layer to its parent
+// after its diff was applied, which is required to stage layers.
+func (ls *layerStore) CanStage() bool {
+	_, ok := ls.driver.(graphdriver.ParentSetterDriver)
+	return ok
+}
+
+func (ls *layerStore) Stage(ts io.Reader, descriptor distribution.Descriptor) (StagedLayer, error) {
+	if !ls.CanStage() {
+		return nil, fmt.Errorf("graph driver %s does not support staging layers", ls.driver)
+	}
+
+	layer := &roLayer{
+		cacheID:        stringid.GenerateRandomID(),
+		referenceCount: 1,
+		layerStore:     ls,
+		references:     map[Layer]struct{}{},
+		descriptor:     descriptor,
+	}
+
+	// err is used to hold the error which will always trigger
+	// cleanup of creates sources.
+	var err error
+	if err = ls.driver.Create(layer.cacheID, "", nil); err != nil {
+		return nil, err
+	}
+
+	tx, err := ls.store.StartTransaction()
+	if err != nil {
+		if err := ls.driver.Remove(layer.cacheID); err != nil {
+			logrus.Errorf("Error cleaning up cache layer %s: %v", layer.cacheID, err)
+		}
+		return nil, err
+	}
+
+	sl := &stagedLayer{
+		layerStore: ls,
+		layer:      layer,
+		tx:         tx,
+	}
+	defer func() {
+		if err != nil {
+			logrus.Debugf("Cleaning up staged layer %s: %v", layer.cacheID, err)
+			if err := sl.Discard(); err != nil {
+				logrus.Errorf("Error cleaning up cache layer %s: %v", layer.cacheID, err)
+			}
+		}
+	}()
+
+	if err = ls.applyTar(tx, ts, "", layer); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+func (ls *layerStore) Commit(staged StagedLayer, parent ChainID) (Layer, error) {
+	sl, ok := staged.(*stagedLayer)
+	if !ok || sl.layerStore != ls {
+		return nil, errors.New("staged layer does not belong to this layer store")
+	}
+
+	// err is used to hold the error which will always trigger
+	// cleanup of creates sources but may not be an error returned
+	// to the caller (already exists).
+	var err error
+	defer func() {
+		if err != nil {
+			logrus.Debugf("Cleaning up staged layer %s: %v", sl.layer.cacheID, err)
+			if err := sl.Discard(); err != nil {
+				logrus.Errorf("Error cleaning up cache layer %s: %v", sl.layer.cacheID, err)
+			}
+		}
+	}()
+
+	layer := sl.layer
+	if string(parent) != "" {
+		p := ls.get(parent)
+		if p == nil {
+			err = ErrLayerDoesNotExist
+			return nil, err
+		}
+		// Release parent chain if error
+		defer func() {
+			if err != nil {
+				ls.layerL.Lock()
+				ls.releaseLayer(p)
+				ls.layerL.Unlock()
+			}
+		}()
+		if p.depth() >= maxLayerDepth {
+			err = ErrMaxDepthExceeded
+			return nil, err
+		}
+		if err = ls.driver.(graphdriver.ParentSetterDriver).SetParent(layer.cacheID, p.cacheID); err != nil {
+			return nil, err
+		}
+		layer.parent = p
+	}
+
+	if layer.parent == nil {
+		layer.chainID = ChainID(layer.diffID)
+	} else {
+		layer.chainID = createChainIDFromParent(layer.parent.chainID, layer.diffID)
+	}
+
+	if err = storeLayer(sl.tx, layer); err != nil {
+		return nil, err
+	}
+
+	ls.layerL.Lock()
+	defer ls.layerL.Unlock()
+
+	if existingLayer := ls.getWithoutLock(layer.chainID); existingLayer != nil {
+		// Set error for cleanup, but do not return the error
+		err = errors.New("layer already exists")
+		return existingLayer.getReference(), nil
+	}
+
+	if err = sl.tx.Commit(layer.chainID); err != nil {
+		return nil, err
+	}
+
+	ls.layerMap[layer.chainID] = layer
+
+	return layer.getReference(), nil
+}
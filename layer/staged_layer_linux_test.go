@@ -0,0 +1,110 @@
+package layer // import "github.com/docker/docker/layer"
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/docker/daemon/graphdriver"
+	_ "github.com/docker/docker/daemon/graphdriver/overlay2"
+	"github.com/docker/docker/pkg/reexec"
+)
+
+func init() {
+	// Applying diffs with overlay2 re-executes the test binary
+	reexec.Init()
+}
+
+// newOverlayTestStore returns a layer store backed by the overlay2 graph
+// driver, which supports staging layers.
+func newOverlayTestStore(t *testing.T) (Store, func()) {
+	td, err := ioutil.TempDir("", "layerstore-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphRoot := filepath.Join(td, "graph")
+	if err := os.Mkdir(graphRoot, 0700); err != nil {
+		t.Fatal(err)
+	}
+	driver, err := graphdriver.GetDriver("overlay2", nil, graphdriver.Options{Root: graphRoot})
+	if err != nil {
+		os.RemoveAll(td)
+		t.Skipf("overlay2 graph driver not supported: %v", err)
+	}
+	ls, err := newStoreFromGraphDriver(filepath.Join(td, "layerdb"), driver, runtime.GOOS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ls, func() {
+		driver.Cleanup()
+		os.RemoveAll(td)
+	}
+}
+
+func TestStageAndCommit(t *testing.T) {
+	ls, cleanup := newOverlayTestStore(t)
+	defer cleanup()
+
+	ss := ls.(StagingStore)
+	if !ss.CanStage() {
+		t.Fatal("Expected staging to be supported")
+	}
+
+	tar1, err := tarFromFiles(newTestFile("/etc/profile", []byte("# Base configuration"), 0644))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tar2, err := tarFromFiles(newTestFile("/root/.bashrc", []byte("# Root configuration"), 0644))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stage the child before the parent is committed.
+	staged2, err := ss.Stage(bytes.NewReader(tar2), distribution.Descriptor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	staged1, err := ss.Stage(bytes.NewReader(tar1), distribution.Descriptor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer1, err := ss.Commit(staged1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer2, err := ss.Commit(staged2, layer1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if layer2.DiffID() != staged2.DiffID() {
+		t.Fatalf("Unexpected diff ID %s, expected %s", layer2.DiffID(), staged2.DiffID())
+	}
+	expected := CreateChainID([]DiffID{layer1.DiffID(), layer2.DiffID()})
+	if layer2.ChainID() != expected {
+		t.Fatalf("Unexpected chain ID %s, expected %s", layer2.ChainID(), expected)
+	}
+
+	registered, err := ls.Register(bytes.NewReader(tar2), layer1.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReferences(t, layer2, registered)
+
+	// Discarded layers do not leave anything behind.
+	staged3, err := ss.Stage(bytes.NewReader(tar1), distribution.Descriptor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := staged3.Discard(); err != nil {
+		t.Fatal(err)
+	}
+	if ls.(*layerStore).driver.Exists(staged3.(*stagedLayer).layer.cacheID) {
+		t.Fatal("Expected discarded layer to be removed from the graph driver")
+	}
+}
@@ -0,0 +1,187 @@
+package sysinfo // import "github.com/docker/docker/pkg/sysinfo"
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// Cgroup2Root is the mount point of the cgroup v2 unified hierarchy.
+const Cgroup2Root = "/sys/fs/cgroup"
+
+var (
+	isUnifiedOnce sync.Once
+	isUnified     bool
+)
+
+// IsCgroup2UnifiedMode returns whether the host uses the cgroup v2 unified
+// hierarchy, that is whether the cgroup2 filesystem is mounted on
+// /sys/fs/cgroup. Hosts using the hybrid hierarchy, where cgroup v2 is
+// mounted next to the cgroup v1 controllers, are not in unified mode.
+func IsCgroup2UnifiedMode() bool {
+	isUnifiedOnce.Do(func() {
+		var st unix.Statfs_t
+		if err := unix.Statfs(Cgroup2Root, &st); err != nil {
+			return
+		}
+		isUnified = st.Type == unix.CGROUP2_SUPER_MAGIC
+	})
+	return isUnified
+}
+
+// findCgroup2Controllers returns the controllers available to the cgroup of
+// the current process, mapped to the path of that cgroup. The cgroup is read
+// from procCgroupFile. The controllers of the root cgroup are used if the
+// current process is in the root cgroup, or its cgroup cannot be read.
+func findCgroup2Controllers(root, procCgroupFile string) (map[string]string, error) {
+	dir := root
+	if group, err := Cgroup2Group(procCgroupFile); err == nil {
+		dir = path.Join(root, group)
+	}
+	content, err := ioutil.ReadFile(path.Join(dir, "cgroup.controllers"))
+	if err != nil && dir != root {
+		dir = root
+		content, err = ioutil.ReadFile(path.Join(dir, "cgroup.controllers"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read available cgroup controllers: %v", err)
+	}
+	controllers := make(map[string]string)
+	for _, c := range strings.Fields(string(content)) {
+		controllers[c] = dir
+	}
+	return controllers, nil
+}
+
+// Cgroup2Group returns the path of the cgroup v2 group of a process, relative
+// to Cgroup2Root, as listed in its /proc/<pid>/cgroup file.
+func Cgroup2Group(procCgroupFile string) (string, error) {
+	f, err := os.Open(procCgroupFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// The cgroup v2 hierarchy has ID 0 and no controllers: "0::/path".
+		if group := strings.TrimPrefix(s.Text(), "0::"); group != s.Text() {
+			return group, nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no cgroup v2 group found in %s", procCgroupFile)
+}
+
+// applyMemoryCgroupInfoV2 reads the memory information from the controllers
+// available on a cgroup v2 host. There is no equivalent of the swappiness,
+// OOM killer and kernel memory settings of cgroup v1.
+func applyMemoryCgroupInfoV2(info *SysInfo, controllers map[string]string) []string {
+	var warnings []string
+	if _, ok := controllers["memory"]; !ok {
+		warnings = append(warnings, "Unable to find memory controller")
+		return warnings
+	}
+
+	info.MemoryLimit = true
+	info.SwapLimit = true
+	info.MemoryReservation = true
+	return warnings
+}
+
+// applyCPUCgroupInfoV2 reads the cpu information from the controllers available
+// on a cgroup v2 host. Real-time scheduling is not supported by cgroup v2.
+func applyCPUCgroupInfoV2(info *SysInfo, controllers map[string]string) []string {
+	var warnings []string
+	if _, ok := controllers["cpu"]; !ok {
+		warnings = append(warnings, "Unable to find cpu controller")
+		return warnings
+	}
+
+	info.CPUShares = true
+	info.CPUCfsPeriod = true
+	info.CPUCfsQuota = true
+	return warnings
+}
+
+// applyIOCgroupInfoV2 reads the io information from the controllers available
+// on a cgroup v2 host. The io controller replaces the blkio controller.
+func applyIOCgroupInfoV2(info *SysInfo, controllers map[string]string) []string {
+	var warnings []string
+	if _, ok := controllers["io"]; !ok {
+		warnings = append(warnings, "Unable to find io controller")
+		return warnings
+	}
+
+	info.BlkioWeight = true
+	info.BlkioWeightDevice = true
+	info.BlkioReadBpsDevice = true
+	info.BlkioWriteBpsDevice = true
+	info.BlkioReadIOpsDevice = true
+	info.BlkioWriteIOpsDevice = true
+	return warnings
+}
+
+// applyCPUSetCgroupInfoV2 reads the cpuset information from the controllers
+// available on a cgroup v2 host.
+func applyCPUSetCgroupInfoV2(info *SysInfo, controllers map[string]string) []string {
+	var warnings []string
+	dir, ok := controllers["cpuset"]
+	if !ok {
+		warnings = append(warnings, "Unable to find cpuset controller")
+		return warnings
+	}
+	info.Cpuset = true
+
+	// The cpuset interface files only exist in non-root cgroups.
+	cpus, err := readCgroup2File(dir, "cpuset.cpus.effective")
+	if err != nil {
+		return warnings
+	}
+	info.Cpus = cpus
+
+	mems, err := readCgroup2File(dir, "cpuset.mems.effective")
+	if err != nil {
+		return warnings
+	}
+	info.Mems = mems
+	return warnings
+}
+
+// readCgroup2File reads an interface file of the cgroup at dir, falling back to
+// the one of the root cgroup if dir does not have it.
+func readCgroup2File(dir, name string) (string, error) {
+	content, err := ioutil.ReadFile(path.Join(dir, name))
+	if os.IsNotExist(err) {
+		content, err = ioutil.ReadFile(path.Join(Cgroup2Root, name))
+	}
+	return strings.TrimSpace(string(content)), err
+}
+
+// applyPIDSCgroupInfoV2 reads the pids information from the controllers
+// available on a cgroup v2 host.
+func applyPIDSCgroupInfoV2(info *SysInfo, controllers map[string]string) []string {
+	var warnings []string
+	if _, ok := controllers["pids"]; !ok {
+		warnings = append(warnings, "Unable to find pids controller")
+		return warnings
+	}
+	info.PidsLimit = true
+	return warnings
+}
+
+// applyDevicesCgroupInfoV2 adds the devices information on a cgroup v2 host.
+// cgroup v2 has no devices controller; access to devices is controlled by
+// eBPF programs attached to the cgroup, which is always possible.
+func applyDevicesCgroupInfoV2(info *SysInfo, _ map[string]string) []string {
+	info.CgroupDevicesEnabled = true
+	return nil
+}
@@ -0,0 +1,68 @@
+package sysinfo // import "github.com/docker/docker/pkg/sysinfo"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFindCgroup2Controllers(t *testing.T) {
+	root, err := ioutil.TempDir("", "test-sysinfo-cgroup2")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	group := filepath.Join(root, "system.slice", "docker.service")
+	assert.NilError(t, os.MkdirAll(group, 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpuset cpu io memory hugetlb pids\n"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(group, "cgroup.controllers"), []byte("cpu memory pids\n"), 0644))
+
+	procCgroup := filepath.Join(root, "cgroup")
+	assert.NilError(t, ioutil.WriteFile(procCgroup, []byte("0::/system.slice/docker.service\n"), 0644))
+
+	controllers, err := findCgroup2Controllers(root, procCgroup)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(controllers, map[string]string{"cpu": group, "memory": group, "pids": group}))
+
+	info := &SysInfo{}
+	for _, o := range []infoCollector{applyMemoryCgroupInfoV2, applyCPUCgroupInfoV2, applyIOCgroupInfoV2, applyPIDSCgroupInfoV2} {
+		o(info, controllers)
+	}
+	assert.Check(t, info.MemoryLimit)
+	assert.Check(t, info.SwapLimit)
+	assert.Check(t, !info.OomKillDisable)
+	assert.Check(t, !info.KernelMemory)
+	assert.Check(t, info.CPUCfsQuota)
+	assert.Check(t, !info.CPURealtimePeriod)
+	assert.Check(t, !info.BlkioWeight)
+	assert.Check(t, info.PidsLimit)
+
+	// Fall back to the root cgroup if the cgroup of the process is unknown.
+	assert.NilError(t, ioutil.WriteFile(procCgroup, []byte("0::/unknown\n"), 0644))
+	controllers, err = findCgroup2Controllers(root, procCgroup)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(controllers, 6))
+	assert.Check(t, is.Equal(controllers["io"], root))
+}
+
+func TestCgroup2Group(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-sysinfo-cgroup2")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	procCgroup := filepath.Join(dir, "cgroup")
+	assert.NilError(t, ioutil.WriteFile(procCgroup, []byte("12:pids:/user.slice\n1:name=systemd:/user.slice\n0::/user.slice/session-1.scope\n"), 0644))
+	group, err := Cgroup2Group(procCgroup)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(group, "/user.slice/session-1.scope"))
+
+	assert.NilError(t, ioutil.WriteFile(procCgroup, []byte("12:pids:/user.slice\n"), 0644))
+	_, err = Cgroup2Group(procCgroup)
+	assert.Check(t, is.ErrorContains(err, "no cgroup v2 group found"))
+
+	_, err = Cgroup2Group(filepath.Join(dir, "missing"))
+	assert.Check(t, os.IsNotExist(err))
+}
@@ -30,6 +30,9 @@ type SysInfo struct {
 
 	// Whether the cgroup has the mountpoint of "devices" or not
 	CgroupDevicesEnabled bool
+
+	// Whether the host uses the cgroup v2 unified hierarchy or not
+	CgroupUnified bool
 }
 
 type cgroupMemInfo struct {
@@ -26,6 +26,9 @@ func findCgroupMountpoints() (map[string]string, error) {
 	return mps, nil
 }
 
+// infoCollector collects information about a feature. cgMounts maps cgroup v1
+// subsystems to their mount point, or, on cgroup v2 hosts, the controllers
+// available to the daemon's cgroup to the path of that cgroup.
 type infoCollector func(info *SysInfo, cgMounts map[string]string) (warnings []string)
 
 // New returns a new SysInfo, using the filesystem to detect which features
@@ -35,8 +38,24 @@ func New(quiet bool) *SysInfo {
 	var ops []infoCollector
 	var warnings []string
 	sysInfo := &SysInfo{}
-	cgMounts, err := findCgroupMountpoints()
-	if err != nil {
+	var cgMounts map[string]string
+	var err error
+	if IsCgroup2UnifiedMode() {
+		sysInfo.CgroupUnified = true
+		cgMounts, err = findCgroup2Controllers(Cgroup2Root, "/proc/self/cgroup")
+		if err != nil {
+			logrus.Warn(err)
+		} else {
+			ops = append(ops, []infoCollector{
+				applyMemoryCgroupInfoV2,
+				applyCPUCgroupInfoV2,
+				applyIOCgroupInfoV2,
+				applyCPUSetCgroupInfoV2,
+				applyPIDSCgroupInfoV2,
+				applyDevicesCgroupInfoV2,
+			}...)
+		}
+	} else if cgMounts, err = findCgroupMountpoints(); err != nil {
 		logrus.Warn(err)
 	} else {
 		ops = append(ops, []infoCollector{
@@ -121,11 +121,13 @@ func TestIpcModeTest(t *testing.T) {
 
 func TestUTSModeTest(t *testing.T) {
 	utsModes := map[container.UTSMode][]bool{
-		// private, host, valid
-		"":                {true, false, true},
-		"something:weird": {true, false, false},
-		"host":            {false, true, true},
-		"host:name":       {true, false, true},
+		// private, host, container, valid
+		"":                {true, false, false, true},
+		"something:weird": {true, false, false, false},
+		"host":            {false, true, false, true},
+		"host:name":       {true, false, false, true},
+		"container:name":  {false, false, true, true},
+		"container:":      {false, false, true, false},
 	}
 	for utsMode, state := range utsModes {
 		if utsMode.IsPrivate() != state[0] {
@@ -134,8 +136,11 @@ func TestUTSModeTest(t *testing.T) {
 		if utsMode.IsHost() != state[1] {
 			t.Fatalf("UtsMode.IsHost for %v should have been %v but was %v", utsMode, state[1], utsMode.IsHost())
 		}
-		if utsMode.Valid() != state[2] {
-			t.Fatalf("UtsMode.Valid for %v should have been %v but was %v", utsMode, state[2], utsMode.Valid())
+		if utsMode.IsContainer() != state[2] {
+			t.Fatalf("UtsMode.IsContainer for %v should have been %v but was %v", utsMode, state[2], utsMode.IsContainer())
+		}
+		if utsMode.Valid() != state[3] {
+			t.Fatalf("UtsMode.Valid for %v should have been %v but was %v", utsMode, state[3], utsMode.Valid())
 		}
 	}
 }
@@ -1,10 +1,12 @@
 package drivers // import "github.com/docker/docker/volume/drivers"
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/volume"
 	"github.com/sirupsen/logrus"
 )
@@ -29,10 +31,11 @@ func (a *volumeDriverAdapter) Create(name string, opts map[string]string) (volum
 		return nil, err
 	}
 	return &volumeAdapter{
-		proxy:      a.proxy,
-		name:       name,
-		driverName: a.name,
-		scopePath:  a.scopePath,
+		proxy:        a.proxy,
+		capabilities: a.getCapabilities,
+		name:         name,
+		driverName:   a.name,
+		scopePath:    a.scopePath,
 	}, nil
 }
 
@@ -49,11 +52,12 @@ func (a *volumeDriverAdapter) List() ([]volume.Volume, error) {
 	var out []volume.Volume
 	for _, vp := range ls {
 		out = append(out, &volumeAdapter{
-			proxy:      a.proxy,
-			name:       vp.Name,
-			scopePath:  a.scopePath,
-			driverName: a.name,
-			eMount:     a.scopePath(vp.Mountpoint),
+			proxy:        a.proxy,
+			capabilities: a.getCapabilities,
+			name:         vp.Name,
+			scopePath:    a.scopePath,
+			driverName:   a.name,
+			eMount:       a.scopePath(vp.Mountpoint),
 		})
 	}
 	return out, nil
@@ -71,13 +75,14 @@ func (a *volumeDriverAdapter) Get(name string) (volume.Volume, error) {
 	}
 
 	return &volumeAdapter{
-		proxy:      a.proxy,
-		name:       v.Name,
-		driverName: a.Name(),
-		eMount:     v.Mountpoint,
-		createdAt:  v.CreatedAt,
-		status:     v.Status,
-		scopePath:  a.scopePath,
+		proxy:        a.proxy,
+		capabilities: a.getCapabilities,
+		name:         v.Name,
+		driverName:   a.Name(),
+		eMount:       v.Mountpoint,
+		createdAt:    v.CreatedAt,
+		status:       v.Status,
+		scopePath:    a.scopePath,
 	}, nil
 }
 
@@ -103,6 +108,13 @@ func (a *volumeDriverAdapter) getCapabilities() volume.Capability {
 		cap.Scope = volume.LocalScope
 	}
 
+	if cap.Version < volume.DriverProtocolVersion2 && (cap.Snapshot || cap.Resize || cap.Usage || cap.MountOptions) {
+		// optional features are only supported by drivers opting into
+		// the version 2 of the protocol
+		logrus.WithField("driver", a.name).Warn("Volume driver reported optional capabilities without opting into version 2 of the volume driver protocol, ignoring them")
+		cap.Snapshot, cap.Resize, cap.Usage, cap.MountOptions = false, false, false, false
+	}
+
 	cap.Scope = strings.ToLower(cap.Scope)
 	if cap.Scope != volume.LocalScope && cap.Scope != volume.GlobalScope {
 		logrus.WithField("driver", a.Name()).WithField("scope", a.Scope).Warn("Volume driver returned an invalid scope")
@@ -113,14 +125,89 @@ func (a *volumeDriverAdapter) getCapabilities() volume.Capability {
 	return cap
 }
 
+// Capabilities returns the capabilities of the driver.
+func (a *volumeDriverAdapter) Capabilities() volume.Capability {
+	return a.getCapabilities()
+}
+
+func (a *volumeDriverAdapter) checkSnapshot() error {
+	if !a.getCapabilities().Snapshot {
+		return errdefs.NotImplemented(errors.New("volume driver " + a.name + " does not support snapshots"))
+	}
+	return nil
+}
+
+func (a *volumeDriverAdapter) Snapshot(v volume.Volume, name string) (volume.Snapshot, error) {
+	if err := a.checkSnapshot(); err != nil {
+		return volume.Snapshot{}, err
+	}
+	if err := a.proxy.Snapshot(v.Name(), name); err != nil {
+		return volume.Snapshot{}, err
+	}
+	return volume.Snapshot{Name: name, CreatedAt: time.Now().UTC()}, nil
+}
+
+func (a *volumeDriverAdapter) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	if err := a.checkSnapshot(); err != nil {
+		return nil, err
+	}
+	ls, err := a.proxy.ListSnapshots(v.Name())
+	if err != nil {
+		return nil, err
+	}
+	out := make([]volume.Snapshot, 0, len(ls))
+	for _, sp := range ls {
+		out = append(out, volume.Snapshot{Name: sp.Name, CreatedAt: sp.CreatedAt})
+	}
+	return out, nil
+}
+
+func (a *volumeDriverAdapter) RemoveSnapshot(v volume.Volume, name string) error {
+	if err := a.checkSnapshot(); err != nil {
+		return err
+	}
+	return a.proxy.RemoveSnapshot(v.Name(), name)
+}
+
+func (a *volumeDriverAdapter) Clone(v volume.Volume, snapshot, name string, opts map[string]string) (volume.Volume, error) {
+	if err := a.checkSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := a.proxy.Clone(v.Name(), snapshot, name, opts); err != nil {
+		return nil, err
+	}
+	return &volumeAdapter{
+		proxy:        a.proxy,
+		capabilities: a.getCapabilities,
+		name:         name,
+		driverName:   a.name,
+		scopePath:    a.scopePath,
+	}, nil
+}
+
+func (a *volumeDriverAdapter) Restore(v volume.Volume, snapshot string) error {
+	if err := a.checkSnapshot(); err != nil {
+		return err
+	}
+	return a.proxy.Restore(v.Name(), snapshot)
+}
+
+func (a *volumeDriverAdapter) Resize(v volume.Volume, size int64) error {
+	if !a.getCapabilities().Resize {
+		return errdefs.NotImplemented(errors.New("volume driver " + a.name + " does not support resizing volumes"))
+	}
+	return a.proxy.Resize(v.Name(), size)
+}
+
 type volumeAdapter struct {
-	proxy      volumeDriver
-	name       string
-	scopePath  func(string) string
-	driverName string
-	eMount     string    // ephemeral host volume path
-	createdAt  time.Time // time the directory was created
-	status     map[string]interface{}
+	proxy        volumeDriver
+	capabilities func() volume.Capability
+	name         string
+	scopePath    func(string) string
+	driverName   string
+	eMount       string    // ephemeral host volume path
+	createdAt    time.Time // time the directory was created
+	status       map[string]interface{}
 }
 
 type proxyVolume struct {
@@ -130,6 +217,21 @@ type proxyVolume struct {
 	Status     map[string]interface{}
 }
 
+type proxySnapshot struct {
+	Name      string
+	CreatedAt time.Time
+}
+
+type proxyUsage struct {
+	Size  int64
+	Limit int64
+}
+
+type proxyMountOptions struct {
+	ReadOnly bool
+	Subpath  string
+}
+
 func (a *volumeAdapter) Name() string {
 	return a.name
 }
@@ -156,6 +258,33 @@ func (a *volumeAdapter) Mount(id string) (string, error) {
 	return a.eMount, err
 }
 
+// MountWithOptions mounts the volume with the given options if the driver
+// accepts per-mount options, and like Mount otherwise.
+func (a *volumeAdapter) MountWithOptions(id string, opts volume.MountOptions) (string, error) {
+	if !a.capabilities().MountOptions {
+		return a.Mount(id)
+	}
+	mountpoint, err := a.proxy.MountWithOptions(a.name, id, &proxyMountOptions{ReadOnly: opts.ReadOnly, Subpath: opts.Subpath})
+	a.eMount = a.scopePath(mountpoint)
+	return a.eMount, err
+}
+
+// Usage returns the disk space used by the volume, and its size limit, if
+// the driver reports the disk usage of volumes.
+func (a *volumeAdapter) Usage(ctx context.Context) (usage int64, limit int64, err error) {
+	if !a.capabilities().Usage {
+		return 0, 0, errdefs.NotImplemented(errors.New("volume driver " + a.driverName + " does not report the disk usage of volumes"))
+	}
+	u, err := a.proxy.Usage(a.name)
+	if err != nil {
+		return 0, 0, err
+	}
+	if u == nil {
+		return 0, 0, errdefs.System(errors.New("volume driver " + a.driverName + " returned no disk usage"))
+	}
+	return u.Size, u.Limit, nil
+}
+
 func (a *volumeAdapter) Unmount(id string) error {
 	err := a.proxy.Unmount(a.name, id)
 	if err == nil {
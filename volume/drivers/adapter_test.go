@@ -0,0 +1,122 @@
+package drivers // import "github.com/docker/docker/volume/drivers"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/docker/docker/volume"
+	"github.com/docker/go-connections/tlsconfig"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newTestAdapter(t *testing.T, mux *http.ServeMux) (*volumeDriverAdapter, func()) {
+	server := httptest.NewServer(mux)
+
+	u, _ := url.Parse(server.URL)
+	client, err := plugins.NewClient("tcp://"+u.Host, &tlsconfig.Options{InsecureSkipVerify: true})
+	assert.NilError(t, err)
+	return &volumeDriverAdapter{
+		name:      "test",
+		scopePath: func(s string) string { return s },
+		proxy:     &volumeDriverProxy{client},
+	}, server.Close
+}
+
+func handle(mux *http.ServeMux, method string, resp interface{}) {
+	mux.HandleFunc("/VolumeDriver."+method, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func TestAdapterProtocolVersion1(t *testing.T) {
+	mux := http.NewServeMux()
+	// optional features are ignored for drivers not opting into the version 2
+	// of the protocol
+	handle(mux, "Capabilities", map[string]interface{}{
+		"Capabilities": map[string]interface{}{"Scope": "global", "Snapshot": true, "Resize": true, "Usage": true, "MountOptions": true},
+	})
+	handle(mux, "Get", map[string]interface{}{"Volume": map[string]string{"Name": "vol"}})
+	handle(mux, "Mount", map[string]string{"Mountpoint": "/v1"})
+	mux.HandleFunc("/VolumeDriver.MountWithOptions", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("MountWithOptions called on a version 1 driver")
+		http.Error(w, "unexpected", http.StatusInternalServerError)
+	})
+	a, cleanup := newTestAdapter(t, mux)
+	defer cleanup()
+
+	caps := a.Capabilities()
+	assert.Check(t, is.DeepEqual(caps, volume.Capability{Scope: volume.GlobalScope}))
+
+	v, err := a.Get("vol")
+	assert.NilError(t, err)
+
+	mountpoint, err := v.(volume.OptionsMounter).MountWithOptions("id", volume.MountOptions{ReadOnly: true, Subpath: "sub"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mountpoint, "/v1"))
+
+	_, _, err = v.(*volumeAdapter).Usage(context.Background())
+	assert.Check(t, errdefs.IsNotImplemented(err), "%v", err)
+	err = a.Resize(v, 1024)
+	assert.Check(t, errdefs.IsNotImplemented(err), "%v", err)
+	_, err = a.Snapshot(v, "snap")
+	assert.Check(t, errdefs.IsNotImplemented(err), "%v", err)
+}
+
+func TestAdapterProtocolVersion2(t *testing.T) {
+	mux := http.NewServeMux()
+	handle(mux, "Capabilities", map[string]interface{}{
+		"Capabilities": map[string]interface{}{"Version": 2, "Resize": true, "Usage": true, "MountOptions": true},
+	})
+	handle(mux, "Get", map[string]interface{}{"Volume": map[string]string{"Name": "vol"}})
+	handle(mux, "Usage", map[string]interface{}{"Usage": map[string]int64{"Size": 10, "Limit": 100}})
+
+	var resizeReq volumeDriverProxyResizeRequest
+	mux.HandleFunc("/VolumeDriver.Resize", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&resizeReq)
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{}`)
+	})
+	var mountReq volumeDriverProxyMountWithOptionsRequest
+	mux.HandleFunc("/VolumeDriver.MountWithOptions", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&mountReq)
+		w.Header().Set("Content-Type", "application/vnd.docker.plugins.v1+json")
+		fmt.Fprintln(w, `{"Mountpoint": "/v2"}`)
+	})
+	a, cleanup := newTestAdapter(t, mux)
+	defer cleanup()
+
+	caps := a.Capabilities()
+	assert.Check(t, is.DeepEqual(caps, volume.Capability{Scope: volume.LocalScope, Version: 2, Resize: true, Usage: true, MountOptions: true}))
+
+	v, err := a.Get("vol")
+	assert.NilError(t, err)
+
+	mountpoint, err := v.(volume.OptionsMounter).MountWithOptions("id", volume.MountOptions{ReadOnly: true, Subpath: "sub"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mountpoint, "/v2"))
+	assert.Check(t, is.Equal(mountReq.Name, "vol"))
+	assert.Check(t, is.Equal(mountReq.ID, "id"))
+	assert.Check(t, is.DeepEqual(mountReq.Opts, &proxyMountOptions{ReadOnly: true, Subpath: "sub"}))
+
+	usage, limit, err := v.(*volumeAdapter).Usage(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(usage, int64(10)))
+	assert.Check(t, is.Equal(limit, int64(100)))
+
+	assert.NilError(t, a.Resize(v, 1024))
+	assert.Check(t, is.Equal(resizeReq.Name, "vol"))
+	assert.Check(t, is.Equal(resizeReq.Size, int64(1024)))
+
+	// snapshots are not supported by this driver
+	_, err = a.Snapshot(v, "snap")
+	assert.Check(t, errdefs.IsNotImplemented(err), "%v", err)
+}
@@ -39,6 +39,23 @@ type volumeDriver interface {
 	Get(name string) (volume *proxyVolume, err error)
 	// Capabilities gets the list of capabilities of the driver
 	Capabilities() (capabilities volume.Capability, err error)
+	// Snapshot creates a snapshot with the given name of the volume
+	Snapshot(name, snapshot string) (err error)
+	// ListSnapshots lists the snapshots of the volume
+	ListSnapshots(name string) (snapshots []*proxySnapshot, err error)
+	// RemoveSnapshot removes the snapshot with the given name of the volume
+	RemoveSnapshot(name, snapshot string) (err error)
+	// Clone creates the target volume from the volume, or from its snapshot
+	Clone(name, snapshot, target string, opts map[string]string) (err error)
+	// Restore replaces the data of the volume with the data of the snapshot
+	Restore(name, snapshot string) (err error)
+	// Resize changes the size of the volume
+	Resize(name string, size int64) (err error)
+	// Usage gets the disk usage of the volume
+	Usage(name string) (usage *proxyUsage, err error)
+	// MountWithOptions mounts the given volume with per-mount options and
+	// returns the mountpoint
+	MountWithOptions(name, id string, opts *proxyMountOptions) (mountpoint string, err error)
 }
 
 // Store is an in-memory store for volume drivers
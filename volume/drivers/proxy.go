@@ -253,3 +253,246 @@ func (pp *volumeDriverProxy) Capabilities() (capabilities volume.Capability, err
 
 	return
 }
+
+type volumeDriverProxySnapshotRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxySnapshotResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) Snapshot(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxySnapshotRequest
+		ret volumeDriverProxySnapshotResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+
+	if err = pp.CallWithOptions("VolumeDriver.Snapshot", req, &ret, plugins.WithRequestTimeout(longTimeout)); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyListSnapshotsRequest struct {
+	Name string
+}
+
+type volumeDriverProxyListSnapshotsResponse struct {
+	Snapshots []*proxySnapshot
+	Err       string
+}
+
+func (pp *volumeDriverProxy) ListSnapshots(name string) (snapshots []*proxySnapshot, err error) {
+	var (
+		req volumeDriverProxyListSnapshotsRequest
+		ret volumeDriverProxyListSnapshotsResponse
+	)
+
+	req.Name = name
+
+	if err = pp.CallWithOptions("VolumeDriver.ListSnapshots", req, &ret, plugins.WithRequestTimeout(shortTimeout)); err != nil {
+		return
+	}
+
+	snapshots = ret.Snapshots
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyRemoveSnapshotRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxyRemoveSnapshotResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) RemoveSnapshot(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxyRemoveSnapshotRequest
+		ret volumeDriverProxyRemoveSnapshotResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+
+	if err = pp.CallWithOptions("VolumeDriver.RemoveSnapshot", req, &ret, plugins.WithRequestTimeout(shortTimeout)); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyCloneRequest struct {
+	Name     string
+	Snapshot string
+	Target   string
+	Opts     map[string]string
+}
+
+type volumeDriverProxyCloneResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) Clone(name string, snapshot string, target string, opts map[string]string) (err error) {
+	var (
+		req volumeDriverProxyCloneRequest
+		ret volumeDriverProxyCloneResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+	req.Target = target
+	req.Opts = opts
+
+	if err = pp.CallWithOptions("VolumeDriver.Clone", req, &ret, plugins.WithRequestTimeout(longTimeout)); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyRestoreRequest struct {
+	Name     string
+	Snapshot string
+}
+
+type volumeDriverProxyRestoreResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) Restore(name string, snapshot string) (err error) {
+	var (
+		req volumeDriverProxyRestoreRequest
+		ret volumeDriverProxyRestoreResponse
+	)
+
+	req.Name = name
+	req.Snapshot = snapshot
+
+	if err = pp.CallWithOptions("VolumeDriver.Restore", req, &ret, plugins.WithRequestTimeout(longTimeout)); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyResizeRequest struct {
+	Name string
+	Size int64
+}
+
+type volumeDriverProxyResizeResponse struct {
+	Err string
+}
+
+func (pp *volumeDriverProxy) Resize(name string, size int64) (err error) {
+	var (
+		req volumeDriverProxyResizeRequest
+		ret volumeDriverProxyResizeResponse
+	)
+
+	req.Name = name
+	req.Size = size
+
+	if err = pp.CallWithOptions("VolumeDriver.Resize", req, &ret, plugins.WithRequestTimeout(longTimeout)); err != nil {
+		return
+	}
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyUsageRequest struct {
+	Name string
+}
+
+type volumeDriverProxyUsageResponse struct {
+	Usage *proxyUsage
+	Err   string
+}
+
+func (pp *volumeDriverProxy) Usage(name string) (usage *proxyUsage, err error) {
+	var (
+		req volumeDriverProxyUsageRequest
+		ret volumeDriverProxyUsageResponse
+	)
+
+	req.Name = name
+
+	if err = pp.CallWithOptions("VolumeDriver.Usage", req, &ret, plugins.WithRequestTimeout(shortTimeout)); err != nil {
+		return
+	}
+
+	usage = ret.Usage
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
+
+type volumeDriverProxyMountWithOptionsRequest struct {
+	Name string
+	ID   string
+	Opts *proxyMountOptions
+}
+
+type volumeDriverProxyMountWithOptionsResponse struct {
+	Mountpoint string
+	Err        string
+}
+
+func (pp *volumeDriverProxy) MountWithOptions(name string, id string, opts *proxyMountOptions) (mountpoint string, err error) {
+	var (
+		req volumeDriverProxyMountWithOptionsRequest
+		ret volumeDriverProxyMountWithOptionsResponse
+	)
+
+	req.Name = name
+	req.ID = id
+	req.Opts = opts
+
+	if err = pp.CallWithOptions("VolumeDriver.MountWithOptions", req, &ret, plugins.WithRequestTimeout(longTimeout)); err != nil {
+		return
+	}
+
+	mountpoint = ret.Mountpoint
+
+	if ret.Err != "" {
+		err = errors.New(ret.Err)
+	}
+
+	return
+}
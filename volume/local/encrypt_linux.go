@@ -0,0 +1,191 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// supportedEncryptions are the supported values of the encryption option.
+var supportedEncryptions = map[string]struct{}{
+	"luks": {}, // dm-crypt/LUKS encrypted disk image
+}
+
+const (
+	// luksMapperPrefix prefixes the names of the device mapper devices of
+	// open encrypted volumes, followed by the SHA-256 digest of the name of
+	// the volume, which can be longer than the 127 bytes allowed for the
+	// names of the devices.
+	luksMapperPrefix = "docker-volume-"
+)
+
+// encryption holds the state of an encrypted volume. The data of encrypted
+// volumes is stored in a LUKS-formatted disk image, which is opened with
+// dm-crypt when the volume is mounted.
+type encryption struct {
+	diskImage  string
+	mapperName string
+	keyID      string
+	// keyProvider returns the key provider of the volume. It is looked up
+	// when needed, as it may be registered after the volume is loaded.
+	keyProvider func() (KeyProvider, error)
+}
+
+func (e *encryption) mapperDevice() string {
+	return filepath.Join("/dev/mapper", e.mapperName)
+}
+
+// setupEncryption creates the encrypted disk image of a new volume, if it was
+// created with the encryption option. Its key is created if the volume was not
+// created with the key option.
+func (r *Root) setupEncryption(v *localVolume) (retErr error) {
+	if v.opts == nil || v.opts.Encryption == "" {
+		return nil
+	}
+	if _, err := exec.LookPath("cryptsetup"); err != nil {
+		return errdefs.NotImplemented(errors.New("encrypted volumes require cryptsetup to be installed"))
+	}
+	keyProvider, err := r.keyProvider(v.opts.KeyProvider)
+	if err != nil {
+		return err
+	}
+
+	var key []byte
+	if v.opts.KeyID == "" {
+		v.opts.KeyID = v.name
+		v.opts.GeneratedKey = true
+		key, err = keyProvider.CreateKey(v.opts.KeyID)
+		if err != nil {
+			return errors.Wrap(err, "error while creating key of volume")
+		}
+		defer func() {
+			if retErr != nil {
+				if err := keyProvider.RemoveKey(v.opts.KeyID); err != nil {
+					logrus.WithError(err).WithField("volume", v.name).Warn("Error removing key of volume")
+				}
+			}
+		}()
+	} else {
+		key, err = keyProvider.GetKey(v.opts.KeyID)
+		if err != nil {
+			return errors.Wrap(err, "error while getting key of volume")
+		}
+	}
+
+	e := r.newEncryption(v)
+	if err := e.format(v.opts.Size, key, r.rootIdentity); err != nil {
+		os.Remove(e.diskImage)
+		return err
+	}
+	v.encryption = e
+	v.size = &sizeLimit{size: v.opts.Size, volumeDir: filepath.Dir(v.path), diskImage: e.diskImage}
+	return nil
+}
+
+// loadEncryption loads the state of an existing encrypted volume, and closes
+// its device if it is still open, for example after an unclean shutdown.
+func (r *Root) loadEncryption(v *localVolume) {
+	if v.opts == nil || v.opts.Encryption == "" {
+		return
+	}
+	v.encryption = r.newEncryption(v)
+	v.size = &sizeLimit{size: v.opts.Size, volumeDir: filepath.Dir(v.path), diskImage: v.encryption.diskImage}
+	if err := v.encryption.close(); err != nil {
+		logrus.WithError(err).WithField("volume", v.name).Warn("Error closing encrypted device of volume")
+	}
+}
+
+func (r *Root) newEncryption(v *localVolume) *encryption {
+	keyProvider := v.opts.KeyProvider
+	return &encryption{
+		diskImage:  filepath.Join(filepath.Dir(v.path), diskImageName),
+		mapperName: luksMapperPrefix + digest.FromString(v.name).Hex(),
+		keyID:      v.opts.KeyID,
+		keyProvider: func() (KeyProvider, error) {
+			return r.keyProvider(keyProvider)
+		},
+	}
+}
+
+// format creates the disk image with a LUKS header and an ext4 filesystem.
+func (e *encryption) format(size uint64, key []byte, rootIdentity idtools.Identity) error {
+	if err := createSparseFile(e.diskImage, size); err != nil {
+		return err
+	}
+	if err := cryptsetup(key, "luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", e.diskImage); err != nil {
+		return errors.Wrap(err, "error while formatting encrypted disk image of volume")
+	}
+	if err := e.open(key); err != nil {
+		return err
+	}
+	defer e.close()
+	return mkfsExt4(e.mapperDevice(), rootIdentity)
+}
+
+// open opens the disk image as a dm-crypt device. cryptsetup attaches the
+// disk image to a loopback device, which is detached automatically once the
+// dm-crypt device is closed.
+func (e *encryption) open(key []byte) error {
+	if err := cryptsetup(key, "open", "--type", "luks", "--key-file", "-", e.diskImage, e.mapperName); err != nil {
+		return errors.Wrap(err, "error while opening encrypted disk image of volume")
+	}
+	return nil
+}
+
+// close closes the dm-crypt device of the volume, if it is open.
+func (e *encryption) close() error {
+	if _, err := os.Stat(e.mapperDevice()); err != nil {
+		return nil
+	}
+	return errors.Wrap(cryptsetup(nil, "close", e.mapperName), "error while closing encrypted device of volume")
+}
+
+// mount opens the encrypted device of the volume and mounts it on target.
+func (e *encryption) mount(target string) error {
+	keyProvider, err := e.keyProvider()
+	if err != nil {
+		return err
+	}
+	key, err := keyProvider.GetKey(e.keyID)
+	if err != nil {
+		return errors.Wrap(err, "error while getting key of volume")
+	}
+	if err := e.open(key); err != nil {
+		return err
+	}
+	if err := mount.Mount(e.mapperDevice(), target, "ext4", ""); err != nil {
+		e.close()
+		return errors.Wrap(err, "failed to mount encrypted device of volume")
+	}
+	removeLostAndFound(target)
+	return nil
+}
+
+// remove removes the key of the volume if it was created with the volume.
+func (e *encryption) remove(opts *optsConfig) error {
+	if !opts.GeneratedKey {
+		return nil
+	}
+	keyProvider, err := e.keyProvider()
+	if err != nil {
+		return err
+	}
+	return keyProvider.RemoveKey(e.keyID)
+}
+
+func cryptsetup(key []byte, args ...string) error {
+	cmd := exec.Command("cryptsetup", args...)
+	cmd.Stdin = bytes.NewReader(key)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errdefs.System(errors.Wrapf(err, "cryptsetup %s failed: %s", args[0], bytes.TrimSpace(out)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestValidateEncryptionOpts(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{"encryption": "luks"},
+		{"encryption": "rot13", "size": "10M"},
+		{"encryption": "luks", "size": "10M", "device": "tmpfs"},
+		{"key": "foo"},
+		{"keyprovider": "file", "size": "10M"},
+	} {
+		err := validateOpts(opts)
+		assert.Check(t, errdefs.IsInvalidParameter(err), "options: %v, error: %v", opts, err)
+	}
+
+	assert.Check(t, validateOpts(map[string]string{"encryption": "luks", "size": "10M"}))
+	assert.Check(t, validateOpts(map[string]string{"encryption": "luks", "size": "10M", "key": "foo", "keyprovider": "file"}))
+}
+
+func TestCreateEncrypted(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	for _, cmd := range []string{"cryptsetup", "mkfs.ext4"} {
+		if _, err := exec.LookPath(cmd); err != nil {
+			t.Skipf("requires %s", cmd)
+		}
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	rootIdentity := idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()}
+	r, err := New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+
+	_, err = r.Create("test", map[string]string{"encryption": "luks", "size": "32M", "keyprovider": "foo"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	vol, err := r.Create("test", map[string]string{"encryption": "luks", "size": "32M"})
+	assert.NilError(t, err)
+	v := vol.(*localVolume)
+	keyPath := filepath.Join(rootDir, keyringPathName, "test")
+	_, err = os.Stat(keyPath)
+	assert.NilError(t, err)
+
+	dir, err := v.Mount("1234")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "data"), []byte("secret"), 0644))
+
+	// double mount only opens the device once
+	_, err = v.Mount("5678")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v.active.count, uint64(2)))
+
+	assert.NilError(t, v.Unmount("5678"))
+	mounted, err := mount.Mounted(dir)
+	assert.NilError(t, err)
+	assert.Check(t, mounted)
+
+	assert.NilError(t, v.Unmount("1234"))
+	mounted, err = mount.Mounted(dir)
+	assert.NilError(t, err)
+	assert.Check(t, !mounted)
+	_, err = os.Stat(v.encryption.mapperDevice())
+	assert.Check(t, os.IsNotExist(err))
+
+	// the data is only accessible when the volume is mounted
+	_, err = os.Stat(filepath.Join(dir, "data"))
+	assert.Check(t, os.IsNotExist(err))
+
+	r, err = New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+	v2 := r.volumes["test"]
+	assert.Assert(t, v2.encryption != nil)
+	dir, err = v2.Mount("1234")
+	assert.NilError(t, err)
+	b, err := ioutil.ReadFile(filepath.Join(dir, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "secret"))
+	assert.NilError(t, v2.Unmount("1234"))
+
+	assert.NilError(t, r.Remove(v2))
+	_, err = os.Stat(keyPath)
+	assert.Check(t, os.IsNotExist(err))
+}
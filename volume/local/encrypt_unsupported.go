@@ -0,0 +1,27 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+// supportedEncryptions is empty, as encrypted volumes are only supported on
+// Linux.
+var supportedEncryptions = map[string]struct{}{}
+
+type encryption struct{}
+
+func (r *Root) setupEncryption(v *localVolume) error {
+	return nil
+}
+
+func (r *Root) loadEncryption(v *localVolume) {}
+
+func (e *encryption) mount(target string) error {
+	return nil
+}
+
+func (e *encryption) close() error {
+	return nil
+}
+
+func (e *encryption) remove(opts *optsConfig) error {
+	return nil
+}
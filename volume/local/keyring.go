@@ -0,0 +1,195 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultKeyProvider is the name of the key provider used for encrypted
+	// volumes if none is specified with the keyprovider option.
+	DefaultKeyProvider = "file"
+
+	keyringPathName = "volume-keys"
+	keySize         = 64
+
+	// keyHelperExitCode is the exit code of a key helper program for keys
+	// which do not exist, or which already exist when creating them.
+	keyHelperExitCode = 2
+)
+
+// KeyProvider provides the keys of encrypted volumes. Keys are identified by
+// an ID, which is the name of the volume unless the key option is used.
+type KeyProvider interface {
+	// GetKey returns the key with the given ID. An error satisfying
+	// errdefs.IsNotFound is returned if the key does not exist.
+	GetKey(id string) ([]byte, error)
+	// CreateKey creates a new random key with the given ID. An error
+	// satisfying errdefs.IsConflict is returned if the key already exists.
+	CreateKey(id string) ([]byte, error)
+	// RemoveKey removes the key with the given ID. Removing a key that
+	// does not exist is not an error.
+	RemoveKey(id string) error
+}
+
+// RegisterKeyProvider registers a key provider for encrypted volumes with the
+// given name, which can then be selected with the keyprovider option. It
+// replaces any key provider previously registered with the same name.
+func (r *Root) RegisterKeyProvider(name string, p KeyProvider) {
+	r.keyProvidersMu.Lock()
+	r.keyProviders[name] = p
+	r.keyProvidersMu.Unlock()
+}
+
+// keyProvider returns the key provider with the given name, or the default
+// key provider if name is empty.
+func (r *Root) keyProvider(name string) (KeyProvider, error) {
+	if name == "" {
+		name = DefaultKeyProvider
+	}
+	r.keyProvidersMu.RLock()
+	p, ok := r.keyProviders[name]
+	r.keyProvidersMu.RUnlock()
+	if !ok {
+		return nil, errdefs.InvalidParameter(errors.Errorf("unknown key provider: %q", name))
+	}
+	return p, nil
+}
+
+// fileKeyring is a KeyProvider storing each key in a file, named after the
+// key ID, in a directory only accessible by the daemon.
+type fileKeyring struct {
+	path string
+}
+
+func newFileKeyring(path string, rootIdentity idtools.Identity) (*fileKeyring, error) {
+	if err := idtools.MkdirAllAndChown(path, 0700, rootIdentity); err != nil {
+		return nil, err
+	}
+	return &fileKeyring{path: path}, nil
+}
+
+func (k *fileKeyring) keyPath(id string) (string, error) {
+	if !volumeNameRegex.MatchString(id) {
+		return "", errdefs.InvalidParameter(errors.Errorf("invalid key ID: %q", id))
+	}
+	return filepath.Join(k.path, id), nil
+}
+
+func (k *fileKeyring) GetKey(id string) ([]byte, error) {
+	path, err := k.keyPath(id)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errdefs.NotFound(errors.Errorf("no such key: %s", id))
+		}
+		return nil, errdefs.System(errors.Wrapf(err, "error while reading key %s", id))
+	}
+	return key, nil
+}
+
+func (k *fileKeyring) CreateKey(id string) ([]byte, error) {
+	path, err := k.keyPath(id)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errdefs.System(errors.Wrap(err, "error while generating key"))
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, errdefs.Conflict(errors.Errorf("key %s already exists", id))
+		}
+		return nil, errdefs.System(err)
+	}
+	_, err = f.Write(key)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, errdefs.System(errors.Wrapf(err, "error while writing key %s", id))
+	}
+	return key, nil
+}
+
+func (k *fileKeyring) RemoveKey(id string) error {
+	path, err := k.keyPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errdefs.System(errors.Wrapf(err, "error while removing key %s", id))
+	}
+	return nil
+}
+
+// keyHelper is a KeyProvider running a helper program, which is given the
+// operation, "get", "create" or "remove", and the key ID as arguments. For the
+// "get" and "create" operations, the helper writes the key to its standard
+// output. The helper exits with the status keyHelperExitCode if the key does
+// not exist, or if it already exists when creating it.
+type keyHelper struct {
+	path string
+}
+
+// NewKeyHelper returns a KeyProvider running the helper program at path for
+// each operation on the keys.
+func NewKeyHelper(path string) KeyProvider {
+	return &keyHelper{path: path}
+}
+
+func (k *keyHelper) run(op, id string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(k.path, op, id)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == keyHelperExitCode {
+			return nil, exitErr
+		}
+		return nil, errdefs.System(errors.Wrapf(err, "key helper %s %s failed: %s", k.path, op, bytes.TrimSpace(stderr.Bytes())))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (k *keyHelper) GetKey(id string) ([]byte, error) {
+	key, err := k.run("get", id)
+	if _, ok := err.(*exec.ExitError); ok {
+		return nil, errdefs.NotFound(errors.Errorf("no such key: %s", id))
+	}
+	return key, err
+}
+
+func (k *keyHelper) CreateKey(id string) ([]byte, error) {
+	key, err := k.run("create", id)
+	if _, ok := err.(*exec.ExitError); ok {
+		return nil, errdefs.Conflict(errors.Errorf("key %s already exists", id))
+	}
+	return key, err
+}
+
+func (k *keyHelper) RemoveKey(id string) error {
+	_, err := k.run("remove", id)
+	if _, ok := err.(*exec.ExitError); ok {
+		return nil
+	}
+	return err
+}
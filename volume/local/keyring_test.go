@@ -0,0 +1,87 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local-volume-keyring-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	k, err := newFileKeyring(filepath.Join(dir, "keys"), idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+
+	_, err = k.GetKey("test")
+	assert.Check(t, errdefs.IsNotFound(err), err)
+	_, err = k.CreateKey("../test")
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	key, err := k.CreateKey("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(key, keySize))
+
+	_, err = k.CreateKey("test")
+	assert.Check(t, errdefs.IsConflict(err), err)
+
+	key2, err := k.GetKey("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(key, key2))
+
+	fi, err := os.Stat(filepath.Join(dir, "keys", "test"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0600)))
+
+	assert.NilError(t, k.RemoveKey("test"))
+	assert.NilError(t, k.RemoveKey("test"))
+	_, err = k.GetKey("test")
+	assert.Check(t, errdefs.IsNotFound(err), err)
+}
+
+func TestKeyHelper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local-volume-keyhelper-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	helper := filepath.Join(dir, "helper")
+	script := `#!/bin/sh
+key="` + dir + `/$2"
+case "$1" in
+get) [ -f "$key" ] || exit 2; cat "$key" ;;
+create) [ -f "$key" ] && exit 2; echo -n "key-$2" > "$key"; cat "$key" ;;
+remove) [ -f "$key" ] || exit 2; rm "$key" ;;
+*) echo "unknown operation" >&2; exit 1 ;;
+esac
+`
+	assert.NilError(t, ioutil.WriteFile(helper, []byte(script), 0700))
+	k := NewKeyHelper(helper)
+
+	_, err = k.GetKey("test")
+	assert.Check(t, errdefs.IsNotFound(err), err)
+
+	key, err := k.CreateKey("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(key), "key-test"))
+	_, err = k.CreateKey("test")
+	assert.Check(t, errdefs.IsConflict(err), err)
+
+	key, err = k.GetKey("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(key), "key-test"))
+
+	assert.NilError(t, k.RemoveKey("test"))
+	assert.NilError(t, k.RemoveKey("test"))
+	_, err = k.GetKey("test")
+	assert.Check(t, errdefs.IsNotFound(err), err)
+
+	err = (&keyHelper{path: filepath.Join(dir, "missing")}).RemoveKey("test")
+	assert.Check(t, errdefs.IsSystem(err), err)
+}
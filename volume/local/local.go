@@ -4,6 +4,7 @@
 package local // import "github.com/docker/docker/volume/local"
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -15,10 +16,12 @@ import (
 
 	"github.com/docker/docker/daemon/names"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/volume"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // VolumeDataPathName is the name of the directory where the volume data is stored.
@@ -58,8 +61,16 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 		path:         rootDirectory,
 		volumes:      make(map[string]*localVolume),
 		rootIdentity: rootIdentity,
+		sizeCtl:      newSizeController(rootDirectory),
+		keyProviders: make(map[string]KeyProvider),
 	}
 
+	keyring, err := newFileKeyring(filepath.Join(scope, keyringPathName), rootIdentity)
+	if err != nil {
+		return nil, err
+	}
+	r.keyProviders[DefaultKeyProvider] = keyring
+
 	dirs, err := ioutil.ReadDir(rootDirectory)
 	if err != nil {
 		return nil, err
@@ -88,9 +99,11 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 			if !reflect.DeepEqual(opts, optsConfig{}) {
 				v.opts = &opts
 			}
+			r.loadSize(v)
 
 			// unmount anything that may still be mounted (for example, from an unclean shutdown)
 			mount.Unmount(v.path)
+			r.loadEncryption(v)
 		}
 	}
 
@@ -106,6 +119,10 @@ type Root struct {
 	path         string
 	volumes      map[string]*localVolume
 	rootIdentity idtools.Identity
+	sizeCtl      *sizeController
+
+	keyProvidersMu sync.RWMutex
+	keyProviders   map[string]KeyProvider
 }
 
 // List lists all the volumes
@@ -146,8 +163,8 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 	}
 
 	path := r.DataPath(name)
-	if err := idtools.MkdirAllAndChown(path, 0755, r.rootIdentity); err != nil {
-		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", path)
+	if err := idtools.MkdirAllAndChown(filepath.Dir(path), 0755, r.rootIdentity); err != nil {
+		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", filepath.Dir(path))
 	}
 
 	var err error
@@ -167,6 +184,12 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		if err = setOpts(v, opts); err != nil {
 			return nil, err
 		}
+		if err = r.setupSize(v); err != nil {
+			return nil, err
+		}
+		if err = r.setupEncryption(v); err != nil {
+			return nil, err
+		}
 		var b []byte
 		b, err = json.Marshal(v.opts)
 		if err != nil {
@@ -177,6 +200,12 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		}
 	}
 
+	// The data path is created once the size of the volume is limited, so
+	// that it inherits the project quota of the volume directory.
+	if err = idtools.MkdirAllAndChown(path, 0755, r.rootIdentity); err != nil {
+		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", path)
+	}
+
 	r.volumes[name] = v
 	return v, nil
 }
@@ -219,7 +248,15 @@ func (r *Root) Remove(v volume.Volume) error {
 	}
 
 	delete(r.volumes, lv.name)
-	return removePath(filepath.Dir(lv.path))
+	if err := removePath(filepath.Dir(lv.path)); err != nil {
+		return err
+	}
+	if lv.encryption != nil {
+		if err := lv.encryption.remove(lv.opts); err != nil {
+			logrus.WithError(err).WithField("volume", lv.name).Warn("Error removing key of volume")
+		}
+	}
+	return nil
 }
 
 func removePath(path string) error {
@@ -248,6 +285,16 @@ func (r *Root) Scope() string {
 	return volume.LocalScope
 }
 
+// Capabilities returns the capabilities of the local volume driver.
+func (r *Root) Capabilities() volume.Capability {
+	return volume.Capability{
+		Scope:    volume.LocalScope,
+		Version:  volume.DriverProtocolVersion2,
+		Snapshot: true,
+		Usage:    true,
+	}
+}
+
 func (r *Root) validateName(name string) error {
 	if len(name) == 1 {
 		return errdefs.InvalidParameter(errors.New("volume name is too short, names should be at least two alphanumeric characters"))
@@ -272,6 +319,12 @@ type localVolume struct {
 	opts *optsConfig
 	// active refcounts the active mounts
 	active activeMount
+	// size is the size limit of the volume, if it was created with the
+	// size option
+	size *sizeLimit
+	// encryption is set if the volume was created with the encryption
+	// option
+	encryption *encryption
 }
 
 // Name returns the name of the given Volume.
@@ -339,13 +392,36 @@ func (v *localVolume) unmount() error {
 				return errdefs.System(err)
 			}
 		}
+		if v.encryption != nil {
+			if err := v.encryption.close(); err != nil {
+				return errdefs.System(err)
+			}
+		}
 		v.active.mounted = false
 	}
 	return nil
 }
 
 func (v *localVolume) Status() map[string]interface{} {
-	return nil
+	if v.size == nil {
+		return nil
+	}
+	status := map[string]interface{}{"Size": v.size.size}
+	if usage, err := v.size.usage(context.Background(), v.path); err == nil {
+		status["Usage"] = usage
+	}
+	return status
+}
+
+// Usage returns the disk space used by the volume, and its size limit, or 0
+// if its size is not limited.
+func (v *localVolume) Usage(ctx context.Context) (usage int64, limit int64, err error) {
+	if v.size == nil {
+		usage, err = directory.Size(ctx, v.path)
+		return usage, 0, err
+	}
+	usage, err = v.size.usage(ctx, v.path)
+	return usage, int64(v.size.size), err
 }
 
 // getAddress finds out address/hostname from options
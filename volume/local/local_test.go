@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"gotest.tools/skip"
@@ -333,3 +334,100 @@ func TestRelaodNoOpts(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotCloneRestore(t *testing.T) {
+	skip.If(t, runtime.GOOS != "linux", "volume snapshots are only supported on Linux")
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("testing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataFile := filepath.Join(vol.Path(), "data")
+	if err := ioutil.WriteFile(dataFile, []byte("snapshotted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := r.Snapshot(vol, "snap1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Name != "snap1" || snapshot.CreatedAt.IsZero() {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	if _, err := r.Snapshot(vol, "snap1"); !errdefs.IsConflict(err) {
+		t.Fatalf("expected conflict error, got: %v", err)
+	}
+	if _, err := r.Snapshot(vol, "../snap"); !errdefs.IsInvalidParameter(err) {
+		t.Fatalf("expected invalid parameter error, got: %v", err)
+	}
+
+	if err := ioutil.WriteFile(dataFile, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := r.ListSnapshots(vol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "snap1" {
+		t.Fatalf("unexpected snapshots: %+v", snapshots)
+	}
+
+	clone, err := r.Clone(vol, "snap1", "testing-clone", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFileContent(t, filepath.Join(clone.Path(), "data"), "snapshotted")
+	if _, err := r.Clone(vol, "", "testing-clone", nil); !errdefs.IsConflict(err) {
+		t.Fatalf("expected conflict error, got: %v", err)
+	}
+
+	clone, err = r.Clone(vol, "", "testing-clone2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFileContent(t, filepath.Join(clone.Path(), "data"), "changed")
+
+	if err := r.Restore(vol, "snap1"); err != nil {
+		t.Fatal(err)
+	}
+	assertFileContent(t, dataFile, "snapshotted")
+
+	if err := r.RemoveSnapshot(vol, "snap1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RemoveSnapshot(vol, "snap1"); !errdefs.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+	if err := r.Restore(vol, "snap1"); !errdefs.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+	snapshots, err = r.ListSnapshots(vol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("expected no snapshots, got: %+v", snapshots)
+	}
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != expected {
+		t.Fatalf("expected %q in %s, got %q", expected, path, string(b))
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/mount"
+	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +27,11 @@ var (
 		"type":   {}, // specify the filesystem type for mount, e.g. nfs
 		"o":      {}, // generic mount options
 		"device": {}, // device to mount from
+		"size":   {}, // maximum size of the volume data, e.g. 10G
+
+		"encryption":  {}, // encrypt the volume data, e.g. luks
+		"key":         {}, // ID of the key of an encrypted volume
+		"keyprovider": {}, // provider of the key of an encrypted volume
 	}
 	mandatoryOpts = map[string]struct{}{
 		"device": {},
@@ -37,9 +43,29 @@ type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	Size        uint64 `json:",omitempty"`
+
+	Encryption  string `json:",omitempty"`
+	KeyProvider string `json:",omitempty"`
+	KeyID       string `json:",omitempty"`
+	// GeneratedKey is set if the key was created with the volume, and is
+	// removed with it.
+	GeneratedKey bool `json:",omitempty"`
+}
+
+// isMount returns whether the options mount a device on the volume path,
+// rather than storing the data of the volume locally.
+func (o *optsConfig) isMount() bool {
+	return o.MountType != "" || o.MountOpts != "" || o.MountDevice != ""
 }
 
 func (o *optsConfig) String() string {
+	if o.Encryption != "" {
+		return fmt.Sprintf("size='%d' encryption='%s' keyprovider='%s' key='%s'", o.Size, o.Encryption, o.KeyProvider, o.KeyID)
+	}
+	if o.Size > 0 {
+		return fmt.Sprintf("size='%d'", o.Size)
+	}
 	return fmt.Sprintf("type='%s' device='%s' o='%s'", o.MountType, o.MountDevice, o.MountOpts)
 }
 
@@ -71,6 +97,14 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		MountType:   opts["type"],
 		MountOpts:   opts["o"],
 		MountDevice: opts["device"],
+		Encryption:  opts["encryption"],
+		KeyProvider: opts["keyprovider"],
+		KeyID:       opts["key"],
+	}
+	if size, ok := opts["size"]; ok {
+		// validateOpts already checked the size can be parsed
+		s, _ := units.RAMInBytes(size)
+		v.opts.Size = uint64(s)
 	}
 	return nil
 }
@@ -84,6 +118,38 @@ func validateOpts(opts map[string]string) error {
 			return errdefs.InvalidParameter(errors.Errorf("invalid option: %q", opt))
 		}
 	}
+	if encryption, ok := opts["encryption"]; ok {
+		if _, ok := supportedEncryptions[encryption]; !ok {
+			return errdefs.InvalidParameter(errors.Errorf("unsupported encryption: %q", encryption))
+		}
+		// Encrypted volumes are stored in a disk image, whose size
+		// must be known.
+		if _, ok := opts["size"]; !ok {
+			return errdefs.InvalidParameter(errors.New("missing required option for encrypted volumes: \"size\""))
+		}
+	}
+	for _, opt := range []string{"key", "keyprovider"} {
+		if _, ok := opts[opt]; ok && opts["encryption"] == "" {
+			return errdefs.InvalidParameter(errors.Errorf("the %s option is only supported for encrypted volumes", opt))
+		}
+	}
+	if size, ok := opts["size"]; ok {
+		// The size of volumes on other devices is the size of the device,
+		// so the size option can't be combined with mount options.
+		for _, opt := range []string{"type", "o", "device"} {
+			if _, ok := opts[opt]; ok {
+				return errdefs.InvalidParameter(errors.New("the size option can not be combined with mount options"))
+			}
+		}
+		s, err := units.RAMInBytes(size)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid size: %q", size))
+		}
+		if s <= 0 {
+			return errdefs.InvalidParameter(errors.Errorf("invalid size: %q, the size must be greater than zero", size))
+		}
+		return nil
+	}
 	for opt := range mandatoryOpts {
 		if _, ok := opts[opt]; !ok {
 			return errdefs.InvalidParameter(errors.Errorf("missing required option: %q", opt))
@@ -92,7 +158,37 @@ func validateOpts(opts map[string]string) error {
 	return nil
 }
 
+// setupSize limits the size of a new volume, if it was created with the size
+// option.
+func (r *Root) setupSize(v *localVolume) error {
+	// the size of encrypted volumes is the size of their disk image, see
+	// setupEncryption
+	if v.opts == nil || v.opts.Size == 0 || v.opts.Encryption != "" {
+		return nil
+	}
+	size, err := r.sizeCtl.setup(v.path, v.opts.Size, r.rootIdentity)
+	if err != nil {
+		return err
+	}
+	v.size = size
+	return nil
+}
+
+// loadSize loads the size limit of an existing volume.
+func (r *Root) loadSize(v *localVolume) {
+	if v.opts == nil || v.opts.Size == 0 || v.opts.Encryption != "" {
+		return
+	}
+	v.size = r.sizeCtl.load(v.path, v.opts.Size)
+}
+
 func (v *localVolume) mount() error {
+	if v.encryption != nil {
+		return v.encryption.mount(v.path)
+	}
+	if v.size != nil {
+		return v.size.mount(v.path)
+	}
 	if v.opts.MountDevice == "" {
 		return fmt.Errorf("missing device in volume options")
 	}
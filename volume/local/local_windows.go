@@ -16,6 +16,10 @@ import (
 
 type optsConfig struct{}
 
+func (o *optsConfig) isMount() bool {
+	return false
+}
+
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
 func (r *Root) scopedPath(realPath string) bool {
@@ -32,6 +36,12 @@ func setOpts(v *localVolume, opts map[string]string) error {
 	return nil
 }
 
+func (r *Root) setupSize(v *localVolume) error {
+	return nil
+}
+
+func (r *Root) loadSize(v *localVolume) {}
+
 func (v *localVolume) mount() error {
 	return nil
 }
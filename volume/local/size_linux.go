@@ -0,0 +1,176 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/docker/docker/daemon/graphdriver/quota"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/directory"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/loopback"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// diskImageName is the name of the file in a volume's directory holding the
+// filesystem of volumes whose size is limited with a loopback device.
+const diskImageName = "disk.img"
+
+// sizeController limits the size of volumes. It uses project quotas if the
+// filesystem of the volumes root supports them (XFS, and ext4 mounted with
+// prjquota), and falls back to a size-limited filesystem in a loopback
+// device otherwise.
+type sizeController struct {
+	quotaCtl *quota.Control
+}
+
+func newSizeController(rootDirectory string) *sizeController {
+	// The quota package allocates project ids per filesystem, so volumes do
+	// not get ids used by the storage driver if it shares the filesystem.
+	quotaCtl, err := quota.NewControl(rootDirectory)
+	if err != nil {
+		logrus.WithError(err).Debug("project quotas are not supported for local volumes, size limited volumes use loopback devices")
+		return &sizeController{}
+	}
+	return &sizeController{quotaCtl: quotaCtl}
+}
+
+// sizeLimit is the size limit of a volume.
+type sizeLimit struct {
+	size uint64
+	// volumeDir is the directory of the volume, which is limited by a
+	// project quota, or holds the disk image.
+	volumeDir string
+	quotaCtl  *quota.Control
+	// diskImage is the path of the disk image holding the filesystem of
+	// the volume, if the size is not limited with a project quota.
+	diskImage string
+}
+
+// setup limits the size of the volume with the given data path. The data path
+// must not exist yet.
+func (c *sizeController) setup(dataPath string, size uint64, rootIdentity idtools.Identity) (*sizeLimit, error) {
+	volumeDir := filepath.Dir(dataPath)
+	if c.quotaCtl != nil {
+		// Set the quota on the directory of the volume rather than on its
+		// data, so that the quota is found again when the daemon restarts.
+		// The data path inherits the project of the directory when it is
+		// created.
+		if err := c.quotaCtl.SetQuota(volumeDir, quota.Quota{Size: size}); err != nil {
+			return nil, errdefs.System(errors.Wrap(err, "error while setting quota of volume"))
+		}
+		return &sizeLimit{size: size, volumeDir: volumeDir, quotaCtl: c.quotaCtl}, nil
+	}
+
+	l := &sizeLimit{size: size, volumeDir: volumeDir, diskImage: filepath.Join(volumeDir, diskImageName)}
+	if err := createDiskImage(l.diskImage, size, rootIdentity); err != nil {
+		os.Remove(l.diskImage)
+		return nil, err
+	}
+	return l, nil
+}
+
+// load returns the size limit of the existing volume with the given data
+// path.
+func (c *sizeController) load(dataPath string, size uint64) *sizeLimit {
+	volumeDir := filepath.Dir(dataPath)
+	l := &sizeLimit{size: size, volumeDir: volumeDir}
+	if diskImage := filepath.Join(volumeDir, diskImageName); fileExists(diskImage) {
+		l.diskImage = diskImage
+	} else {
+		l.quotaCtl = c.quotaCtl
+	}
+	return l
+}
+
+// createDiskImage creates a sparse file of the given size holding an ext4
+// filesystem, whose root is owned by rootIdentity.
+func createDiskImage(path string, size uint64, rootIdentity idtools.Identity) error {
+	if err := createSparseFile(path, size); err != nil {
+		return err
+	}
+	return mkfsExt4(path, rootIdentity)
+}
+
+// createSparseFile creates a sparse file of the given size.
+func createSparseFile(path string, size uint64) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errdefs.System(err)
+	}
+	err = f.Truncate(int64(size))
+	f.Close()
+	if err != nil {
+		return errdefs.System(errors.Wrap(err, "error while creating disk image of volume"))
+	}
+	return nil
+}
+
+// mkfsExt4 creates an ext4 filesystem, whose root is owned by rootIdentity, on
+// the given file or device.
+func mkfsExt4(path string, rootIdentity idtools.Identity) error {
+	args := []string{"-q", "-F", "-m", "0", "-E", fmt.Sprintf("root_owner=%d:%d", rootIdentity.UID, rootIdentity.GID), path}
+	if out, err := exec.Command("mkfs.ext4", args...).CombinedOutput(); err != nil {
+		return errdefs.System(errors.Wrapf(err, "error while creating filesystem of volume: %s", out))
+	}
+	return nil
+}
+
+// needsMount returns whether the data of the volume lives on a filesystem
+// that needs to be mounted before the volume can be used.
+func (l *sizeLimit) needsMount() bool {
+	return l.diskImage != ""
+}
+
+// mount mounts the filesystem of the volume on target, if the volume has one.
+func (l *sizeLimit) mount(target string) error {
+	if !l.needsMount() {
+		return nil
+	}
+	loopFile, err := loopback.AttachLoopDevice(l.diskImage)
+	if err != nil {
+		return errors.Wrap(err, "failed to attach disk image of volume")
+	}
+	// The loopback device is detached automatically once it is unmounted.
+	defer loopFile.Close()
+
+	if err := mount.Mount(loopFile.Name(), target, "ext4", ""); err != nil {
+		return errors.Wrap(err, "failed to mount disk image of volume")
+	}
+	removeLostAndFound(target)
+	return nil
+}
+
+// removeLostAndFound removes the lost+found directory created by mkfs from a
+// freshly mounted filesystem, as it is just confusing in a volume. It is kept
+// if it is not empty.
+func removeLostAndFound(target string) {
+	if err := os.Remove(filepath.Join(target, "lost+found")); err != nil && !os.IsNotExist(err) {
+		logrus.WithError(err).WithField("path", target).Debug("failed to remove lost+found directory from volume")
+	}
+}
+
+// usage returns the disk space used by the volume with the given data path.
+func (l *sizeLimit) usage(ctx context.Context, dataPath string) (int64, error) {
+	if !l.needsMount() {
+		return directory.Size(ctx, dataPath)
+	}
+	// The data path is empty if the volume is not mounted; use the disk
+	// space allocated to the (sparse) disk image instead.
+	var st unix.Stat_t
+	if err := unix.Stat(l.diskImage, &st); err != nil {
+		return 0, err
+	}
+	return st.Blocks * 512, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
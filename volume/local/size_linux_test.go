@@ -0,0 +1,208 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"golang.org/x/sys/unix"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestValidateSizeOpts(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{"size": "foo"},
+		{"size": "0"},
+		{"size": "-1G"},
+		{"size": "10M", "type": "tmpfs", "device": "tmpfs"},
+		{"size": "10M", "o": "uid=1000"},
+	} {
+		err := validateOpts(opts)
+		assert.Check(t, errdefs.IsInvalidParameter(err), "options: %v, error: %v", opts, err)
+	}
+
+	assert.Check(t, validateOpts(map[string]string{"size": "10M"}))
+
+	v := &localVolume{}
+	assert.NilError(t, setOpts(v, map[string]string{"size": "10M"}))
+	assert.Check(t, is.Equal(v.opts.Size, uint64(10*1024*1024)))
+}
+
+func TestCreateWithSize(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		t.Skip("requires mkfs.ext4")
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	rootIdentity := idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()}
+	r, err := New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+
+	const size = 16 * 1024 * 1024
+	vol, err := r.Create("test", map[string]string{"size": "16M"})
+	assert.NilError(t, err)
+	v := vol.(*localVolume)
+	assert.Assert(t, v.size != nil)
+
+	dir, err := v.Mount("1234")
+	assert.NilError(t, err)
+
+	// writing more data than the size limit fails
+	data := make([]byte, 2*size)
+	err = ioutil.WriteFile(filepath.Join(dir, "data"), data, 0644)
+	assert.Check(t, err != nil, "expected writing more than the volume size to fail")
+
+	usage, limit, err := v.Usage(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(limit, int64(size)))
+	assert.Check(t, usage > 0)
+	assert.Check(t, usage <= size, "usage %d exceeds size %d", usage, size)
+
+	status := v.Status()
+	assert.Check(t, is.Equal(status["Size"], uint64(size)))
+
+	assert.NilError(t, v.Unmount("1234"))
+
+	// the size limit is restored when the daemon restarts
+	r, err = New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+	v2, exists := r.volumes["test"]
+	assert.Assert(t, exists)
+	assert.Assert(t, v2.size != nil)
+	assert.Check(t, is.Equal(v2.size.size, uint64(size)))
+	assert.Check(t, is.Equal(v2.size.needsMount(), v.size.needsMount()))
+
+	assert.NilError(t, r.Remove(v2))
+	_, err = os.Stat(filepath.Dir(v2.path))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestCreateWithSizeQuota(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		t.Skip("requires mkfs.ext4")
+	}
+
+	tmpDir, err := ioutil.TempDir("", "local-volume-quota-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// create a filesystem with project quotas enabled
+	image := filepath.Join(tmpDir, "image")
+	assert.NilError(t, createSparseFile(image, 64*1024*1024))
+	if out, err := exec.Command("mkfs.ext4", "-q", "-F", "-O", "quota,project", image).CombinedOutput(); err != nil {
+		t.Skipf("mkfs.ext4 does not support project quotas: %v: %s", err, out)
+	}
+	rootDir := filepath.Join(tmpDir, "volumes")
+	assert.NilError(t, os.Mkdir(rootDir, 0755))
+	if out, err := exec.Command("mount", "-o", "loop,prjquota", image, rootDir).CombinedOutput(); err != nil {
+		t.Skipf("project quotas are not supported: %v: %s", err, out)
+	}
+	defer unix.Unmount(rootDir, unix.MNT_DETACH)
+
+	rootIdentity := idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()}
+	r, err := New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+	skip.If(t, r.sizeCtl.quotaCtl == nil, "project quotas are not supported")
+
+	const size = 1024 * 1024
+	vol, err := r.Create("test", map[string]string{"size": "1M"})
+	assert.NilError(t, err)
+	v := vol.(*localVolume)
+	assert.Assert(t, v.size != nil)
+	assert.Check(t, !v.size.needsMount())
+
+	dir, err := v.Mount("1234")
+	assert.NilError(t, err)
+	defer v.Unmount("1234")
+
+	// writing more data than the size limit fails
+	err = writeFileSync(filepath.Join(dir, "data"), make([]byte, 2*size))
+	assert.Check(t, isQuotaExceeded(err), "expected the quota to be exceeded, got %v", err)
+
+	// the quota still applies when the daemon restarts
+	r, err = New(rootDir, rootIdentity)
+	assert.NilError(t, err)
+	v2, exists := r.volumes["test"]
+	assert.Assert(t, exists)
+	assert.Assert(t, v2.size != nil)
+	assert.Check(t, v2.size.quotaCtl != nil)
+	err = writeFileSync(filepath.Join(v2.path, "data2"), make([]byte, 2*size))
+	assert.Check(t, isQuotaExceeded(err), "expected the quota to be exceeded, got %v", err)
+}
+
+func TestSnapshotWithSize(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		t.Skip("requires mkfs.ext4")
+	}
+
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+
+	vol, err := r.Create("test", map[string]string{"size": "16M"})
+	assert.NilError(t, err)
+
+	// the data of the volume is only on its disk image once unmounted
+	dir, err := vol.Mount("1234")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "data"), []byte("snapshotted"), 0644))
+	assert.NilError(t, vol.Unmount("1234"))
+
+	_, err = r.Snapshot(vol, "snap1")
+	assert.NilError(t, err)
+
+	dir, err = vol.Mount("1234")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "data"), []byte("changed"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "new"), []byte("new"), 0644))
+	assert.NilError(t, vol.Unmount("1234"))
+
+	clone, err := r.Clone(vol, "", "test-clone", nil)
+	assert.NilError(t, err)
+	assertFileContent(t, filepath.Join(clone.Path(), "data"), "changed")
+
+	assert.NilError(t, r.Restore(vol, "snap1"))
+	dir, err = vol.Mount("1234")
+	assert.NilError(t, err)
+	defer vol.Unmount("1234")
+	assertFileContent(t, filepath.Join(dir, "data"), "snapshotted")
+	_, err = os.Stat(filepath.Join(dir, "new"))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+// writeFileSync writes data to the file with the given name, and flushes it
+// to disk, so that quotas are enforced.
+func writeFileSync(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func isQuotaExceeded(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+	return err == unix.EDQUOT || err == unix.ENOSPC
+}
@@ -0,0 +1,37 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"context"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
+
+type sizeController struct{}
+
+func newSizeController(rootDirectory string) *sizeController {
+	return &sizeController{}
+}
+
+type sizeLimit struct {
+	size uint64
+}
+
+func (c *sizeController) setup(dataPath string, size uint64, rootIdentity idtools.Identity) (*sizeLimit, error) {
+	return nil, errdefs.NotImplemented(errors.New("size limits for local volumes are not supported on this platform"))
+}
+
+func (c *sizeController) load(dataPath string, size uint64) *sizeLimit {
+	return &sizeLimit{size: size}
+}
+
+func (l *sizeLimit) mount(target string) error {
+	return nil
+}
+
+func (l *sizeLimit) usage(ctx context.Context, dataPath string) (int64, error) {
+	return 0, errdefs.NotImplemented(errors.New("size limits for local volumes are not supported on this platform"))
+}
@@ -0,0 +1,337 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// snapshotsPathName is the name of the directory in a volume's directory
+// where its snapshots are stored. Each snapshot is a directory holding a copy
+// of the volume data in VolumeDataPathName, and its metadata.
+const (
+	snapshotsPathName    = "_snapshots"
+	snapshotMetaFileName = "snapshot.json"
+)
+
+type snapshotMeta struct {
+	CreatedAt time.Time
+}
+
+func (r *Root) snapshotsPath(volumeName string) string {
+	return filepath.Join(r.path, volumeName, snapshotsPathName)
+}
+
+func (r *Root) snapshotPath(volumeName, name string) string {
+	return filepath.Join(r.snapshotsPath(volumeName), name)
+}
+
+// snapshotDataPath returns the path of the data of the given snapshot, or of
+// the volume itself if name is empty. An error is returned if the snapshot
+// does not exist.
+func (r *Root) snapshotDataPath(lv *localVolume, name string) (string, error) {
+	if name == "" {
+		return lv.path, nil
+	}
+	if err := validateSnapshotName(name); err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.snapshotPath(lv.name, name), VolumeDataPathName)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", errdefs.NotFound(errors.Errorf("volume %s has no snapshot %s", lv.name, name))
+		}
+		return "", errdefs.System(err)
+	}
+	return path, nil
+}
+
+func validateSnapshotName(name string) error {
+	if !volumeNameRegex.MatchString(name) {
+		return errdefs.InvalidParameter(errors.Errorf("%q includes invalid characters for a snapshot name, only %q are allowed", name, names.RestrictedNameChars))
+	}
+	return nil
+}
+
+// snapshotVolume returns the local volume for v, if it can be snapshotted.
+func (r *Root) snapshotVolume(v volume.Volume) (*localVolume, error) {
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+	if lv.opts != nil && lv.opts.isMount() {
+		// The data of these volumes lives on the mounted device, and
+		// is typically not local at all.
+		return nil, errdefs.NotImplemented(errors.Errorf("volume %s has mount options, snapshots are only supported for volumes without mount options", lv.name))
+	}
+	if lv.encryption != nil {
+		// Snapshots are not encrypted, they would hold a plain copy of
+		// the data of the volume.
+		return nil, errdefs.NotImplemented(errors.Errorf("volume %s is encrypted, snapshots are not supported for encrypted volumes", lv.name))
+	}
+	return lv, nil
+}
+
+// withData runs fn with the data of the volume available at its path. The
+// filesystem of sized volumes is mounted for the duration of fn, if the
+// volume is not in use. The volume must be locked.
+func (lv *localVolume) withData(fn func() error) error {
+	if lv.opts == nil || lv.active.mounted {
+		return fn()
+	}
+	if err := lv.mount(); err != nil {
+		return errdefs.System(err)
+	}
+	err := fn()
+	if uErr := lv.unmount(); uErr != nil {
+		if err == nil {
+			return uErr
+		}
+		logrus.WithError(uErr).WithField("volume", lv.name).Warn("failed to unmount volume")
+	}
+	return err
+}
+
+// Snapshot copies the data of the volume to a new snapshot with the given
+// name. Files are cloned instead of copied where the backing filesystem
+// supports it (reflinks, e.g. on btrfs and xfs).
+func (r *Root) Snapshot(v volume.Volume, name string) (volume.Snapshot, error) {
+	lv, err := r.snapshotVolume(v)
+	if err != nil {
+		return volume.Snapshot{}, err
+	}
+	if err := validateSnapshotName(name); err != nil {
+		return volume.Snapshot{}, err
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	path := r.snapshotPath(lv.name, name)
+	if _, err := os.Stat(path); err == nil {
+		return volume.Snapshot{}, errdefs.Conflict(errors.Errorf("volume %s already has a snapshot %s", lv.name, name))
+	}
+	if err := os.MkdirAll(r.snapshotsPath(lv.name), 0700); err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+
+	// Snapshot names can't start with a '.', so the temporary directory is
+	// never mistaken for a snapshot.
+	tmpPath, err := ioutil.TempDir(r.snapshotsPath(lv.name), "."+name+"-")
+	if err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	defer os.RemoveAll(tmpPath)
+
+	if err := lv.withData(func() error {
+		return copyDir(lv.path, filepath.Join(tmpPath, VolumeDataPathName))
+	}); err != nil {
+		return volume.Snapshot{}, errors.Wrapf(err, "error while copying data of volume %s", lv.name)
+	}
+
+	meta := snapshotMeta{CreatedAt: time.Now().UTC()}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return volume.Snapshot{}, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpPath, snapshotMetaFileName), b, 0600); err != nil {
+		return volume.Snapshot{}, errdefs.System(errors.Wrap(err, "error while persisting snapshot metadata"))
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return volume.Snapshot{}, errdefs.System(err)
+	}
+	return volume.Snapshot{Name: name, CreatedAt: meta.CreatedAt}, nil
+}
+
+// ListSnapshots returns the snapshots of the volume, oldest first.
+func (r *Root) ListSnapshots(v volume.Volume) ([]volume.Snapshot, error) {
+	lv, err := r.snapshotVolume(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := ioutil.ReadDir(r.snapshotsPath(lv.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errdefs.System(err)
+	}
+
+	var snapshots []volume.Snapshot
+	for _, d := range dirs {
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(r.snapshotsPath(lv.name), d.Name(), snapshotMetaFileName))
+		if err != nil {
+			continue
+		}
+		var meta snapshotMeta
+		if err := json.Unmarshal(b, &meta); err != nil {
+			return nil, errors.Wrapf(err, "error while unmarshaling metadata of snapshot %s of volume %s", d.Name(), lv.name)
+		}
+		snapshots = append(snapshots, volume.Snapshot{Name: d.Name(), CreatedAt: meta.CreatedAt})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// RemoveSnapshot removes the snapshot with the given name of the volume.
+func (r *Root) RemoveSnapshot(v volume.Volume, name string) error {
+	lv, err := r.snapshotVolume(v)
+	if err != nil {
+		return err
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	if _, err := r.snapshotDataPath(lv, name); err != nil {
+		return err
+	}
+	return removePath(r.snapshotPath(lv.name, name))
+}
+
+// Clone creates a new volume with the given name, holding a copy of the data
+// of the volume, or of its snapshot if snapshot is not empty.
+func (r *Root) Clone(v volume.Volume, snapshot, name string, opts map[string]string) (volume.Volume, error) {
+	lv, err := r.snapshotVolume(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts) != 0 {
+		return nil, errdefs.InvalidParameter(errors.New("cloned volumes can not have mount options"))
+	}
+	if err := r.validateName(name); err != nil {
+		return nil, err
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	src, err := r.snapshotDataPath(lv, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Get(name); err == nil {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+	clone, err := r.Create(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := lv.withData(func() error { return copyDir(src, clone.Path()) }); err != nil {
+		if rmErr := r.Remove(clone); rmErr != nil {
+			err = errors.Wrapf(err, "error while removing volume %s: %v", name, rmErr)
+		}
+		return nil, errors.Wrapf(err, "error while copying data of volume %s", lv.name)
+	}
+	return clone, nil
+}
+
+// Restore replaces the data of the volume with the data of the snapshot. The
+// current data is only removed once the snapshot has been copied, so it is
+// kept if the copy fails. Callers must make sure the volume is not in use.
+func (r *Root) Restore(v volume.Volume, snapshot string) error {
+	lv, err := r.snapshotVolume(v)
+	if err != nil {
+		return err
+	}
+	if snapshot == "" {
+		return errdefs.InvalidParameter(errors.New("no snapshot to restore specified"))
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	src, err := r.snapshotDataPath(lv, snapshot)
+	if err != nil {
+		return err
+	}
+
+	if lv.opts != nil {
+		// The path of sized volumes may be the mount point of their
+		// filesystem, so their data is replaced in place.
+		return lv.withData(func() error {
+			if err := replaceContents(src, lv.path); err != nil {
+				return errors.Wrapf(err, "error while copying data of snapshot %s", snapshot)
+			}
+			return nil
+		})
+	}
+
+	restorePath := lv.path + ".restore"
+	oldPath := lv.path + ".old"
+	for _, p := range []string{restorePath, oldPath} {
+		if err := removePath(p); err != nil {
+			return err
+		}
+	}
+	if err := copyDir(src, restorePath); err != nil {
+		os.RemoveAll(restorePath)
+		return errors.Wrapf(err, "error while copying data of snapshot %s", snapshot)
+	}
+	if err := os.Rename(lv.path, oldPath); err != nil {
+		os.RemoveAll(restorePath)
+		return errdefs.System(err)
+	}
+	if err := os.Rename(restorePath, lv.path); err != nil {
+		// put the original data back
+		if rErr := os.Rename(oldPath, lv.path); rErr != nil {
+			err = errors.Wrapf(err, "error while restoring original data of volume: %v", rErr)
+		}
+		return errdefs.System(err)
+	}
+	return removePath(oldPath)
+}
+
+// replaceContents replaces the contents of the directory dst with a copy of
+// the contents of src. The current contents are only removed once src has
+// been copied.
+func replaceContents(src, dst string) error {
+	tmpPath, err := ioutil.TempDir(dst, ".restore-")
+	if err != nil {
+		return errdefs.System(err)
+	}
+	defer os.RemoveAll(tmpPath)
+
+	if err := copyDir(src, tmpPath); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(dst)
+	if err != nil {
+		return errdefs.System(err)
+	}
+	for _, e := range entries {
+		if p := filepath.Join(dst, e.Name()); p != tmpPath {
+			if err := os.RemoveAll(p); err != nil {
+				return errdefs.System(err)
+			}
+		}
+	}
+	entries, err = ioutil.ReadDir(tmpPath)
+	if err != nil {
+		return errdefs.System(err)
+	}
+	for _, e := range entries {
+		if err := os.Rename(filepath.Join(tmpPath, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return errdefs.System(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/copy"
+)
+
+// copyDir copies the directory src to dst. Files are cloned if the backing
+// filesystem supports it, and copied otherwise.
+func copyDir(src, dst string) error {
+	return copy.DirCopy(src, dst, copy.Content, true)
+}
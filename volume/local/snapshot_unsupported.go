@@ -0,0 +1,12 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+func copyDir(src, dst string) error {
+	return errdefs.NotImplemented(errors.New("volume snapshots are not supported on this platform"))
+}
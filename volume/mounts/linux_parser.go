@@ -99,6 +99,23 @@ func (p *linuxParser) validateMountConfigImpl(mnt *mount.Mount, validateBindSour
 		if len(mnt.Source) == 0 && mnt.ReadOnly {
 			return &errMountConfig{mnt, fmt.Errorf("must not set ReadOnly mode when using anonymous volumes")}
 		}
+
+		if err := validateVolumeSubpath(mnt); err != nil {
+			return err
+		}
+	case mount.TypeImage:
+		if len(mnt.Source) == 0 {
+			return &errMountConfig{mnt, errMissingField("Source")}
+		}
+		if mnt.BindOptions != nil {
+			return &errMountConfig{mnt, errExtraField("BindOptions")}
+		}
+		if mnt.VolumeOptions != nil {
+			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
+		}
+		if mnt.TmpfsOptions != nil {
+			return &errMountConfig{mnt, errExtraField("TmpfsOptions")}
+		}
 	case mount.TypeTmpfs:
 		if mnt.BindOptions != nil {
 			return &errMountConfig{mnt, errExtraField("BindOptions")}
@@ -308,7 +325,9 @@ func (p *linuxParser) parseMountSpec(cfg mount.Mount, validateBindSourceExists b
 			if cfg.VolumeOptions.DriverConfig != nil {
 				mp.Driver = cfg.VolumeOptions.DriverConfig.Name
 			}
-			if cfg.VolumeOptions.NoCopy {
+			// Data is not copied to mounts of a subpath of the volume,
+			// as it may be shared with other containers.
+			if cfg.VolumeOptions.NoCopy || cfg.VolumeOptions.Subpath != "" {
 				mp.CopyData = false
 			}
 		}
@@ -321,6 +340,11 @@ func (p *linuxParser) parseMountSpec(cfg mount.Mount, validateBindSourceExists b
 			// default propagation mode.
 			mp.Propagation = linuxDefaultPropagationMode
 		}
+	case mount.TypeImage:
+		// Images are always mounted read-only. The image reference is
+		// resolved to an image ID by the daemon.
+		mp.RW = false
+		mp.Name = cfg.Source
 	case mount.TypeTmpfs:
 		// NOP
 	}
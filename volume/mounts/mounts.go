@@ -7,11 +7,14 @@ import (
 	"syscall"
 
 	mounttypes "github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/volume"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // MountPoint is the intersection point between a volume and a container. It
@@ -72,6 +75,10 @@ type MountPoint struct {
 	// Specifically needed for containers which are running and calls to `docker cp`
 	// because both these actions require mounting the volumes.
 	active int
+
+	// subpathMount is the mount point of the subpath of the volume, while
+	// the mount point is active.
+	subpathMount string
 }
 
 // Cleanup frees resources used by the mountpoint
@@ -80,6 +87,13 @@ func (m *MountPoint) Cleanup() error {
 		return nil
 	}
 
+	if m.active <= 1 && m.subpathMount != "" {
+		if err := unmountSubpath(m.subpathMount); err != nil {
+			return errors.Wrapf(err, "error unmounting subpath of volume %s", m.Volume.Name())
+		}
+		m.subpathMount = ""
+	}
+
 	if err := m.Volume.Unmount(m.ID); err != nil {
 		return errors.Wrapf(err, "error unmounting volume %s", m.Volume.Name())
 	}
@@ -127,11 +141,37 @@ func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun
 		if id == "" {
 			id = stringid.GenerateRandomID()
 		}
-		path, err := m.Volume.Mount(id)
+		var path string
+		if om, ok := m.Volume.(volume.OptionsMounter); ok {
+			opts := volume.MountOptions{ReadOnly: !m.RW}
+			if m.Spec.VolumeOptions != nil {
+				opts.Subpath = m.Spec.VolumeOptions.Subpath
+			}
+			path, err = om.MountWithOptions(id, opts)
+		} else {
+			path, err = m.Volume.Mount(id)
+		}
 		if err != nil {
 			return "", errors.Wrapf(err, "error while mounting volume '%s'", m.Source)
 		}
 
+		if m.Spec.VolumeOptions != nil && m.Spec.VolumeOptions.Subpath != "" {
+			// The subpath is mounted once for all the active mounts of the
+			// mount point.
+			subpath := m.subpathMount
+			if subpath == "" {
+				subpath, err = mountSubpath(path, m.Spec.VolumeOptions.Subpath)
+			}
+			if err != nil {
+				if uErr := m.Volume.Unmount(id); uErr != nil {
+					logrus.WithError(uErr).WithField("volume", m.Volume.Name()).Warn("error unmounting volume")
+				}
+				return "", errors.Wrapf(err, "error while mounting volume '%s'", m.Source)
+			}
+			m.subpathMount = subpath
+			path = subpath
+		}
+
 		m.ID = id
 		m.active++
 		return path, nil
@@ -164,6 +204,23 @@ func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun
 	return m.Source, nil
 }
 
+// resolveSubpath resolves the given subpath in the volume mounted at
+// volumePath. Symlinks are resolved in the scope of the volume, so the
+// resolved path can't be outside of the volume. The subpath must exist.
+func resolveSubpath(volumePath, subpath string) (string, error) {
+	resolved, err := symlink.FollowSymlinkInScope(filepath.Join(volumePath, filepath.FromSlash(subpath)), volumePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving subpath %q", subpath)
+	}
+	if _, err := os.Stat(resolved); err != nil {
+		if os.IsNotExist(err) {
+			return "", errdefs.InvalidParameter(errors.Errorf("subpath %q does not exist in volume", subpath))
+		}
+		return "", errors.Wrapf(err, "error resolving subpath %q", subpath)
+	}
+	return resolved, nil
+}
+
 // Path returns the path of a volume in a mount point.
 func (m *MountPoint) Path() string {
 	if m.Volume != nil {
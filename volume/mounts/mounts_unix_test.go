@@ -0,0 +1,71 @@
+// +build !windows
+
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestResolveSubpath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-resolve-subpath")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	volumePath := filepath.Join(dir, "volume")
+	assert.NilError(t, os.MkdirAll(filepath.Join(volumePath, "foo", "bar"), 0755))
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, "outside"), 0755))
+	assert.NilError(t, os.Symlink("foo/bar", filepath.Join(volumePath, "link")))
+	assert.NilError(t, os.Symlink("../outside", filepath.Join(volumePath, "escape")))
+	assert.NilError(t, os.Symlink("/", filepath.Join(volumePath, "root")))
+
+	path, err := resolveSubpath(volumePath, "foo/bar")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, filepath.Join(volumePath, "foo", "bar")))
+
+	path, err = resolveSubpath(volumePath, "link")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, filepath.Join(volumePath, "foo", "bar")))
+
+	// symlinks pointing outside of the volume are resolved in the scope of
+	// the volume
+	_, err = resolveSubpath(volumePath, "escape")
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	path, err = resolveSubpath(volumePath, "root")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(path, volumePath))
+
+	_, err = resolveSubpath(volumePath, "missing")
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+}
+
+func TestParseImageMountSpec(t *testing.T) {
+	parser := NewParser("linux")
+
+	for _, m := range []mount.Mount{
+		{Type: mount.TypeImage, Target: "/foo"},
+		{Type: mount.TypeImage, Target: "/foo", Source: "busybox", BindOptions: &mount.BindOptions{}},
+		{Type: mount.TypeImage, Target: "/foo", Source: "busybox", VolumeOptions: &mount.VolumeOptions{}},
+		{Type: mount.TypeImage, Target: "/foo", Source: "busybox", TmpfsOptions: &mount.TmpfsOptions{}},
+	} {
+		_, err := parser.ParseMountSpec(m)
+		assert.Check(t, err != nil, "mount: %+v", m)
+	}
+
+	mp, err := parser.ParseMountSpec(mount.Mount{Type: mount.TypeImage, Target: "/foo/", Source: "busybox:latest"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mp.Type, mount.TypeImage))
+	assert.Check(t, is.Equal(mp.Destination, "/foo"))
+	assert.Check(t, is.Equal(mp.Name, "busybox:latest"))
+	// image mounts are always read-only
+	assert.Check(t, !mp.RW)
+	assert.Check(t, !mp.CopyData)
+}
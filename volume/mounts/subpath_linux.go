@@ -0,0 +1,85 @@
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// mountSubpath resolves the subpath in the volume mounted at volumePath, and
+// bind mounts it on a new mount point, which is returned. The resolved path
+// is opened, checked to still be inside of the volume, and mounted through
+// its file descriptor. A container sharing the volume therefore can't swap a
+// component of the path for a symlink to a path outside of the volume between
+// the resolution and the bind mount of the container.
+func mountSubpath(volumePath, subpath string) (string, error) {
+	resolved, err := resolveSubpath(volumePath, subpath)
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.EvalSymlinks(volumePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving subpath %q", subpath)
+	}
+
+	fd, err := unix.Open(resolved, unix.O_PATH|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return "", errors.Wrapf(&os.PathError{Op: "open", Path: resolved, Err: err}, "error opening subpath %q", subpath)
+	}
+	defer unix.Close(fd)
+
+	fdPath := "/proc/self/fd/" + strconv.Itoa(fd)
+	opened, err := os.Readlink(fdPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error opening subpath %q", subpath)
+	}
+	if opened != root && !strings.HasPrefix(opened, root+string(filepath.Separator)) {
+		return "", errdefs.InvalidParameter(errors.Errorf("subpath %q is not inside of the volume", subpath))
+	}
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return "", errors.Wrapf(err, "error opening subpath %q", subpath)
+	}
+	if st.Mode&unix.S_IFMT == unix.S_IFLNK {
+		return "", errdefs.InvalidParameter(errors.Errorf("subpath %q was replaced by a symlink", subpath))
+	}
+
+	dir, err := ioutil.TempDir("", "subpath-")
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(dir, "mount")
+	if st.Mode&unix.S_IFMT == unix.S_IFDIR {
+		err = os.Mkdir(target, 0700)
+	} else {
+		err = ioutil.WriteFile(target, nil, 0600)
+	}
+	if err == nil {
+		err = unix.Mount(fdPath, target, "", unix.MS_BIND, "")
+	}
+	if err != nil {
+		os.Remove(target)
+		os.Remove(dir)
+		return "", errors.Wrapf(err, "error mounting subpath %q", subpath)
+	}
+	return target, nil
+}
+
+// unmountSubpath unmounts a subpath mounted by mountSubpath, and removes its
+// mount point.
+func unmountSubpath(target string) error {
+	if err := unix.Unmount(target, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+		return errors.Wrapf(&os.PathError{Op: "unmount", Path: target, Err: err}, "error unmounting subpath")
+	}
+	// The mount point is not removed recursively, in case it is still
+	// mounted.
+	os.Remove(target)
+	os.Remove(filepath.Dir(target))
+	return nil
+}
@@ -0,0 +1,60 @@
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/mount"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestMountSubpath(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	dir, err := ioutil.TempDir("", "test-mount-subpath")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	volumePath := filepath.Join(dir, "volume")
+	assert.NilError(t, os.MkdirAll(filepath.Join(volumePath, "foo", "bar"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(volumePath, "file"), []byte("data"), 0644))
+	outside := filepath.Join(dir, "outside")
+	assert.NilError(t, os.MkdirAll(outside, 0755))
+
+	target, err := mountSubpath(volumePath, "foo/bar")
+	assert.NilError(t, err)
+	mounted, err := mount.Mounted(target)
+	assert.NilError(t, err)
+	assert.Check(t, mounted)
+
+	// replacing the subpath with a symlink to a path outside of the volume
+	// doesn't change the mounted directory
+	assert.NilError(t, os.Rename(filepath.Join(volumePath, "foo", "bar"), filepath.Join(volumePath, "foo", "old")))
+	assert.NilError(t, os.Symlink(outside, filepath.Join(volumePath, "foo", "bar")))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(target, "written"), nil, 0644))
+	_, err = os.Stat(filepath.Join(volumePath, "foo", "old", "written"))
+	assert.Check(t, err)
+	_, err = os.Stat(filepath.Join(outside, "written"))
+	assert.Check(t, os.IsNotExist(err))
+
+	assert.NilError(t, unmountSubpath(target))
+	_, err = os.Stat(filepath.Dir(target))
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(volumePath, "foo", "old", "written"))
+	assert.Check(t, err)
+
+	// files can be mounted too
+	target, err = mountSubpath(volumePath, "file")
+	assert.NilError(t, err)
+	data, err := ioutil.ReadFile(target)
+	assert.Check(t, err)
+	assert.Check(t, is.Equal(string(data), "data"))
+	assert.NilError(t, unmountSubpath(target))
+
+	// the symlink is resolved in the scope of the volume
+	_, err = mountSubpath(volumePath, "foo/bar")
+	assert.Check(t, is.ErrorContains(err, "does not exist in volume"))
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+// mountSubpath resolves the subpath in the volume mounted at volumePath.
+func mountSubpath(volumePath, subpath string) (string, error) {
+	return resolveSubpath(volumePath, subpath)
+}
+
+func unmountSubpath(target string) error {
+	return nil
+}
@@ -2,6 +2,8 @@ package mounts // import "github.com/docker/docker/volume/mounts"
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/docker/docker/api/types/mount"
 	"github.com/pkg/errors"
@@ -16,6 +18,25 @@ func (e *errMountConfig) Error() string {
 	return fmt.Sprintf("invalid mount config for type %q: %v", e.mount.Type, e.err.Error())
 }
 
+// validateVolumeSubpath checks that the subpath of a volume mount is a
+// relative path that does not refer to a path outside of the volume.
+func validateVolumeSubpath(mnt *mount.Mount) error {
+	if mnt.VolumeOptions == nil || mnt.VolumeOptions.Subpath == "" {
+		return nil
+	}
+	if len(mnt.Source) == 0 {
+		return &errMountConfig{mnt, errors.New("must not set Subpath when using anonymous volumes")}
+	}
+	subpath := filepath.FromSlash(mnt.VolumeOptions.Subpath)
+	if filepath.IsAbs(subpath) || filepath.VolumeName(subpath) != "" || strings.HasPrefix(subpath, string(filepath.Separator)) {
+		return &errMountConfig{mnt, errors.Errorf("subpath must be a relative path: %s", mnt.VolumeOptions.Subpath)}
+	}
+	if cleaned := filepath.Clean(subpath); cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return &errMountConfig{mnt, errors.Errorf("subpath must not refer to a path outside of the volume: %s", mnt.VolumeOptions.Subpath)}
+	}
+	return nil
+}
+
 func errBindSourceDoesNotExist(path string) error {
 	return errors.Errorf("bind source path does not exist: %s", path)
 }
@@ -267,6 +267,10 @@ func (p *windowsParser) validateMountConfigReg(mnt *mount.Mount, destRegex strin
 			return &errMountConfig{mnt, fmt.Errorf("must not set ReadOnly mode when using anonymous volumes")}
 		}
 
+		if err := validateVolumeSubpath(mnt); err != nil {
+			return err
+		}
+
 		if len(mnt.Source) != 0 {
 			if err := p.ValidateVolumeName(mnt.Source); err != nil {
 				return &errMountConfig{mnt, err}
@@ -395,7 +399,9 @@ func (p *windowsParser) parseMountSpec(cfg mount.Mount, destRegex string, conver
 			if cfg.VolumeOptions.DriverConfig != nil {
 				mp.Driver = cfg.VolumeOptions.DriverConfig.Name
 			}
-			if cfg.VolumeOptions.NoCopy {
+			// Data is not copied to mounts of a subpath of the volume,
+			// as it may be shared with other containers.
+			if cfg.VolumeOptions.NoCopy || cfg.VolumeOptions.Subpath != "" {
 				mp.CopyData = false
 			}
 		}
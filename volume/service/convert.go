@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/volume"
 	"github.com/sirupsen/logrus"
@@ -28,6 +29,23 @@ type pathCacher interface {
 	CachedPath() string
 }
 
+// usageReporter is implemented by volumes that can report their disk usage
+// themselves, such as size limited local volumes, whose data is not
+// necessarily accessible at their path.
+type usageReporter interface {
+	Usage(ctx context.Context) (usage int64, limit int64, err error)
+}
+
+// volumeUsage returns the disk space used by the volume, and its size limit,
+// or 0 if its size is not limited.
+func volumeUsage(ctx context.Context, v volume.Volume) (int64, int64, error) {
+	if ur, ok := unwrapVolume(v).(usageReporter); ok {
+		return ur.Usage(ctx)
+	}
+	sz, err := directory.Size(ctx, v.Path())
+	return sz, 0, err
+}
+
 func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) []*types.Volume {
 	var (
 		out        = make([]*types.Volume, 0, len(volumes))
@@ -50,6 +68,7 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 		default:
 		}
 		apiV := volumeToAPIType(v)
+		s.setUsageTimes(&apiV, v)
 
 		if cachedPath {
 			if vv, ok := v.(pathCacher); ok {
@@ -64,12 +83,12 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 			if apiV.Mountpoint == "" {
 				apiV.Mountpoint = p
 			}
-			sz, err := directory.Size(ctx, p)
+			sz, limit, err := volumeUsage(ctx, v)
 			if err != nil {
 				logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
 				sz = -1
 			}
-			apiV.UsageData = &types.VolumeUsageData{Size: sz, RefCount: int64(s.vs.CountReferences(v))}
+			apiV.UsageData = &types.VolumeUsageData{Size: sz, Limit: limit, RefCount: int64(s.vs.CountReferences(v))}
 		}
 
 		out = append(out, &apiV)
@@ -95,6 +114,71 @@ func volumeToAPIType(v volume.Volume) types.Volume {
 	return tv
 }
 
+// setUsageTimes sets the times the volume was last mounted and unmounted, as
+// recorded by the store. The creation time recorded by the store is used if
+// the driver does not report one.
+func (s *VolumesService) setUsageTimes(apiV *types.Volume, v volume.Volume) {
+	meta, err := s.vs.getMeta(v.Name())
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Debug("Failed to get volume metadata")
+		return
+	}
+	if createdAt, err := v.CreatedAt(); (err != nil || createdAt.IsZero()) && !meta.CreatedAt.IsZero() {
+		apiV.CreatedAt = meta.CreatedAt.Format(time.RFC3339)
+	}
+	if !meta.LastMountedAt.IsZero() {
+		apiV.LastMountedAt = meta.LastMountedAt.Format(time.RFC3339)
+	}
+	if !meta.LastUnmountedAt.IsZero() {
+		apiV.LastUnmountedAt = meta.LastUnmountedAt.Format(time.RFC3339)
+	}
+}
+
+// setDriverCapabilities sets the capabilities of the driver of the volume,
+// and the disk usage of the volume if the driver is a plugin reporting it.
+// The disk usage of local volumes is only calculated by `system df`, as it
+// may require walking the data of the volume.
+func (s *VolumesService) setDriverCapabilities(ctx context.Context, apiV *types.Volume, v volume.Volume) {
+	vd, err := s.vs.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Debug("Failed to get volume driver")
+		return
+	}
+	cd, ok := vd.(volume.CapabilityDriver)
+	if !ok {
+		return
+	}
+	caps := cd.Capabilities()
+	apiV.DriverCapabilities = &types.VolumeDriverCapabilities{
+		ProtocolVersion: int64(caps.Version),
+		Snapshot:        caps.Snapshot,
+		Resize:          caps.Resize,
+		Usage:           caps.Usage,
+		MountOptions:    caps.MountOptions,
+	}
+
+	if !caps.Usage || v.DriverName() == volume.DefaultDriverName {
+		return
+	}
+	ur, ok := unwrapVolume(v).(usageReporter)
+	if !ok {
+		return
+	}
+	sz, limit, err := ur.Usage(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warn("Failed to determine size of volume")
+		return
+	}
+	apiV.UsageData = &types.VolumeUsageData{Size: sz, Limit: limit, RefCount: int64(s.vs.CountReferences(v))}
+}
+
+func snapshotToAPIType(s volume.Snapshot) types.VolumeSnapshot {
+	return types.VolumeSnapshot{
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+	}
+}
+
 func filtersToBy(filter filters.Args, acceptedFilters map[string]bool) (By, error) {
 	if err := filter.Validate(acceptedFilters); err != nil {
 		return nil, err
@@ -130,3 +214,69 @@ func filtersToBy(filter filters.Args, acceptedFilters map[string]bool) (By, erro
 	}
 	return by, nil
 }
+
+// usageFilter returns a filter for the "until" and "unused-for" prune
+// filters, which match volumes created before the given time, and volumes
+// that were not created, mounted, or unmounted for the given duration. Volumes
+// for which this information is not known never match.
+func (s *VolumesService) usageFilter(filter filters.Args) (By, error) {
+	var (
+		until     time.Time
+		unusedFor time.Duration
+	)
+	if filter.Contains("until") {
+		values := filter.Get("until")
+		if len(values) > 1 {
+			return nil, invalidFilter{"until", values}
+		}
+		ts, err := timetypes.GetTimestamp(values[0], time.Now())
+		if err != nil {
+			return nil, invalidFilter{"until", values[0]}
+		}
+		seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return nil, invalidFilter{"until", values[0]}
+		}
+		until = time.Unix(seconds, nanoseconds)
+	}
+	if filter.Contains("unused-for") {
+		values := filter.Get("unused-for")
+		if len(values) > 1 {
+			return nil, invalidFilter{"unused-for", values}
+		}
+		d, err := time.ParseDuration(values[0])
+		if err != nil || d < 0 {
+			return nil, invalidFilter{"unused-for", values[0]}
+		}
+		unusedFor = d
+	}
+	if until.IsZero() && unusedFor == 0 {
+		return nil, nil
+	}
+
+	return CustomFilter(func(v volume.Volume) bool {
+		meta, err := s.vs.getMeta(v.Name())
+		if err != nil {
+			return false
+		}
+		if !until.IsZero() {
+			createdAt := meta.CreatedAt
+			if createdAt.IsZero() {
+				createdAt, _ = v.CreatedAt()
+			}
+			if createdAt.IsZero() || !createdAt.Before(until) {
+				return false
+			}
+		}
+		if unusedFor > 0 {
+			lastUsed := meta.lastUsed()
+			if lastUsed.IsZero() {
+				lastUsed, _ = v.CreatedAt()
+			}
+			if lastUsed.IsZero() || time.Since(lastUsed) < unusedFor {
+				return false
+			}
+		}
+		return true
+	}), nil
+}
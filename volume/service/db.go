@@ -2,6 +2,7 @@ package service // import "github.com/docker/docker/volume/service"
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
@@ -16,6 +17,25 @@ type volumeMetadata struct {
 	Driver  string
 	Labels  map[string]string
 	Options map[string]string
+
+	// CreatedAt, LastMountedAt, and LastUnmountedAt track when the
+	// volume was created and last used. They are zero for volumes that
+	// were created before they were tracked, or not used since.
+	CreatedAt       time.Time
+	LastMountedAt   time.Time
+	LastUnmountedAt time.Time
+}
+
+// lastUsed returns the last time the volume was created, mounted, or
+// unmounted, or the zero time if it is not known.
+func (m volumeMetadata) lastUsed() time.Time {
+	lastUsed := m.CreatedAt
+	for _, t := range []time.Time{m.LastMountedAt, m.LastUnmountedAt} {
+		if t.After(lastUsed) {
+			lastUsed = t
+		}
+	}
+	return lastUsed
 }
 
 func (s *VolumeStore) setMeta(name string, meta volumeMetadata) error {
@@ -36,6 +56,24 @@ func setMeta(tx *bolt.Tx, name string, meta volumeMetadata) error {
 	return errors.Wrap(b.Put([]byte(name), metaJSON), "error setting volume metadata")
 }
 
+// updateMeta updates the stored metadata of the volume with the given name
+// with fn in a single transaction. Nothing is updated if no metadata is
+// stored for the volume, for example because it was removed meanwhile.
+func (s *VolumeStore) updateMeta(name string, fn func(*volumeMetadata)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(volumeBucketName)
+		if b == nil || len(b.Get([]byte(name))) == 0 {
+			return nil
+		}
+		var meta volumeMetadata
+		if err := getMeta(tx, name, &meta); err != nil {
+			return err
+		}
+		fn(&meta)
+		return setMeta(tx, name, meta)
+	})
+}
+
 func (s *VolumeStore) getMeta(name string) (volumeMetadata, error) {
 	var meta volumeMetadata
 	err := s.db.View(func(tx *bolt.Tx) error {
@@ -19,3 +19,27 @@ func setupDefaultDriver(store *drivers.Store, root string, rootIDs idtools.Ident
 	}
 	return nil
 }
+
+// RegisterKeyHelpers registers the key providers of the encrypted volumes of
+// the default driver, which run the helper programs at the given paths, by
+// name.
+func (s *VolumesService) RegisterKeyHelpers(helpers map[string]string) error {
+	if len(helpers) == 0 {
+		return nil
+	}
+	d, err := s.vs.drivers.GetDriver(volume.DefaultDriverName)
+	if err != nil {
+		return err
+	}
+	r, ok := d.(*local.Root)
+	if !ok {
+		return errors.New("local volume driver does not support key providers")
+	}
+	for name, path := range helpers {
+		if name == local.DefaultKeyProvider {
+			return errors.Errorf("key provider %q cannot be replaced", name)
+		}
+		r.RegisterKeyProvider(name, local.NewKeyHelper(path))
+	}
+	return nil
+}
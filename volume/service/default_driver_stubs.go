@@ -5,6 +5,15 @@ package service // import "github.com/docker/docker/volume/service"
 import (
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/volume/drivers"
+	"github.com/pkg/errors"
 )
 
 func setupDefaultDriver(_ *drivers.Store, _ string, _ idtools.Identity) error { return nil }
+
+// RegisterKeyHelpers is not supported, as there is no default driver.
+func (s *VolumesService) RegisterKeyHelpers(helpers map[string]string) error {
+	if len(helpers) == 0 {
+		return nil
+	}
+	return errors.New("key providers are not supported on this platform")
+}
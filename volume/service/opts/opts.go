@@ -87,3 +87,27 @@ func WithPurgeOnError(b bool) RemoveOption {
 		o.PurgeOnError = b
 	}
 }
+
+// MountConfig is used by `MountOption` to store config options for mount
+type MountConfig struct {
+	ReadOnly bool
+	Subpath  string
+}
+
+// MountOption is used to pass options to the volumes service `Mount` implementation
+type MountOption func(*MountConfig)
+
+// WithMountReadOnly indicates to `Mount` that the volume is mounted read-only.
+// The option is passed to volume drivers accepting per-mount options.
+func WithMountReadOnly(cfg *MountConfig) {
+	cfg.ReadOnly = true
+}
+
+// WithMountSubpath indicates to `Mount` that only the given path inside the
+// volume is used. The option is passed to volume drivers accepting per-mount
+// options; the returned path is still the root of the volume.
+func WithMountSubpath(subpath string) MountOption {
+	return func(cfg *MountConfig) {
+		cfg.Subpath = subpath
+	}
+}
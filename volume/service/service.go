@@ -2,12 +2,13 @@ package service // import "github.com/docker/docker/volume/service"
 
 import (
 	"context"
+	"strconv"
 	"sync/atomic"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/errdefs"
-	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/plugingetter"
 	"github.com/docker/docker/pkg/stringid"
@@ -82,6 +83,7 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 		return nil, err
 	}
 	vol := volumeToAPIType(v)
+	s.setUsageTimes(&vol, v)
 
 	var cfg opts.GetConfig
 	for _, o := range getOpts {
@@ -91,6 +93,7 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 	if cfg.ResolveStatus {
 		vol.Status = v.Status()
 	}
+	s.setDriverCapabilities(ctx, &vol, v)
 	return &vol, nil
 }
 
@@ -103,7 +106,7 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 // s.Mount(ctx, vol, mountID)
 // s.Unmount(ctx, vol, mountID)
 // ```
-func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref string) (string, error) {
+func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref string, mountOpts ...opts.MountOption) (string, error) {
 	v, err := s.vs.Get(ctx, vol.Name, opts.WithGetDriver(vol.Driver))
 	if err != nil {
 		if IsNotExist(err) {
@@ -111,7 +114,24 @@ func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref strin
 		}
 		return "", err
 	}
-	return v.Mount(ref)
+	var cfg opts.MountConfig
+	for _, o := range mountOpts {
+		o(&cfg)
+	}
+
+	var path string
+	if om, ok := unwrapVolume(v).(volume.OptionsMounter); ok {
+		path, err = om.MountWithOptions(ref, volume.MountOptions{ReadOnly: cfg.ReadOnly, Subpath: cfg.Subpath})
+	} else {
+		path, err = v.Mount(ref)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := s.vs.setMounted(v.Name(), true, time.Now()); err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warn("Error recording volume mount time")
+	}
+	return path, nil
 }
 
 // Unmount unmounts the volume.
@@ -128,7 +148,13 @@ func (s *VolumesService) Unmount(ctx context.Context, vol *types.Volume, ref str
 		}
 		return err
 	}
-	return v.Unmount(ref)
+	if err := v.Unmount(ref); err != nil {
+		return err
+	}
+	if err := s.vs.setMounted(v.Name(), false, time.Now()); err != nil {
+		logrus.WithError(err).WithField("volume", v.Name()).Warn("Error recording volume unmount time")
+	}
+	return nil
 }
 
 // Release releases a volume reference
@@ -167,9 +193,122 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	return err
 }
 
+// Snapshot creates a snapshot with the given name of the volume.
+func (s *VolumesService) Snapshot(ctx context.Context, name, snapshot string) (*types.VolumeSnapshot, error) {
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	sn, err := s.vs.Snapshot(ctx, v, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "snapshot_create", map[string]string{"driver": v.DriverName(), "snapshot": sn.Name})
+	apiS := snapshotToAPIType(sn)
+	return &apiS, nil
+}
+
+// ListSnapshots lists the snapshots of the volume.
+func (s *VolumesService) ListSnapshots(ctx context.Context, name string) ([]*types.VolumeSnapshot, error) {
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	ls, err := s.vs.ListSnapshots(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*types.VolumeSnapshot, 0, len(ls))
+	for _, sn := range ls {
+		apiS := snapshotToAPIType(sn)
+		out = append(out, &apiS)
+	}
+	return out, nil
+}
+
+// RemoveSnapshot removes the snapshot with the given name of the volume.
+func (s *VolumesService) RemoveSnapshot(ctx context.Context, name, snapshot string) error {
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := s.vs.RemoveSnapshot(ctx, v, snapshot); err != nil {
+		return err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "snapshot_remove", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+	return nil
+}
+
+// Clone creates a new volume with the given target name, holding a copy of the
+// data of the volume, or of its snapshot if snapshot is not empty.
+// The new volume is created by the driver of the volume it is cloned from,
+// which may clone the data more efficiently than copying it.
+func (s *VolumesService) Clone(ctx context.Context, name, snapshot, target string, opts ...opts.CreateOption) (*types.Volume, error) {
+	if target == "" {
+		target = stringid.GenerateRandomID()
+	}
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := s.vs.Clone(ctx, v, snapshot, target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]string{"driver": clone.DriverName(), "source": v.Name()}
+	if snapshot != "" {
+		attributes["snapshot"] = snapshot
+	}
+	s.eventLogger.LogVolumeEvent(clone.Name(), "create", attributes)
+	apiV := volumeToAPIType(clone)
+	return &apiV, nil
+}
+
+// Restore replaces the data of the volume with the data of the snapshot with
+// the given name.
+// An error is returned if the volume is referenced.
+func (s *VolumesService) Restore(ctx context.Context, name, snapshot string) error {
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	err = s.vs.Restore(ctx, v, snapshot)
+	if IsInUse(err) {
+		err = errdefs.Conflict(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "restore", map[string]string{"driver": v.DriverName(), "snapshot": snapshot})
+	return nil
+}
+
+// Resize changes the size of the volume to the given size in bytes.
+func (s *VolumesService) Resize(ctx context.Context, name string, size int64) error {
+	if size <= 0 {
+		return errdefs.InvalidParameter(errors.New("volume size must be greater than 0"))
+	}
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := s.vs.Resize(ctx, v, size); err != nil {
+		return err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "resize", map[string]string{"driver": v.DriverName(), "size": strconv.FormatInt(size, 10)})
+	return nil
+}
+
 var acceptedPruneFilters = map[string]bool{
-	"label":  true,
-	"label!": true,
+	"label":      true,
+	"label!":     true,
+	"until":      true,
+	"unused-for": true,
 }
 
 var acceptedListFilters = map[string]bool{
@@ -179,15 +318,37 @@ var acceptedListFilters = map[string]bool{
 	"label":    true,
 }
 
+// localDataOpts are the options of the local driver that keep the data of the
+// volume on the host.
+var localDataOpts = map[string]struct{}{
+	"size":        {},
+	"encryption":  {},
+	"key":         {},
+	"keyprovider": {},
+}
+
+// hasLocalData returns whether the data of the volume is stored locally, that
+// is, if the volume has no mount options. Typically volumes with mount options
+// are not really local even if they are using the local driver.
+func hasLocalData(v volume.Volume) bool {
+	dv, ok := v.(volume.DetailedVolume)
+	if !ok {
+		return false
+	}
+	for opt := range dv.Options() {
+		if _, ok := localDataOpts[opt]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // LocalVolumesSize gets all local volumes and fetches their size on disk
 // Note that this intentionally skips volumes which have mount options. Typically
 // volumes with mount options are not really local even if they are using the
 // local driver.
 func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume, error) {
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(hasLocalData)))
 	if err != nil {
 		return nil, err
 	}
@@ -207,10 +368,11 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 	if err != nil {
 		return nil, err
 	}
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
+	usageBy, err := s.usageFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, usageBy, CustomFilter(hasLocalData)))
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +389,7 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 		default:
 		}
 
-		vSize, err := directory.Size(ctx, v.Path())
+		vSize, _, err := volumeUsage(ctx, v)
 		if err != nil {
 			logrus.WithField("volume", v.Name()).WithError(err).Warn("could not determine size of volume")
 		}
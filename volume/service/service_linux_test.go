@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
@@ -64,3 +66,131 @@ func TestLocalVolumeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceSnapshotCloneRestore(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("fake"), "fake"))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	v, err := service.Create(ctx, "test1", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	dataFile := filepath.Join(v.Mountpoint, "data")
+	assert.NilError(t, ioutil.WriteFile(dataFile, []byte("snapshotted"), 0644))
+
+	s, err := service.Snapshot(ctx, "test1", "snap1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(s.Name, "snap1"))
+
+	ls, err := service.ListSnapshots(ctx, "test1")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, is.Equal(ls[0].Name, "snap1"))
+
+	assert.NilError(t, ioutil.WriteFile(dataFile, []byte("changed"), 0644))
+
+	clone, err := service.Clone(ctx, "test1", "snap1", "test1-clone", opts.WithCreateLabels(map[string]string{"cloned": "true"}))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(clone.Driver, volume.DefaultDriverName))
+	assert.Check(t, is.DeepEqual(clone.Labels, map[string]string{"cloned": "true"}))
+	clone, err = service.Get(ctx, "test1-clone")
+	assert.NilError(t, err)
+	b, err := ioutil.ReadFile(filepath.Join(clone.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "snapshotted"))
+
+	_, err = service.Clone(ctx, "test1", "", "test1-clone")
+	assert.Assert(t, errdefs.IsConflict(err), err)
+
+	_, err = service.Get(ctx, "test1", opts.WithGetReference("container"))
+	assert.NilError(t, err)
+	err = service.Restore(ctx, "test1", "snap1")
+	assert.Assert(t, errdefs.IsConflict(err), err)
+	assert.NilError(t, service.Release(ctx, "test1", "container"))
+
+	assert.NilError(t, service.Restore(ctx, "test1", "snap1"))
+	b, err = ioutil.ReadFile(dataFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "snapshotted"))
+
+	assert.NilError(t, service.RemoveSnapshot(ctx, "test1", "snap1"))
+	err = service.RemoveSnapshot(ctx, "test1", "snap1")
+	assert.Assert(t, errdefs.IsNotFound(err), err)
+
+	_, err = service.Create(ctx, "test2", "fake")
+	assert.NilError(t, err)
+	_, err = service.Snapshot(ctx, "test2", "snap1")
+	assert.Assert(t, errdefs.IsNotImplemented(err), err)
+}
+
+func TestServiceDriverCapabilitiesResize(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("fake"), "fake"))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err = service.Create(ctx, "test1", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	v, err := service.Get(ctx, "test1")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(v.DriverCapabilities, &types.VolumeDriverCapabilities{
+		ProtocolVersion: volume.DriverProtocolVersion2,
+		Snapshot:        true,
+		Usage:           true,
+	}))
+	// the disk usage of local volumes is only calculated by `system df`
+	assert.Check(t, is.Nil(v.UsageData))
+
+	err = service.Resize(ctx, "test1", 0)
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	err = service.Resize(ctx, "test1", 1024)
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+
+	_, err = service.Create(ctx, "test2", "fake")
+	assert.NilError(t, err)
+	v, err = service.Get(ctx, "test2")
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(v.DriverCapabilities))
+}
+
+func TestRegisterKeyHelpers(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	assert.NilError(t, service.RegisterKeyHelpers(map[string]string{"vault": "/usr/local/bin/vault-key-helper"}))
+	err = service.RegisterKeyHelpers(map[string]string{local.DefaultKeyProvider: "/usr/local/bin/file-key-helper"})
+	assert.Check(t, is.ErrorContains(err, `key provider "file" cannot be replaced`))
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/errdefs"
@@ -233,6 +234,56 @@ func TestServicePrune(t *testing.T) {
 	assert.Assert(t, is.Equal(pr.VolumesDeleted[0], "test"))
 }
 
+func TestServicePruneUsage(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, name := range []string{"old", "old-used", "new"} {
+		_, err := service.Create(ctx, name, volume.DefaultDriverName)
+		assert.NilError(t, err)
+	}
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	for _, name := range []string{"old", "old-used"} {
+		assert.NilError(t, service.vs.updateMeta(name, func(meta *volumeMetadata) {
+			meta.CreatedAt = weekAgo
+		}))
+	}
+
+	v, err := service.Get(ctx, "old-used")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v.LastMountedAt, ""))
+	_, err = service.Mount(ctx, v, t.Name())
+	assert.NilError(t, err)
+	assert.NilError(t, service.Unmount(ctx, v, t.Name()))
+
+	v, err = service.Get(ctx, "old-used")
+	assert.NilError(t, err)
+	assert.Check(t, v.LastMountedAt != "")
+	assert.Check(t, v.LastUnmountedAt != "")
+
+	_, err = service.Prune(ctx, filters.NewArgs(filters.Arg("unused-for", "foo")))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	_, err = service.Prune(ctx, filters.NewArgs(filters.Arg("until", "foo")))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	pr, err := service.Prune(ctx, filters.NewArgs(filters.Arg("unused-for", "24h")))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pr.VolumesDeleted, []string{"old"}))
+
+	pr, err = service.Prune(ctx, filters.NewArgs(filters.Arg("until", "24h")))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pr.VolumesDeleted, []string{"old-used"}))
+
+	_, err = service.Get(ctx, "new")
+	assert.NilError(t, err)
+}
+
 func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, func()) {
 	t.Helper()
 
@@ -604,25 +604,53 @@ func (s *VolumeStore) create(ctx context.Context, name, driverName string, opts,
 		}
 	}
 
+	return s.register(name, v, vd, opts, labels)
+}
+
+// register stores the labels and options of a volume that was created by the
+// given driver, and returns the volume wrapped with them.
+// It is expected that callers of this function hold any necessary locks.
+func (s *VolumeStore) register(name string, v volume.Volume, vd volume.Driver, opts, labels map[string]string) (volume.Volume, error) {
 	s.globalLock.Lock()
 	s.labels[name] = labels
 	s.options[name] = opts
 	s.refs[name] = make(map[string]struct{})
 	s.globalLock.Unlock()
 
-	metadata := volumeMetadata{
-		Name:    name,
-		Driver:  vd.Name(),
-		Labels:  labels,
-		Options: opts,
-	}
-
-	if err := s.setMeta(name, metadata); err != nil {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		// keep the timestamps of volumes that are registered again, for
+		// example when a volume that only exists in the driver is created
+		var metadata volumeMetadata
+		if err := getMeta(tx, name, &metadata); err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+		metadata.Name = name
+		metadata.Driver = vd.Name()
+		metadata.Labels = labels
+		metadata.Options = opts
+		if metadata.CreatedAt.IsZero() {
+			metadata.CreatedAt = time.Now().UTC()
+		}
+		return setMeta(tx, name, metadata)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return volumeWrapper{v, labels, vd.Scope(), opts}, nil
 }
 
+// setMounted records that the volume with the given name was mounted or
+// unmounted at the given time.
+func (s *VolumeStore) setMounted(name string, mounted bool, t time.Time) error {
+	return s.updateMeta(normalizeVolumeName(name), func(meta *volumeMetadata) {
+		if mounted {
+			meta.LastMountedAt = t.UTC()
+		} else {
+			meta.LastUnmountedAt = t.UTC()
+		}
+	})
+}
+
 // Get looks if a volume with the given name exists and returns it if so
 func (s *VolumeStore) Get(ctx context.Context, name string, getOptions ...opts.GetOption) (volume.Volume, error) {
 	var cfg opts.GetConfig
@@ -806,6 +834,196 @@ func (s *VolumeStore) Remove(ctx context.Context, v volume.Volume, rmOpts ...opt
 	return err
 }
 
+// snapshotDriver returns the driver of the volume if it supports snapshots.
+func (s *VolumeStore) snapshotDriver(v volume.Volume) (volume.SnapshotDriver, error) {
+	vd, err := s.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := vd.(volume.SnapshotDriver)
+	if !ok {
+		return nil, errdefs.NotImplemented(errors.Errorf("volume driver %s does not support snapshots", vd.Name()))
+	}
+	return sd, nil
+}
+
+// Snapshot creates a snapshot with the given name of the volume.
+func (s *VolumeStore) Snapshot(ctx context.Context, v volume.Volume, name string) (volume.Snapshot, error) {
+	s.locks.Lock(v.Name())
+	defer s.locks.Unlock(v.Name())
+
+	select {
+	case <-ctx.Done():
+		return volume.Snapshot{}, ctx.Err()
+	default:
+	}
+
+	sd, err := s.snapshotDriver(v)
+	if err != nil {
+		return volume.Snapshot{}, &OpErr{Err: err, Name: v.Name(), Op: "snapshot"}
+	}
+	snapshot, err := sd.Snapshot(unwrapVolume(v), name)
+	if err != nil {
+		return volume.Snapshot{}, &OpErr{Err: err, Name: v.Name(), Op: "snapshot"}
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots lists the snapshots of the volume.
+func (s *VolumeStore) ListSnapshots(ctx context.Context, v volume.Volume) ([]volume.Snapshot, error) {
+	sd, err := s.snapshotDriver(v)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.Name(), Op: "list snapshots"}
+	}
+	snapshots, err := sd.ListSnapshots(unwrapVolume(v))
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.Name(), Op: "list snapshots"}
+	}
+	return snapshots, nil
+}
+
+// RemoveSnapshot removes the snapshot with the given name of the volume.
+func (s *VolumeStore) RemoveSnapshot(ctx context.Context, v volume.Volume, name string) error {
+	s.locks.Lock(v.Name())
+	defer s.locks.Unlock(v.Name())
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	sd, err := s.snapshotDriver(v)
+	if err != nil {
+		return &OpErr{Err: err, Name: v.Name(), Op: "remove snapshot"}
+	}
+	if err := sd.RemoveSnapshot(unwrapVolume(v), name); err != nil {
+		return &OpErr{Err: err, Name: v.Name(), Op: "remove snapshot"}
+	}
+	return nil
+}
+
+// Clone creates a new volume with the given name from the volume, or from its
+// snapshot if snapshot is not empty. The new volume uses the same driver as
+// the volume it is cloned from.
+func (s *VolumeStore) Clone(ctx context.Context, v volume.Volume, snapshot, name string, createOpts ...opts.CreateOption) (volume.Volume, error) {
+	var cfg opts.CreateConfig
+	for _, o := range createOpts {
+		o(&cfg)
+	}
+
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	clone, err := s.clone(ctx, v, snapshot, name, cfg.Options, cfg.Labels)
+	if err != nil {
+		if _, ok := err.(*OpErr); ok {
+			return nil, err
+		}
+		return nil, &OpErr{Err: err, Name: name, Op: "clone"}
+	}
+
+	s.setNamed(clone, cfg.Reference)
+	return clone, nil
+}
+
+// clone asks the driver of the volume to clone it to a new volume.
+// It is expected that callers of this function hold any necessary locks.
+func (s *VolumeStore) clone(ctx context.Context, v volume.Volume, snapshot, name string, opts, labels map[string]string) (volume.Volume, error) {
+	parser := volumemounts.NewParser(runtime.GOOS)
+	if err := parser.ValidateVolumeName(name); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.checkConflict(ctx, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.Wrapf(errNameConflict, "volume '%s' already exists", name)
+	}
+
+	if _, err := s.snapshotDriver(v); err != nil {
+		return nil, err
+	}
+
+	driverName := v.DriverName()
+	vd, err := s.drivers.CreateDriver(driverName)
+	if err != nil {
+		return nil, err
+	}
+	sd := vd.(volume.SnapshotDriver)
+
+	logrus.Debugf("Registering cloned volume reference: driver %q, name %q, source %q", vd.Name(), name, v.Name())
+	clone, err := sd.Clone(unwrapVolume(v), snapshot, name, opts)
+	if err != nil {
+		if _, err := s.drivers.ReleaseDriver(driverName); err != nil {
+			logrus.WithError(err).WithField("driver", driverName).Error("Error releasing reference to volume driver")
+		}
+		return nil, err
+	}
+	return s.register(name, clone, vd, opts, labels)
+}
+
+// Restore replaces the data of the volume with the data of the snapshot with
+// the given name. A volume is not restored if it has any refs.
+func (s *VolumeStore) Restore(ctx context.Context, v volume.Volume, snapshot string) error {
+	name := v.Name()
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if s.hasRef(name) {
+		return &OpErr{Err: errVolumeInUse, Name: name, Op: "restore", Refs: s.getRefs(name)}
+	}
+
+	sd, err := s.snapshotDriver(v)
+	if err != nil {
+		return &OpErr{Err: err, Name: name, Op: "restore"}
+	}
+	if err := sd.Restore(unwrapVolume(v), snapshot); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "restore"}
+	}
+	return nil
+}
+
+// Resize changes the size of the volume to the given size in bytes.
+func (s *VolumeStore) Resize(ctx context.Context, v volume.Volume, size int64) error {
+	s.locks.Lock(v.Name())
+	defer s.locks.Unlock(v.Name())
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	vd, err := s.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return &OpErr{Err: err, Name: v.Name(), Op: "resize"}
+	}
+	rd, ok := vd.(volume.ResizeDriver)
+	if !ok {
+		return &OpErr{Err: errdefs.NotImplemented(errors.Errorf("volume driver %s does not support resizing volumes", vd.Name())), Name: v.Name(), Op: "resize"}
+	}
+	if err := rd.Resize(unwrapVolume(v), size); err != nil {
+		return &OpErr{Err: err, Name: v.Name(), Op: "resize"}
+	}
+	return nil
+}
+
 // Release releases the specified reference to the volume
 func (s *VolumeStore) Release(ctx context.Context, name string, ref string) error {
 	s.locks.Lock(name)
@@ -15,6 +15,12 @@ const (
 	GlobalScope = "global"
 )
 
+// DriverProtocolVersion2 is the version of the volume driver protocol which
+// adds optional features to the original protocol. Drivers opt into these
+// features by returning this version, and the features they support, in their
+// capabilities.
+const DriverProtocolVersion2 = 2
+
 // Driver is for creating and removing volumes.
 type Driver interface {
 	// Name returns the name of the volume driver.
@@ -32,6 +38,48 @@ type Driver interface {
 	Scope() string
 }
 
+// SnapshotDriver is implemented by drivers that can snapshot, clone and
+// restore volumes. It is an optional capability of a Driver.
+type SnapshotDriver interface {
+	Driver
+	// Snapshot creates a point-in-time copy of the volume with the given name.
+	Snapshot(vol Volume, name string) (Snapshot, error)
+	// ListSnapshots lists the snapshots of the volume.
+	ListSnapshots(vol Volume) ([]Snapshot, error)
+	// RemoveSnapshot removes the snapshot with the given name of the volume.
+	RemoveSnapshot(vol Volume, name string) error
+	// Clone creates a new volume with the given name and options, holding a
+	// copy of the data of the volume, or of its snapshot if snapshot is not
+	// empty.
+	Clone(vol Volume, snapshot, name string, opts map[string]string) (Volume, error)
+	// Restore replaces the data of the volume with the data of the snapshot
+	// with the given name. The volume must not be in use.
+	Restore(vol Volume, snapshot string) error
+}
+
+// ResizeDriver is implemented by drivers that can change the size of volumes.
+// It is an optional capability of a Driver.
+type ResizeDriver interface {
+	Driver
+	// Resize changes the size of the volume to the given size in bytes.
+	Resize(vol Volume, size int64) error
+}
+
+// CapabilityDriver is implemented by drivers that report their capabilities.
+type CapabilityDriver interface {
+	Driver
+	// Capabilities returns the capabilities of the driver.
+	Capabilities() Capability
+}
+
+// Snapshot is a point-in-time copy of a volume.
+type Snapshot struct {
+	// Name is the name of the snapshot, which is unique per volume.
+	Name string
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt time.Time
+}
+
 // Capability defines a set of capabilities that a driver is able to handle.
 type Capability struct {
 	// Scope is the scope of the driver, `global` or `local`
@@ -39,6 +87,38 @@ type Capability struct {
 	// A `local` scope indicates that the driver only manages volumes resources local to the host
 	// Scope is declared by the driver
 	Scope string
+	// Version is the version of the volume driver protocol implemented by
+	// the driver. It is zero for drivers implementing the original
+	// protocol, in which case the optional features below are ignored.
+	Version int
+	// Snapshot indicates that the driver can snapshot, clone and restore
+	// volumes.
+	Snapshot bool
+	// Resize indicates that the driver can change the size of volumes.
+	Resize bool
+	// Usage indicates that the driver reports the disk usage of volumes.
+	Usage bool
+	// MountOptions indicates that the driver accepts per-mount options,
+	// see MountOptions.
+	MountOptions bool
+}
+
+// MountOptions are the options of a single mount of a volume.
+type MountOptions struct {
+	// ReadOnly is set if the volume is mounted read-only.
+	ReadOnly bool
+	// Subpath is the path inside the volume that is mounted, relative to
+	// the root of the volume. The volume is still mounted at the root of
+	// the volume, but the driver may only make the subpath available.
+	Subpath string
+}
+
+// OptionsMounter is implemented by volumes that accept per-mount options. It
+// is an optional capability of a Volume.
+type OptionsMounter interface {
+	// MountWithOptions mounts the volume like Mount, with the given
+	// options.
+	MountWithOptions(id string, opts MountOptions) (string, error)
 }
 
 // Volume is a place to store data. It is backed by a specific driver, and can be mounted.